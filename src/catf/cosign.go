@@ -0,0 +1,203 @@
+package catf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Co-signed CRYPTO form.
+//
+// A co-signed attestation carries several independent signatures over the same
+// signed scope (BEGIN line through end of CLAIMS). Instead of the single-signer
+// Issuer-Key/Signature/Signature-Alg fields, CRYPTO declares a signer count and
+// one indexed entry per signer:
+//
+//	CRYPTO
+//	Hash-Alg: sha256
+//	Signer-1-Issuer-Key: ed25519:...
+//	Signer-1-Signature: ...
+//	Signer-1-Signature-Alg: ed25519
+//	Signer-2-Issuer-Key: dilithium3:...
+//	Signer-2-Signature: ...
+//	Signer-2-Signature-Alg: dilithium3
+//	Signers: 2
+//
+// Hash-Alg is shared by all signers. Entries are numbered 1..Signers in strictly
+// ascending Issuer-Key order, so a given signer set has exactly one canonical form.
+const (
+	cryptoSignersKey = "Signers"
+	signerKeyPrefix  = "Signer-"
+)
+
+// SignatureEntry is one signer's entry in a CATF CRYPTO section.
+type SignatureEntry struct {
+	IssuerKey    string
+	SignatureAlg string
+	Signature    string
+}
+
+// SignerResult is the verification outcome for a single signer.
+//
+// Err is nil when the signer's signature verifies; otherwise it is a structured
+// *Error carrying the same CATF-CRYPTO-* RuleIDs used by Verify.
+type SignerResult struct {
+	IssuerKey string
+	Err       error
+}
+
+// IsCoSigned reports whether CRYPTO uses the co-signed (multi-signer) form.
+func (c *CATF) IsCoSigned() bool {
+	if c == nil {
+		return false
+	}
+	sec, ok := c.Sections["CRYPTO"]
+	if !ok {
+		return false
+	}
+	_, ok = sec.Pairs[cryptoSignersKey]
+	return ok
+}
+
+// SignatureEntries returns the signer entries declared in CRYPTO, in canonical order.
+//
+// Single-signer attestations yield exactly one entry. For co-signed attestations
+// the co-signed structure is validated first (CATF-CRYPTO-6xx).
+func (c *CATF) SignatureEntries() ([]SignatureEntry, error) {
+	if c == nil {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-001", "nil CATF")
+	}
+	if !c.IsCoSigned() {
+		return []SignatureEntry{{
+			IssuerKey:    c.IssuerKey(),
+			SignatureAlg: c.SignatureAlg(),
+			Signature:    c.Signature(),
+		}}, nil
+	}
+	return coSignedEntries(c.Sections["CRYPTO"].Pairs)
+}
+
+// VerifySigners verifies every signer entry independently and reports per-signer results.
+//
+// The returned error covers document-level failures only (non-canonical bytes,
+// missing Hash-Alg, malformed co-signed structure). Individual signature
+// failures are reported in SignerResult.Err so callers can tell which entry failed.
+//
+// Results are returned in canonical entry order.
+func (c *CATF) VerifySigners() ([]SignerResult, error) {
+	if c == nil {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-001", "nil CATF")
+	}
+	parsed, err := Parse(c.raw)
+	if err != nil {
+		return nil, err
+	}
+	c = parsed
+
+	if c.HashAlg() == "" {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-102", "missing Hash-Alg")
+	}
+	entries, err := c.SignatureEntries()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SignerResult, 0, len(entries))
+	for _, e := range entries {
+		r := SignerResult{IssuerKey: e.IssuerKey}
+		if e.SignatureAlg == "" {
+			r.Err = newError(KindCrypto, "CATF-CRYPTO-101", "missing Signature-Alg")
+		} else if e.IssuerKey == "" {
+			r.Err = newError(KindCrypto, "CATF-CRYPTO-103", "missing Issuer-Key")
+		} else {
+			r.Err = c.verifyEntry(e)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// CoSignedCrypto builds the canonical co-signed CRYPTO pairs for a Document.
+//
+// Entries are ordered by Issuer-Key and numbered from 1. At least two distinct
+// signers are required; a single signer must use the single-signer fields.
+//
+// Signatures are computed over the signed scope, which excludes CRYPTO; callers
+// may therefore render with placeholder signatures, sign SignedBytes(), and
+// render again with the real values.
+func CoSignedCrypto(hashAlg string, entries []SignatureEntry) (map[string]string, error) {
+	if hashAlg == "" {
+		return nil, newError(KindRender, "CATF-CRYPTO-102", "missing Hash-Alg")
+	}
+	if len(entries) < 2 {
+		return nil, newError(KindRender, "CATF-CRYPTO-601", "co-signed CRYPTO requires at least two signers")
+	}
+	sorted := append([]SignatureEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].IssuerKey < sorted[j].IssuerKey })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].IssuerKey == sorted[i].IssuerKey {
+			return nil, newError(KindRender, "CATF-CRYPTO-605", "duplicate co-signer Issuer-Key")
+		}
+	}
+
+	pairs := map[string]string{
+		"Hash-Alg":       hashAlg,
+		cryptoSignersKey: strconv.Itoa(len(sorted)),
+	}
+	for i, e := range sorted {
+		prefix := signerKeyPrefix + strconv.Itoa(i+1) + "-"
+		pairs[prefix+"Issuer-Key"] = e.IssuerKey
+		pairs[prefix+"Signature"] = e.Signature
+		pairs[prefix+"Signature-Alg"] = e.SignatureAlg
+	}
+	return pairs, nil
+}
+
+func coSignedEntries(pairs map[string]string) ([]SignatureEntry, error) {
+	countStr := pairs[cryptoSignersKey]
+	n, err := strconv.Atoi(countStr)
+	if err != nil || n < 2 || strconv.Itoa(n) != countStr {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-601", "invalid Signers count")
+	}
+	for _, k := range []string{"Issuer-Key", "Signature", "Signature-Alg"} {
+		if _, ok := pairs[k]; ok {
+			return nil, newError(KindCrypto, "CATF-CRYPTO-602", "co-signed CRYPTO must not include single-signer fields")
+		}
+	}
+
+	entries := make([]SignatureEntry, n)
+	seen := 0
+	for k, v := range pairs {
+		if !strings.HasPrefix(k, signerKeyPrefix) {
+			continue
+		}
+		idxStr, field, ok := strings.Cut(strings.TrimPrefix(k, signerKeyPrefix), "-")
+		if !ok {
+			return nil, newError(KindCrypto, "CATF-CRYPTO-604", "unexpected co-signer field")
+		}
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil || idx < 1 || idx > n || strconv.Itoa(idx) != idxStr {
+			return nil, newError(KindCrypto, "CATF-CRYPTO-604", "unexpected co-signer field")
+		}
+		e := &entries[idx-1]
+		switch field {
+		case "Issuer-Key":
+			e.IssuerKey = v
+		case "Signature":
+			e.Signature = v
+		case "Signature-Alg":
+			e.SignatureAlg = v
+		default:
+			return nil, newError(KindCrypto, "CATF-CRYPTO-604", "unexpected co-signer field")
+		}
+		seen++
+	}
+	if seen != 3*n {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-603", "incomplete co-signer entry")
+	}
+	for i := 1; i < n; i++ {
+		if !(entries[i-1].IssuerKey < entries[i].IssuerKey) {
+			return nil, newError(KindCrypto, "CATF-CRYPTO-605", "co-signer entries not in canonical Issuer-Key order")
+		}
+	}
+	return entries, nil
+}
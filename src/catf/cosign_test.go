@@ -0,0 +1,230 @@
+package catf
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"xdao.co/catf/keys"
+)
+
+type testSigner struct {
+	pub  ed25519.PublicKey
+	priv ed25519.PrivateKey
+}
+
+func coSignedDoc() Document {
+	return Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": "bafy-contract-1", "Description": "Three-party contract"},
+		Claims:  map[string]string{"Role": "party", "Type": "approval"},
+	}
+}
+
+func coSignedCATFBytes(t *testing.T, signers []testSigner) []byte {
+	t.Helper()
+	doc := coSignedDoc()
+
+	entries := make([]SignatureEntry, 0, len(signers))
+	for _, s := range signers {
+		entries = append(entries, SignatureEntry{IssuerKey: issuerKey(s.pub), SignatureAlg: "ed25519", Signature: "0"})
+	}
+	crypto, err := CoSignedCrypto("sha256", entries)
+	if err != nil {
+		t.Fatalf("CoSignedCrypto pre: %v", err)
+	}
+	doc.Crypto = crypto
+	pre, err := Render(doc)
+	if err != nil {
+		t.Fatalf("render pre: %v", err)
+	}
+	parsed, err := Parse(pre)
+	if err != nil {
+		t.Fatalf("parse pre: %v", err)
+	}
+
+	for i, s := range signers {
+		entries[i].Signature = keys.SignEd25519SHA256(parsed.SignedBytes(), s.priv)
+	}
+	crypto, err = CoSignedCrypto("sha256", entries)
+	if err != nil {
+		t.Fatalf("CoSignedCrypto: %v", err)
+	}
+	doc.Crypto = crypto
+	out, err := Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return out
+}
+
+func mustSigners(t *testing.T, seeds ...byte) []testSigner {
+	t.Helper()
+	out := make([]testSigner, 0, len(seeds))
+	for _, b := range seeds {
+		pub, priv := mustKeypair(t, b)
+		out = append(out, testSigner{pub: pub, priv: priv})
+	}
+	return out
+}
+
+func TestCoSigned_VerifyAllSigners(t *testing.T) {
+	signers := mustSigners(t, 0x31, 0x32, 0x33)
+	a, err := Parse(coSignedCATFBytes(t, signers))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !a.IsCoSigned() {
+		t.Fatalf("expected co-signed CATF")
+	}
+	if a.IssuerKey() != "" {
+		t.Fatalf("expected empty Issuer-Key for co-signed CATF, got %q", a.IssuerKey())
+	}
+	if err := a.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	results, err := a.VerifySigners()
+	if err != nil {
+		t.Fatalf("VerifySigners: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 signer results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("signer %d: unexpected error: %v", i+1, r.Err)
+		}
+		if i > 0 && !(results[i-1].IssuerKey < r.IssuerKey) {
+			t.Fatalf("signer results not in canonical order")
+		}
+	}
+}
+
+func TestCoSigned_PerSignerFailureIsReported(t *testing.T) {
+	signers := mustSigners(t, 0x31, 0x32)
+	good := coSignedCATFBytes(t, signers)
+
+	// Replace one signer's signature with a signature over different bytes.
+	a, err := Parse(good)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	entries, err := a.SignatureEntries()
+	if err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+	var badKey string
+	for i := range entries {
+		if entries[i].IssuerKey == issuerKey(signers[1].pub) {
+			entries[i].Signature = keys.SignEd25519SHA256([]byte("other"), signers[1].priv)
+			badKey = entries[i].IssuerKey
+		}
+	}
+	doc := coSignedDoc()
+	doc.Crypto, err = CoSignedCrypto("sha256", entries)
+	if err != nil {
+		t.Fatalf("CoSignedCrypto: %v", err)
+	}
+	out, err := Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	bad, err := Parse(out)
+	if err != nil {
+		t.Fatalf("parse bad: %v", err)
+	}
+
+	results, err := bad.VerifySigners()
+	if err != nil {
+		t.Fatalf("VerifySigners: %v", err)
+	}
+	for _, r := range results {
+		if r.IssuerKey == badKey {
+			if RuleID(r.Err) != "CATF-CRYPTO-401" {
+				t.Fatalf("expected CATF-CRYPTO-401 for tampered signer, got %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Fatalf("expected untouched signer to verify, got %v", r.Err)
+		}
+	}
+	if err := bad.Verify(); RuleID(err) != "CATF-CRYPTO-401" {
+		t.Fatalf("expected Verify to fail with CATF-CRYPTO-401, got %v", err)
+	}
+}
+
+func TestCoSignedCrypto_RejectsInvalidSignerSets(t *testing.T) {
+	signers := mustSigners(t, 0x31)
+	one := []SignatureEntry{{IssuerKey: issuerKey(signers[0].pub), SignatureAlg: "ed25519", Signature: "0"}}
+	if _, err := CoSignedCrypto("sha256", one); RuleID(err) != "CATF-CRYPTO-601" {
+		t.Fatalf("expected CATF-CRYPTO-601, got %v", err)
+	}
+	dup := append(one, one[0])
+	if _, err := CoSignedCrypto("sha256", dup); RuleID(err) != "CATF-CRYPTO-605" {
+		t.Fatalf("expected CATF-CRYPTO-605, got %v", err)
+	}
+}
+
+func TestCoSigned_StructureRules(t *testing.T) {
+	signers := mustSigners(t, 0x31, 0x32)
+	a, err := Parse(coSignedCATFBytes(t, signers))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	entries, err := a.SignatureEntries()
+	if err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+
+	render := func(t *testing.T, crypto map[string]string) *CATF {
+		t.Helper()
+		doc := coSignedDoc()
+		doc.Crypto = crypto
+		out, err := Render(doc)
+		if err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		c, err := Parse(out)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return c
+	}
+	base := func() map[string]string {
+		m, err := CoSignedCrypto("sha256", entries)
+		if err != nil {
+			t.Fatalf("CoSignedCrypto: %v", err)
+		}
+		return m
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(m map[string]string)
+		rule   string
+	}{
+		{"count", func(m map[string]string) { m["Signers"] = "1" }, "CATF-CRYPTO-601"},
+		{"count-noncanonical", func(m map[string]string) { m["Signers"] = "02" }, "CATF-CRYPTO-601"},
+		{"single-signer-field", func(m map[string]string) { m["Issuer-Key"] = entries[0].IssuerKey }, "CATF-CRYPTO-602"},
+		{"incomplete", func(m map[string]string) { delete(m, "Signer-2-Signature") }, "CATF-CRYPTO-603"},
+		{"unexpected-field", func(m map[string]string) { m["Signer-3-Signature"] = "x" }, "CATF-CRYPTO-604"},
+		{"order", func(m map[string]string) {
+			m["Signer-1-Issuer-Key"], m["Signer-2-Issuer-Key"] = m["Signer-2-Issuer-Key"], m["Signer-1-Issuer-Key"]
+			m["Signer-1-Signature"], m["Signer-2-Signature"] = m["Signer-2-Signature"], m["Signer-1-Signature"]
+		}, "CATF-CRYPTO-605"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := base()
+			tc.mutate(m)
+			c := render(t, m)
+			if _, err := c.VerifySigners(); RuleID(err) != tc.rule {
+				t.Fatalf("expected %s, got %v", tc.rule, err)
+			}
+			if err := c.Verify(); RuleID(err) != tc.rule {
+				t.Fatalf("Verify: expected %s, got %v", tc.rule, err)
+			}
+		})
+	}
+}
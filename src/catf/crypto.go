@@ -3,13 +3,14 @@ package catf
 import (
 	"crypto/ed25519"
 	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/base64"
+	"errors"
 	"io"
 	"strings"
 
 	"github.com/cloudflare/circl/sign/dilithium/mode3"
-	"golang.org/x/crypto/sha3"
+
+	"xdao.co/catf/keys"
 )
 
 func (c *CATF) SignatureAlg() string {
@@ -38,39 +39,39 @@ func (c *CATF) Signature() string {
 // - ed25519:<base64>
 // - dilithium3:<base64>
 func (c *CATF) IssuerPublicKeyBytes() ([]byte, error) {
-	issuer := c.IssuerKey()
+	return decodeIssuerKey(c.IssuerKey())
+}
+
+func (c *CATF) SignatureBytes() ([]byte, error) {
+	if c.Signature() == "" {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-104", "missing Signature")
+	}
+	return decodeSignature(c.SignatureAlg(), c.Signature())
+}
+
+func decodeIssuerKey(issuer string) ([]byte, error) {
 	if issuer == "" {
 		return nil, newError(KindCrypto, "CATF-CRYPTO-103", "missing Issuer-Key")
 	}
 
-	alg, enc, ok := strings.Cut(issuer, ":")
-	if !ok {
+	_, pub, err := keys.ParsePublicKey(issuer)
+	switch {
+	case err == nil:
+		return pub, nil
+	case errors.Is(err, keys.ErrKeyEncoding):
 		return nil, newError(KindCrypto, "CATF-CRYPTO-111", "invalid Issuer-Key encoding")
-	}
-	pub, err := decodeBase64(enc)
-	if err != nil {
+	case errors.Is(err, keys.ErrKeyBase64):
 		return nil, wrapError(KindCrypto, "CATF-CRYPTO-113", "invalid issuer key base64", err)
-	}
-
-	switch alg {
-	case "ed25519":
-		if len(pub) != ed25519.PublicKeySize {
-			return nil, newError(KindCrypto, "CATF-CRYPTO-114", "invalid ed25519 public key length")
-		}
-		return pub, nil
-	case "dilithium3":
-		var pk mode3.PublicKey
-		if err := pk.UnmarshalBinary(pub); err != nil {
-			return nil, wrapError(KindCrypto, "CATF-CRYPTO-115", "invalid dilithium3 public key", err)
-		}
-		return pub, nil
-	default:
+	case errors.Is(err, keys.ErrUnsupportedAlgorithm):
 		return nil, newError(KindCrypto, "CATF-CRYPTO-112", "unsupported issuer key encoding")
+	case strings.HasPrefix(issuer, "dilithium3:"):
+		return nil, wrapError(KindCrypto, "CATF-CRYPTO-115", "invalid dilithium3 public key", err)
+	default:
+		return nil, newError(KindCrypto, "CATF-CRYPTO-114", "invalid ed25519 public key length")
 	}
 }
 
-func (c *CATF) SignatureBytes() ([]byte, error) {
-	s := c.Signature()
+func decodeSignature(sigAlg, s string) ([]byte, error) {
 	if s == "" {
 		return nil, newError(KindCrypto, "CATF-CRYPTO-104", "missing Signature")
 	}
@@ -78,37 +79,25 @@ func (c *CATF) SignatureBytes() ([]byte, error) {
 	if err != nil {
 		return nil, wrapError(KindCrypto, "CATF-CRYPTO-131", "invalid signature base64", err)
 	}
-	if c.SignatureAlg() == "" {
+	if sigAlg == "" {
 		return nil, newError(KindCrypto, "CATF-CRYPTO-101", "missing Signature-Alg")
 	}
 	// Validate signature lengths where we can (some schemes have fixed sizes).
-	switch c.SignatureAlg() {
-	case "ed25519":
-		if len(sig) != ed25519.SignatureSize {
-			return nil, newError(KindCrypto, "CATF-CRYPTO-132", "invalid ed25519 signature length")
-		}
-	case "dilithium3":
-		if len(sig) != mode3.SignatureSize {
+	if err := keys.CheckSignatureLength(sigAlg, sig); errors.Is(err, keys.ErrSignatureLength) {
+		if sigAlg == "dilithium3" {
 			return nil, newError(KindCrypto, "CATF-CRYPTO-133", "invalid dilithium3 signature length")
 		}
+		return nil, newError(KindCrypto, "CATF-CRYPTO-132", "invalid ed25519 signature length")
 	}
 	return sig, nil
 }
 
 func digestFor(hashAlg string, message []byte) ([]byte, error) {
-	switch hashAlg {
-	case "sha256":
-		s := sha256.Sum256(message)
-		return s[:], nil
-	case "sha512":
-		s := sha512.Sum512(message)
-		return s[:], nil
-	case "sha3-256":
-		s := sha3.Sum256(message)
-		return s[:], nil
-	default:
+	digest, err := keys.Digest(hashAlg, message)
+	if err != nil {
 		return nil, newError(KindCrypto, "CATF-CRYPTO-201", "unsupported Hash-Alg")
 	}
+	return digest, nil
 }
 
 // Verify verifies the CATF signature according to v1 rules.
@@ -116,6 +105,9 @@ func digestFor(hashAlg string, message []byte) ([]byte, error) {
 // This library also supports:
 // - Hash-Alg: sha512, sha3-256
 // - Signature-Alg: dilithium3 (post-quantum)
+//
+// For co-signed attestations (CRYPTO declares Signers), every signer entry
+// must verify; use VerifySigners for per-signer results.
 func (c *CATF) Verify() error {
 	if c == nil {
 		return newError(KindCrypto, "CATF-CRYPTO-001", "nil CATF")
@@ -129,30 +121,52 @@ func (c *CATF) Verify() error {
 	// Use the parsed view for all cryptographic fields.
 	c = parsed
 
+	if c.IsCoSigned() {
+		results, err := c.VerifySigners()
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return r.Err
+			}
+		}
+		return nil
+	}
+
 	if c.SignatureAlg() == "" {
 		return newError(KindCrypto, "CATF-CRYPTO-101", "missing Signature-Alg")
 	}
 	if c.HashAlg() == "" {
 		return newError(KindCrypto, "CATF-CRYPTO-102", "missing Hash-Alg")
 	}
-
-	issuer := c.IssuerKey()
-	if issuer == "" {
+	if c.IssuerKey() == "" {
 		return newError(KindCrypto, "CATF-CRYPTO-103", "missing Issuer-Key")
 	}
-	issuerAlg, _, ok := strings.Cut(issuer, ":")
+
+	return c.verifyEntry(SignatureEntry{
+		IssuerKey:    c.IssuerKey(),
+		SignatureAlg: c.SignatureAlg(),
+		Signature:    c.Signature(),
+	})
+}
+
+// verifyEntry verifies one signer entry over the receiver's signed scope.
+// The receiver must hold canonical bytes (i.e. come from Parse).
+func (c *CATF) verifyEntry(e SignatureEntry) error {
+	issuerAlg, _, ok := strings.Cut(e.IssuerKey, ":")
 	if !ok {
 		return newError(KindCrypto, "CATF-CRYPTO-111", "invalid Issuer-Key encoding")
 	}
-	if issuerAlg != c.SignatureAlg() {
+	if issuerAlg != e.SignatureAlg {
 		return newError(KindCrypto, "CATF-CRYPTO-121", "Issuer-Key alg does not match Signature-Alg")
 	}
 
-	pub, err := c.IssuerPublicKeyBytes()
+	pub, err := decodeIssuerKey(e.IssuerKey)
 	if err != nil {
 		return err
 	}
-	sig, err := c.SignatureBytes()
+	sig, err := decodeSignature(e.SignatureAlg, e.Signature)
 	if err != nil {
 		return err
 	}
@@ -160,28 +174,19 @@ func (c *CATF) Verify() error {
 	if err != nil {
 		return err
 	}
-	digest, err := digestFor(c.HashAlg(), signedScope)
-	if err != nil {
-		return err
-	}
 
-	switch c.SignatureAlg() {
-	case "ed25519":
-		if !ed25519.Verify(ed25519.PublicKey(pub), digest, sig) {
-			return newError(KindCrypto, "CATF-CRYPTO-401", "signature invalid")
-		}
+	err = keys.VerifyMessage(e.SignatureAlg, c.HashAlg(), pub, signedScope, sig)
+	switch {
+	case err == nil:
 		return nil
-	case "dilithium3":
-		var pk mode3.PublicKey
-		if err := pk.UnmarshalBinary(pub); err != nil {
-			return wrapError(KindCrypto, "CATF-CRYPTO-115", "invalid dilithium3 public key", err)
-		}
-		if !mode3.Verify(&pk, digest, sig) {
-			return newError(KindCrypto, "CATF-CRYPTO-401", "signature invalid")
-		}
-		return nil
-	default:
+	case errors.Is(err, keys.ErrUnsupportedHash):
+		return newError(KindCrypto, "CATF-CRYPTO-201", "unsupported Hash-Alg")
+	case errors.Is(err, keys.ErrUnsupportedAlgorithm):
 		return newError(KindCrypto, "CATF-CRYPTO-301", "unsupported Signature-Alg")
+	case errors.Is(err, keys.ErrPublicKey):
+		return wrapError(KindCrypto, "CATF-CRYPTO-115", "invalid dilithium3 public key", err)
+	default:
+		return newError(KindCrypto, "CATF-CRYPTO-401", "signature invalid")
 	}
 }
 
@@ -129,3 +129,45 @@ func TestVerify_ErrorTaxonomy_MissingSignatureAlg(t *testing.T) {
 		t.Fatalf("expected RuleID CATF-CRYPTO-101, got %s", e.RuleID)
 	}
 }
+
+func TestValidateCoreClaims_TrustEventRuleIDs(t *testing.T) {
+	cases := []struct {
+		claims map[string]string
+		ruleID string
+	}{
+		{map[string]string{"Type": "policy-supersedes", "Successor-Policy": "bafy-p2"}, "CATF-VAL-281"},
+		{map[string]string{"Type": "policy-supersedes", "Prior-Policy": "bafy-p1"}, "CATF-VAL-282"},
+		{map[string]string{"Type": "policy-supersedes", "Prior-Policy": "bafy-p1", "Successor-Policy": "bafy-p1"}, "CATF-VAL-283"},
+		{map[string]string{"Type": "policy-supersedes", "Prior-Policy": "bafy-p1", "Successor-Policy": "bafy-p2"}, ""},
+		{map[string]string{"Type": "key-rotation", "Old-Key": "ed25519:AA==", "New-Key": "ed25519:AQ=="}, "CATF-VAL-264"},
+		{map[string]string{"Type": "key-rotation", "Old-Key": "ed25519:AA==", "New-Key": "ed25519:AQ==", "Effective-Date": "soon"}, "CATF-VAL-264"},
+		{map[string]string{"Type": "key-rotation", "Old-Key": "ed25519:AA==", "New-Key": "ed25519:AQ==", "Effective-Date": "2026-06-01"}, ""},
+	}
+	for _, tc := range cases {
+		doc := Document{
+			Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+			Subject: map[string]string{"CID": "bafy-p1", "Description": "policy"},
+			Claims:  tc.claims,
+			Crypto:  map[string]string{"Hash-Alg": "sha256", "Issuer-Key": "ed25519:AA==", "Signature": "AA==", "Signature-Alg": "ed25519"},
+		}
+		b, err := Render(doc)
+		if err != nil {
+			t.Fatalf("Render: %v", err)
+		}
+		parsed, err := Parse(b)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		verr := ValidateCoreClaims(parsed)
+		if tc.ruleID == "" {
+			if verr != nil {
+				t.Fatalf("unexpected error: %v", verr)
+			}
+			continue
+		}
+		var e *Error
+		if !errors.As(verr, &e) || e.RuleID != tc.ruleID {
+			t.Fatalf("expected %s, got %v", tc.ruleID, verr)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package catf
+
+import (
+	"encoding/base64"
+
+	"xdao.co/catf/keys"
+)
+
+// Sign renders doc with a single-signer CRYPTO section produced by signer.
+//
+// Any existing doc.Crypto is replaced. The returned bytes are canonical and have
+// been verified with Verify before being returned.
+func Sign(doc Document, hashAlg string, signer keys.Signer) ([]byte, error) {
+	if signer == nil {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-501", "missing signer")
+	}
+	return signDocument(doc, hashAlg, []keys.Signer{signer}, func(entries []SignatureEntry) (map[string]string, error) {
+		e := entries[0]
+		return map[string]string{
+			"Hash-Alg":      hashAlg,
+			"Issuer-Key":    e.IssuerKey,
+			"Signature":     e.Signature,
+			"Signature-Alg": e.SignatureAlg,
+		}, nil
+	})
+}
+
+// CoSign renders doc with a co-signed CRYPTO section, one entry per signer.
+//
+// At least two distinct signers are required. Any existing doc.Crypto is replaced.
+func CoSign(doc Document, hashAlg string, signers ...keys.Signer) ([]byte, error) {
+	for _, s := range signers {
+		if s == nil {
+			return nil, newError(KindCrypto, "CATF-CRYPTO-501", "missing signer")
+		}
+	}
+	return signDocument(doc, hashAlg, signers, func(entries []SignatureEntry) (map[string]string, error) {
+		return CoSignedCrypto(hashAlg, entries)
+	})
+}
+
+func signDocument(doc Document, hashAlg string, signers []keys.Signer, crypto func([]SignatureEntry) (map[string]string, error)) ([]byte, error) {
+	if hashAlg == "" {
+		return nil, newError(KindCrypto, "CATF-CRYPTO-102", "missing Hash-Alg")
+	}
+	entries := make([]SignatureEntry, len(signers))
+	for i, s := range signers {
+		entries[i] = SignatureEntry{
+			IssuerKey:    keys.IssuerKeyForSigner(s),
+			SignatureAlg: s.Algorithm(),
+			Signature:    "0",
+		}
+	}
+
+	// The signed scope excludes CRYPTO, so a placeholder rendering yields the
+	// exact bytes every signer must cover.
+	pairs, err := crypto(entries)
+	if err != nil {
+		return nil, err
+	}
+	doc.Crypto = pairs
+	pre, err := Render(doc)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := Parse(pre)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := digestFor(hashAlg, parsed.SignedBytes())
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range signers {
+		sig, err := s.Sign(digest)
+		if err != nil {
+			return nil, wrapError(KindCrypto, "CATF-CRYPTO-502", "signer failed", err)
+		}
+		entries[i].Signature = base64.StdEncoding.EncodeToString(sig)
+	}
+
+	pairs, err = crypto(entries)
+	if err != nil {
+		return nil, err
+	}
+	doc.Crypto = pairs
+	out, err := Render(doc)
+	if err != nil {
+		return nil, err
+	}
+	final, err := Parse(out)
+	if err != nil {
+		return nil, err
+	}
+	if err := final.Verify(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package catf
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"xdao.co/catf/keys"
+)
+
+func signTestDoc() Document {
+	return Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": "bafy-doc-sign-1", "Description": "Signer test"},
+		Claims:  map[string]string{"Role": "author", "Type": "authorship"},
+	}
+}
+
+func TestSign_Ed25519MatchesManualSigning(t *testing.T) {
+	_, priv := mustKeypair(t, 0xA1)
+	signer, err := keys.NewEd25519Signer(priv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	out, err := Sign(Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": "bafy-doc-1", "Description": "Scientific paper draft"},
+		Claims:  map[string]string{"Role": "author", "Type": "authorship"},
+	}, "sha256", signer)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if string(out) != string(validCATFBytes(t)) {
+		t.Fatalf("Sign output differs from manually signed CATF")
+	}
+}
+
+func TestSign_Dilithium3(t *testing.T) {
+	_, sk, err := keys.GenerateDilithium3Keypair(io.Reader(deterministicReader{}))
+	if err != nil {
+		t.Fatalf("GenerateDilithium3Keypair: %v", err)
+	}
+	signer, err := keys.NewDilithium3Signer(sk)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+	out, err := Sign(signTestDoc(), "sha3-256", signer)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	a, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if a.SignatureAlg() != "dilithium3" || a.IssuerKey() != keys.IssuerKeyForSigner(signer) {
+		t.Fatalf("unexpected CRYPTO: alg=%q issuer=%q", a.SignatureAlg(), a.IssuerKey())
+	}
+}
+
+func TestCoSign_ProducesVerifiableCoSignedCATF(t *testing.T) {
+	_, privA := mustKeypair(t, 0x31)
+	_, privB := mustKeypair(t, 0x32)
+	a, _ := keys.NewEd25519Signer(privA)
+	b, _ := keys.NewEd25519Signer(privB)
+
+	out, err := CoSign(signTestDoc(), "sha256", b, a)
+	if err != nil {
+		t.Fatalf("CoSign: %v", err)
+	}
+	parsed, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !parsed.IsCoSigned() {
+		t.Fatalf("expected co-signed CATF")
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+type failingSigner struct{ keys.Signer }
+
+func (failingSigner) Sign([]byte) ([]byte, error) { return nil, errors.New("device unavailable") }
+
+func TestSign_Errors(t *testing.T) {
+	if _, err := Sign(signTestDoc(), "sha256", nil); RuleID(err) != "CATF-CRYPTO-501" {
+		t.Fatalf("expected CATF-CRYPTO-501, got %v", err)
+	}
+
+	_, priv := mustKeypair(t, 0xA1)
+	signer, _ := keys.NewEd25519Signer(priv)
+	if _, err := Sign(signTestDoc(), "md5", signer); RuleID(err) != "CATF-CRYPTO-201" {
+		t.Fatalf("expected CATF-CRYPTO-201, got %v", err)
+	}
+	if _, err := Sign(signTestDoc(), "sha256", failingSigner{signer}); RuleID(err) != "CATF-CRYPTO-502" {
+		t.Fatalf("expected CATF-CRYPTO-502, got %v", err)
+	}
+	if _, err := CoSign(signTestDoc(), "sha256", signer); RuleID(err) != "CATF-CRYPTO-601" {
+		t.Fatalf("expected CATF-CRYPTO-601, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package catf
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 // ValidateCoreClaims enforces the v1 core required claims per attestation type.
@@ -39,6 +41,76 @@ func ValidateCoreClaims(a *CATF) error {
 		rules = []Rule{required("CATF-VAL-231", "Target-Attestation")}
 	case "name-binding":
 		rules = []Rule{required("CATF-VAL-241", "Name"), required("CATF-VAL-242", "Version"), required("CATF-VAL-243", "Points-To")}
+	case "delegation":
+		rules = []Rule{
+			required("CATF-VAL-251", "Delegator-Key"),
+			required("CATF-VAL-252", "Delegate-Key"),
+			required("CATF-VAL-253", "Roles"),
+			{ID: "CATF-VAL-254", Apply: func(_ *CATF) error {
+				if _, ok := SplitRoles(claims.Pairs["Roles"]); !ok {
+					return newError(KindValidation, "CATF-VAL-254", "invalid Roles: want comma-separated, unique role names")
+				}
+				return nil
+			}},
+			{ID: "CATF-VAL-255", Apply: func(a *CATF) error {
+				entries, err := a.SignatureEntries()
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					if e.IssuerKey == claims.Pairs["Delegator-Key"] {
+						return nil
+					}
+				}
+				return newError(KindValidation, "CATF-VAL-255", "Delegator-Key is not a signer of the attestation")
+			}},
+			{ID: "CATF-VAL-256", Apply: func(_ *CATF) error {
+				if claims.Pairs["Delegate-Key"] == claims.Pairs["Delegator-Key"] {
+					return newError(KindValidation, "CATF-VAL-256", "Delegate-Key must differ from Delegator-Key")
+				}
+				return nil
+			}},
+		}
+	case "key-rotation":
+		rules = []Rule{
+			required("CATF-VAL-261", "Old-Key"),
+			required("CATF-VAL-262", "New-Key"),
+			{ID: "CATF-VAL-263", Apply: func(_ *CATF) error {
+				if claims.Pairs["New-Key"] == claims.Pairs["Old-Key"] {
+					return newError(KindValidation, "CATF-VAL-263", "New-Key must differ from Old-Key")
+				}
+				return nil
+			}},
+			{ID: "CATF-VAL-264", Apply: func(_ *CATF) error {
+				// The rotation retires Old-Key from this instant, so it must be dated.
+				if !validClaimTime(claims.Pairs["Effective-Date"]) {
+					return newError(KindValidation, "CATF-VAL-264", "key-rotation requires Effective-Date: want RFC 3339 or YYYY-MM-DD")
+				}
+				return nil
+			}},
+		}
+	case "key-revocation":
+		rules = []Rule{
+			required("CATF-VAL-271", "Revoked-Key"),
+			{ID: "CATF-VAL-272", Apply: func(_ *CATF) error {
+				v, ok := claims.Pairs["Compromised-At"]
+				if !ok || validClaimTime(v) {
+					return nil
+				}
+				return newError(KindValidation, "CATF-VAL-272", "invalid Compromised-At: want RFC 3339 or YYYY-MM-DD")
+			}},
+		}
+	case "policy-supersedes":
+		rules = []Rule{
+			required("CATF-VAL-281", "Prior-Policy"),
+			required("CATF-VAL-282", "Successor-Policy"),
+			{ID: "CATF-VAL-283", Apply: func(_ *CATF) error {
+				if claims.Pairs["Successor-Policy"] == claims.Pairs["Prior-Policy"] {
+					return newError(KindValidation, "CATF-VAL-283", "Successor-Policy must differ from Prior-Policy")
+				}
+				return nil
+			}},
+		}
 	default:
 		// Unknown claim types are permitted; this function only validates v1 core.
 		return nil
@@ -52,3 +124,31 @@ func ValidateCoreClaims(a *CATF) error {
 	}
 	return ValidateRules(a, rules)
 }
+
+// SplitRoles parses a delegation Roles claim: a comma-separated list of
+// unique role names with no surrounding whitespace (e.g. "approver,clerk").
+// ok is false when the value is empty or malformed.
+func SplitRoles(v string) (roles []string, ok bool) {
+	if v == "" {
+		return nil, false
+	}
+	seen := make(map[string]bool)
+	for _, r := range strings.Split(v, ",") {
+		if r == "" || strings.ContainsAny(r, " \t") || seen[r] {
+			return nil, false
+		}
+		seen[r] = true
+		roles = append(roles, r)
+	}
+	return roles, true
+}
+
+// validClaimTime reports whether v is an RFC 3339 timestamp or a YYYY-MM-DD
+// date.
+func validClaimTime(v string) bool {
+	if _, err := time.Parse("2006-01-02", v); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, v)
+	return err == nil
+}
@@ -1,6 +1,9 @@
 package cidutil
 
 import (
+	"crypto/sha256"
+	"io"
+
 	"github.com/ipfs/go-cid"
 	"github.com/multiformats/go-multihash"
 )
@@ -25,3 +28,17 @@ func CIDv1RawSHA256CID(data []byte) (cid.Cid, error) {
 	}
 	return cid.NewCidV1(cid.Raw, sum), nil
 }
+
+// CIDv1RawSHA256Reader returns the same CID as CIDv1RawSHA256CID for the bytes
+// read from r, without holding them in memory.
+func CIDv1RawSHA256Reader(r io.Reader) (cid.Cid, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return cid.Undef, err
+	}
+	mh, err := multihash.Encode(h.Sum(nil), multihash.SHA2_256)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return cid.NewCidV1(cid.Raw, mh), nil
+}
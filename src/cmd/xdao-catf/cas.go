@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"xdao.co/catf/storage"
+	"xdao.co/catf/storage/casconfig"
+	"xdao.co/catf/storage/casregistry"
+
+	_ "xdao.co/catf/storage/grpccas"
+)
+
+// casFlags are the CAS selection flags shared by commands that hydrate inputs
+// by CID.
+type casFlags struct {
+	backend      string
+	listBackends bool
+	casConfig    string
+}
+
+func (c *casFlags) add(fs *flag.FlagSet) {
+	fs.StringVar(&c.backend, "backend", "grpc", "CAS backend name")
+	fs.BoolVar(&c.listBackends, "list-backends", false, "List supported backends and exit")
+	fs.StringVar(&c.casConfig, "cas-config", "", "Path to CAS JSON config (optional; uses casregistry OpenWithConfig)")
+	casregistry.RegisterFlags(fs, casregistry.UsageCLI)
+}
+
+func (c *casFlags) openCAS() (storage.CAS, func() error, error) {
+	if c.casConfig != "" {
+		cfg, err := casconfig.LoadFile(c.casConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cfg.Open(casregistry.UsageCLI, c.backend)
+	}
+	return casregistry.Open(c.backend, casregistry.UsageCLI)
+}
+
+func printBackends(w io.Writer) {
+	for _, b := range casregistry.List(casregistry.UsageCLI) {
+		if b.Description == "" {
+			_, _ = fmt.Fprintf(w, "%s\n", b.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", b.Name, b.Description)
+	}
+}
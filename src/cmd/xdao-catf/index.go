@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"xdao.co/catf/index"
+)
+
+func cmdIndex(args []string, out io.Writer, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf index <subcommand> ...")
+		fmt.Fprintln(errOut, "subcommands: add, query")
+		return 2
+	}
+	switch args[0] {
+	case "add":
+		return cmdIndexAdd(args[1:], out, errOut)
+	case "query":
+		return cmdIndexQuery(args[1:], out, errOut)
+	default:
+		fmt.Fprintf(errOut, "unknown index subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+func cmdIndexAdd(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("index add", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var cas casFlags
+	fs.StringVar(&dir, "dir", "", "Index directory")
+	cas.add(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if cas.listBackends {
+		printBackends(out)
+		return 0
+	}
+	if dir == "" {
+		fmt.Fprintln(errOut, "missing --dir")
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf index add --dir <dir> [CAS flags] <a1.catf> [...]")
+		return 2
+	}
+
+	store, closeFn, err := cas.openCAS()
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	if closeFn != nil {
+		defer closeFn()
+	}
+	idx, err := index.Open(dir, store)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+
+	for _, p := range fs.Args() {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			fmt.Fprintf(errOut, "read att %s: %v\n", p, err)
+			return 1
+		}
+		e, err := idx.Add(context.Background(), b)
+		if err != nil {
+			fmt.Fprintf(errOut, "index add %s: %v\n", p, err)
+			return 1
+		}
+		_, _ = fmt.Fprintln(out, e.CID)
+	}
+	return 0
+}
+
+func cmdIndexQuery(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("index query", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var q index.Query
+	var asJSON bool
+	fs.StringVar(&dir, "dir", "", "Index directory")
+	fs.StringVar(&q.Subject, "subject", "", "Match Subject CID")
+	fs.StringVar(&q.Issuer, "issuer", "", "Match Issuer-Key (any signer)")
+	fs.StringVar(&q.Type, "type", "", "Match claim Type")
+	fs.StringVar(&q.Name, "name", "", "Match Name claim")
+	fs.StringVar(&q.Reference, "references", "", "Match attestations whose CLAIMS reference this CID or key (Points-To, Supersedes, Target-Attestation, Old-Key, New-Key, Revoked-Key, Delegator-Key, Delegate-Key)")
+	fs.BoolVar(&asJSON, "json", false, "Print entries as a JSON array")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if dir == "" {
+		fmt.Fprintln(errOut, "missing --dir")
+		return 2
+	}
+	if _, err := os.Stat(dir); err != nil {
+		fmt.Fprintf(errOut, "index query: %v\n", err)
+		return 1
+	}
+	idx, err := index.Open(dir, nil)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+
+	entries := idx.Query(q)
+	if asJSON {
+		if entries == nil {
+			entries = []index.Entry{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(errOut, "index query: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", e.CID, e.Type, e.Subject, strings.Join(e.Issuers, ","))
+	}
+	return 0
+}
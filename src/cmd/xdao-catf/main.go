@@ -14,12 +14,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ipfs/go-cid"
+
 	"xdao.co/catf/catf"
 	"xdao.co/catf/cidutil"
 	"xdao.co/catf/compliance"
 	"xdao.co/catf/crof"
 	"xdao.co/catf/keys"
 	"xdao.co/catf/resolver"
+	"xdao.co/catf/storage"
+	"xdao.co/catf/tpdl"
 )
 
 func main() {
@@ -39,12 +43,22 @@ func run(args []string, out io.Writer, errOut io.Writer) int {
 		return cmdCROF(args[1:], out, errOut)
 	case "doc-cid":
 		return cmdDocCID(args[1:], out, errOut)
+	case "index":
+		return cmdIndex(args[1:], out, errOut)
 	case "key":
 		return cmdKey(args[1:], out, errOut)
+	case "names":
+		return cmdNames(args[1:], out, errOut)
+	case "policy":
+		return cmdPolicy(args[1:], out, errOut)
 	case "resolve":
 		return cmdResolve(args[1:], out, errOut)
 	case "resolve-name":
 		return cmdResolveName(args[1:], out, errOut)
+	case "serve":
+		return cmdServe(args[1:], out, errOut)
+	case "tlog":
+		return cmdTlog(args[1:], out, errOut)
 	case "help", "-h", "--help":
 		printUsage(out)
 		return 0
@@ -61,27 +75,44 @@ func printUsage(w io.Writer) {
 	fmt.Fprintln(w, "Usage:")
 	fmt.Fprintln(w, "  xdao-catf crof cid <file>")
 	fmt.Fprintln(w, "  xdao-catf crof validate-supersession --new <file> --old <file>")
-	fmt.Fprintln(w, "  xdao-catf doc-cid <file>")
+	fmt.Fprintln(w, "  xdao-catf crof audit --crof <file> (--backend grpc --grpc-target <host:port> | --cas-config <file.json>) [--mode permissive|strict]")
+	fmt.Fprintln(w, "  xdao-catf doc-cid [--chunked] <file|->")
+	fmt.Fprintln(w, "  xdao-catf index add --dir <dir> [CAS flags] <a1.catf> [...]")
+	fmt.Fprintln(w, "  xdao-catf index query --dir <dir> [--subject <CID>] [--issuer <key>] [--type <t>] [--name <n>] [--references <CID>] [--json]")
 	fmt.Fprintln(w, "  xdao-catf key init --name <name> [--seed-hex <64hex>] [--force]")
 	fmt.Fprintln(w, "  xdao-catf key derive --from <name> --role <role> [--force]")
 	fmt.Fprintln(w, "  xdao-catf key list")
 	fmt.Fprintln(w, "  xdao-catf key export --name <name> [--role <role>]")
+	fmt.Fprintln(w, "  xdao-catf names list --policy <tpdl.txt> --att <a1.catf> [--att ...] [--json]")
+	fmt.Fprintln(w, "  xdao-catf policy cid <file>")
+	fmt.Fprintln(w, "  xdao-catf policy diff --old <tpdl.txt> --new <tpdl.txt> (--att <a1.catf> [--att ...] | --index <dir> [CAS flags]) [--as-of <time>] [--json]")
+	fmt.Fprintln(w, "  xdao-catf policy effective --root <tpdl.txt> [--policy <tpdl.txt> ...] [--att <a1.catf> ...]")
+	fmt.Fprintln(w, "  xdao-catf policy fmt [--check] <file>")
+	fmt.Fprintln(w, "  xdao-catf policy lint <file>")
 	fmt.Fprintln(w, "  xdao-catf attest --subject <CID> --description <text> (--seed-hex <64hex> | --signer <name> [--signer-role <role>] | --key-file <path>) [--type <t>] [--role <r>] [--claim Key=Value ...]")
-	fmt.Fprintln(w, "  xdao-catf resolve --subject <CID> --policy <tpdl.txt> --att <a1.catf> [--att ...] [--supersedes-crof <CID>] [--mode permissive|strict]")
-	fmt.Fprintln(w, "  xdao-catf resolve-name --name <Name> [--version <v>] --policy <tpdl.txt> --att <a1.catf> [--att ...] [--supersedes-crof <CID>] [--mode permissive|strict]")
+	fmt.Fprintln(w, "  xdao-catf resolve --subject <CID> --policy <tpdl.txt> --att <a1.catf> [--att ...] [--supersedes-crof <CID>] [--as-of <time>] [--mode permissive|strict] [--explain]")
+	fmt.Fprintln(w, "  xdao-catf resolve-name --name <Name> [--version <v> | --select <latest|range>] (--policy <tpdl.txt> | --policy-cid <CID>) (--att <a1.catf> | --att-cid <CID>) [...] [--supersedes-crof <CID>] [--as-of <time>] [--mode permissive|strict] [CAS flags]")
+	fmt.Fprintln(w, "  xdao-catf serve [--addr <host:port>] [--grpc-addr <host:port>] [--signer <name> [--signer-role <role>]] [--cas-config <file.json>] [--max-body-bytes <n>] [--request-timeout <d>] [--resolver-id <id>]")
+	fmt.Fprintln(w, "  xdao-catf tlog append --dir <dir> [--catf <file> ...] [--crof <file> ...] [<CID> ...]")
+	fmt.Fprintln(w, "  xdao-catf tlog sth --dir <dir> --signer <name> [--signer-role <role>] [--signature-alg <alg>] [--hash-alg <alg>] [--timestamp <RFC3339>]")
+	fmt.Fprintln(w, "  xdao-catf tlog prove-inclusion --dir <dir> --cid <CID> [--size <n>]")
+	fmt.Fprintln(w, "  xdao-catf tlog prove-consistency --dir <dir> --old <n> [--new <m>]")
+	fmt.Fprintln(w, "  xdao-catf tlog verify-sth --sth <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
+	fmt.Fprintln(w, "  xdao-catf tlog verify-inclusion --sth <file> --proof <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
+	fmt.Fprintln(w, "  xdao-catf tlog verify-consistency --old-sth <file> --new-sth <file> --proof <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
 	fmt.Fprintln(w)
 	fmt.Fprintln(w, "Notes:")
 	fmt.Fprintln(w, "  - --seed-hex must be 32 bytes (64 hex chars) ed25519 seed")
 	fmt.Fprintln(w, "  - KMS-lite stores keys under ~/.xdao/keys/<name> (0600 private key files)")
 	fmt.Fprintln(w, "  - approval attestations require Effective-Date (provide --effective-date or --claim Effective-Date=...)")
 	fmt.Fprintln(w, "  - attest writes canonical CATF bytes to stdout (no trailing newline)")
-	fmt.Fprintln(w, "  - resolve/resolve-name print canonical CROF to stdout")
+	fmt.Fprintln(w, "  - resolve/resolve-name print canonical CROF to stdout (resolve-name uses the name-resolution profile)")
 }
 
 func cmdCROF(args []string, out io.Writer, errOut io.Writer) int {
 	if len(args) == 0 {
 		fmt.Fprintln(errOut, "usage: xdao-catf crof <subcommand> ...")
-		fmt.Fprintln(errOut, "subcommands: cid, validate-supersession")
+		fmt.Fprintln(errOut, "subcommands: cid, validate-supersession, audit")
 		return 2
 	}
 	switch args[0] {
@@ -137,12 +168,93 @@ func cmdCROF(args []string, out io.Writer, errOut io.Writer) int {
 		}
 		_, _ = fmt.Fprintln(out, "OK")
 		return 0
+	case "audit":
+		return cmdCROFAudit(args[1:], out, errOut)
 	default:
 		fmt.Fprintf(errOut, "unknown crof subcommand: %s\n", args[0])
 		return 2
 	}
 }
 
+func cmdCROFAudit(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("crof audit", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	var cas casFlags
+	cas.add(fs)
+	var crofPath string
+	var mode string
+	fs.StringVar(&crofPath, "crof", "", "CROF file to audit")
+	fs.StringVar(&mode, "mode", "permissive", "Compliance mode used by the original resolution: permissive or strict")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if cas.listBackends {
+		printBackends(out)
+		return 0
+	}
+	if crofPath == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf crof audit --crof <file> [CAS flags] [--mode permissive|strict]")
+		return 2
+	}
+	var opts crof.AuditOptions
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "permissive":
+		opts.Compliance = compliance.Permissive
+	case "strict":
+		opts.Compliance = compliance.Strict
+	default:
+		fmt.Fprintln(errOut, "invalid --mode (expected permissive or strict)")
+		return 2
+	}
+
+	b, err := os.ReadFile(crofPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "read crof: %v\n", err)
+		return 1
+	}
+	store, closeFn, err := cas.openCAS()
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	if closeFn != nil {
+		defer closeFn()
+	}
+
+	report, err := crof.Audit(b, store, opts)
+	if err != nil {
+		fmt.Fprintf(errOut, "audit: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(out, "CROF-CID: %s\n", report.CROFCID)
+	_, _ = fmt.Fprintf(out, "Replay-CID: %s\n", report.ReplayCID)
+	switch {
+	case !report.Signed:
+		_, _ = fmt.Fprintln(out, "Signature: unsigned")
+	case report.SignatureErr != nil:
+		_, _ = fmt.Fprintf(out, "Signature: invalid (%v)\n", report.SignatureErr)
+	default:
+		_, _ = fmt.Fprintln(out, "Signature: valid")
+	}
+	if report.Match {
+		_, _ = fmt.Fprintln(out, "Match: true")
+		if !report.Passed() {
+			return 1
+		}
+		return 0
+	}
+	_, _ = fmt.Fprintln(out, "Match: false")
+	for _, d := range report.Diffs {
+		for _, l := range d.Original {
+			_, _ = fmt.Fprintf(out, "- %s: %s\n", d.Section, l)
+		}
+		for _, l := range d.Replayed {
+			_, _ = fmt.Fprintf(out, "+ %s: %s\n", d.Section, l)
+		}
+	}
+	return 1
+}
+
 type stringList []string
 
 func (s *stringList) String() string { return strings.Join(*s, ",") }
@@ -223,8 +335,12 @@ func cmdAttest(args []string, out io.Writer, errOut io.Writer) int {
 		fmt.Fprintf(errOut, "invalid signer: %v\n", err)
 		return 2
 	}
-	priv := ed25519.NewKeyFromSeed(seed)
-	issuerKey := keys.GenerateIssuerKeyFromSeed(seed)
+	signer, err := keys.NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid signer: %v\n", err)
+		return 2
+	}
+	issuerKey := keys.IssuerKeyForSigner(signer)
 	if printIssuerKey {
 		fmt.Fprintf(errOut, "Issuer-Key: %s\n", issuerKey)
 	}
@@ -303,29 +419,10 @@ func cmdAttest(args []string, out io.Writer, errOut io.Writer) int {
 		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
 		Subject: map[string]string{"CID": subjectCID, "Description": description},
 		Claims:  claims,
-		Crypto: map[string]string{
-			"Hash-Alg":      "sha256",
-			"Issuer-Key":    issuerKey,
-			"Signature":     "0",
-			"Signature-Alg": "ed25519",
-		},
-	}
-
-	pre, err := catf.Render(doc)
-	if err != nil {
-		fmt.Fprintf(errOut, "render pre: %v\n", err)
-		return 1
 	}
-	parsed, err := catf.Parse(pre)
+	finalBytes, err := catf.Sign(doc, "sha256", signer)
 	if err != nil {
-		fmt.Fprintf(errOut, "parse pre: %v\n", err)
-		return 1
-	}
-
-	doc.Crypto["Signature"] = keys.SignEd25519SHA256(parsed.SignedBytes(), priv)
-	finalBytes, err := catf.Render(doc)
-	if err != nil {
-		fmt.Fprintf(errOut, "render final: %v\n", err)
+		fmt.Fprintf(errOut, "sign: %v\n", err)
 		return 1
 	}
 	finalAtt, err := catf.Parse(finalBytes)
@@ -333,10 +430,6 @@ func cmdAttest(args []string, out io.Writer, errOut io.Writer) int {
 		fmt.Fprintf(errOut, "parse final: %v\n", err)
 		return 1
 	}
-	if err := finalAtt.Verify(); err != nil {
-		fmt.Fprintf(errOut, "verify final: %v\n", err)
-		return 1
-	}
 	if err := catf.ValidateCoreClaims(finalAtt); err != nil {
 		fmt.Fprintf(errOut, "invalid core claims: %v\n", err)
 		return 2
@@ -556,20 +649,40 @@ func cmdKeyList(args []string, out io.Writer, errOut io.Writer) int {
 func cmdDocCID(args []string, out io.Writer, errOut io.Writer) int {
 	fs := flag.NewFlagSet("doc-cid", flag.ContinueOnError)
 	fs.SetOutput(errOut)
+	var chunked bool
+	fs.BoolVar(&chunked, "chunked", false, "Print the chunked blob root CID (same as cascli put --chunked)")
 	if err := fs.Parse(args); err != nil {
 		return 2
 	}
 	if fs.NArg() != 1 {
-		fmt.Fprintln(errOut, "usage: xdao-catf doc-cid <file>")
+		fmt.Fprintln(errOut, "usage: xdao-catf doc-cid [--chunked] <file|->")
 		return 2
 	}
 	path := fs.Arg(0)
-	b, err := os.ReadFile(path)
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(errOut, "read %s: %v\n", filepath.Base(path), err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Both modes hash the stream without holding the whole document in memory.
+	var id cid.Cid
+	var err error
+	if chunked {
+		id, err = storage.ChunkedCID(r)
+	} else {
+		id, err = cidutil.CIDv1RawSHA256Reader(r)
+	}
 	if err != nil {
 		fmt.Fprintf(errOut, "read %s: %v\n", filepath.Base(path), err)
 		return 1
 	}
-	_, _ = fmt.Fprintln(out, cidutil.CIDv1RawSHA256(b))
+	_, _ = fmt.Fprintln(out, id.String())
 	return 0
 }
 
@@ -583,7 +696,9 @@ func cmdResolve(args []string, out io.Writer, errOut io.Writer) int {
 	var resolverID string
 	var resolvedAt string
 	var supersedesCROF string
+	var asOf string
 	var mode string
+	var explain bool
 
 	fs.StringVar(&subjectCID, "subject", "", "Subject CID")
 	fs.StringVar(&policyPath, "policy", "", "TPDL policy file")
@@ -591,7 +706,9 @@ func cmdResolve(args []string, out io.Writer, errOut io.Writer) int {
 	fs.StringVar(&resolverID, "resolver-id", "xdao-resolver-reference", "Resolver-ID recorded in CROF")
 	fs.StringVar(&resolvedAt, "resolved-at", "", "Optional RFC3339 timestamp for CROF META Resolved-At (omit for deterministic output)")
 	fs.StringVar(&supersedesCROF, "supersedes-crof", "", "Optional CID of a prior CROF this CROF supersedes (emits META Supersedes-CROF-CID)")
+	fs.StringVar(&asOf, "as-of", "", "Optional RFC3339 time or YYYY-MM-DD to resolve as of (recorded in CROF INPUTS As-Of)")
 	fs.StringVar(&mode, "mode", "permissive", "Compliance mode: permissive or strict")
+	fs.BoolVar(&explain, "explain", false, "Print the resolver's decision trace to stderr (CROF output is unchanged)")
 
 	if err := fs.Parse(args); err != nil {
 		return 2
@@ -635,6 +752,15 @@ func cmdResolve(args []string, out io.Writer, errOut io.Writer) int {
 		fmt.Fprintln(errOut, "invalid --mode (expected permissive or strict)")
 		return 2
 	}
+	if asOf != "" {
+		t, perr := resolver.ParseAsOf(asOf)
+		if perr != nil {
+			fmt.Fprintf(errOut, "invalid --as-of: %v\n", perr)
+			return 2
+		}
+		opts.AsOf = t
+	}
+	opts.Trace = explain
 
 	attBytes := make([][]byte, 0, len(attPaths))
 	attCIDs := make([]string, 0, len(attPaths))
@@ -660,10 +786,18 @@ func cmdResolve(args []string, out io.Writer, errOut io.Writer) int {
 		fmt.Fprintf(errOut, "resolve: %v\n", err)
 		return 1
 	}
+	for _, step := range res.Trace {
+		fmt.Fprintln(errOut, step)
+	}
+	policyCID, err := crof.PolicyCIDWithCompliance(policyBytes, opts.Mode)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid policy: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
 
 	crofBytes, err := crof.RenderWithCompliance(
 		res,
-		crof.PolicyCID(policyBytes),
+		policyCID,
 		attCIDs,
 		crof.RenderOptions{ResolverID: resolverID, ResolvedAt: resolvedAtTime, SupersedesCROFCID: supersedesCROF},
 		opts.Mode,
@@ -682,37 +816,64 @@ func cmdResolveName(args []string, out io.Writer, errOut io.Writer) int {
 
 	var name string
 	var version string
+	var selectExpr string
 	var policyPath string
+	var policyCID string
 	var attPaths stringList
+	var attCIDs stringList
 	var resolverID string
 	var resolvedAt string
 	var supersedesCROF string
+	var asOf string
 	var mode string
+	var cas casFlags
 
 	fs.StringVar(&name, "name", "", "Symbolic name")
 	fs.StringVar(&version, "version", "", "Optional version")
+	fs.StringVar(&selectExpr, "select", "", "Optional semver selector: latest or a range such as ^1.2.0 (excludes --version)")
 	fs.StringVar(&policyPath, "policy", "", "TPDL policy file")
+	fs.StringVar(&policyCID, "policy-cid", "", "TPDL policy CID (hydrated from CAS)")
 	fs.Var(&attPaths, "att", "CATF attestation file (repeatable)")
+	fs.Var(&attCIDs, "att-cid", "CATF attestation CID hydrated from CAS (repeatable)")
 	fs.StringVar(&resolverID, "resolver-id", "xdao-resolver-reference", "Resolver-ID recorded in CROF")
 	fs.StringVar(&resolvedAt, "resolved-at", "", "Optional RFC3339 timestamp for CROF META Resolved-At (omit for deterministic output)")
 	fs.StringVar(&supersedesCROF, "supersedes-crof", "", "Optional CID of a prior CROF this CROF supersedes (emits META Supersedes-CROF-CID)")
+	fs.StringVar(&asOf, "as-of", "", "Optional RFC3339 time or YYYY-MM-DD to resolve as of (recorded in CROF INPUTS As-Of)")
 	fs.StringVar(&mode, "mode", "permissive", "Compliance mode: permissive or strict")
+	cas.add(fs)
 
 	if err := fs.Parse(args); err != nil {
 		return 2
 	}
+	if cas.listBackends {
+		printBackends(out)
+		return 0
+	}
 	if name == "" {
 		fmt.Fprintln(errOut, "missing --name")
 		return 2
 	}
-	if policyPath == "" {
-		fmt.Fprintln(errOut, "missing --policy")
+	if (policyPath == "") == (policyCID == "") {
+		fmt.Fprintln(errOut, "specify exactly one of --policy or --policy-cid")
 		return 2
 	}
-	if len(attPaths) == 0 {
-		fmt.Fprintln(errOut, "missing --att")
+	if len(attPaths) == 0 && len(attCIDs) == 0 {
+		fmt.Fprintln(errOut, "missing --att or --att-cid")
 		return 2
 	}
+	var selector resolver.VersionSelector
+	if selectExpr != "" {
+		if version != "" {
+			fmt.Fprintln(errOut, "specify at most one of --version or --select")
+			return 2
+		}
+		sel, err := resolver.ParseVersionSelector(selectExpr)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid --select: %v\n", err)
+			return 2
+		}
+		selector = sel
+	}
 
 	var resolvedAtTime time.Time
 	if resolvedAt != "" {
@@ -724,72 +885,91 @@ func cmdResolveName(args []string, out io.Writer, errOut io.Writer) int {
 		resolvedAtTime = t
 	}
 
-	policyBytes, err := os.ReadFile(policyPath)
-	if err != nil {
-		fmt.Fprintf(errOut, "read policy: %v\n", err)
-		return 1
-	}
-
-	var opts resolver.Options
+	var complianceMode compliance.ComplianceMode
 	switch strings.ToLower(strings.TrimSpace(mode)) {
 	case "", "permissive":
-		opts.Mode = compliance.Permissive
+		complianceMode = compliance.Permissive
 	case "strict":
-		opts.Mode = compliance.Strict
+		complianceMode = compliance.Strict
 	default:
 		fmt.Fprintln(errOut, "invalid --mode (expected permissive or strict)")
 		return 2
 	}
 
-	attBytes := make([][]byte, 0, len(attPaths))
-	attCIDs := make([]string, 0, len(attPaths))
+	var asOfTime time.Time
+	if asOf != "" {
+		t, perr := resolver.ParseAsOf(asOf)
+		if perr != nil {
+			fmt.Fprintf(errOut, "invalid --as-of: %v\n", perr)
+			return 2
+		}
+		asOfTime = t
+	}
+
+	req := resolver.ResolveNameRequestCAS{
+		Name:       name,
+		Version:    version,
+		Selector:   selector,
+		Compliance: complianceMode,
+		AsOf:       asOfTime,
+	}
+	if policyPath != "" {
+		b, err := os.ReadFile(policyPath)
+		if err != nil {
+			fmt.Fprintf(errOut, "read policy: %v\n", err)
+			return 1
+		}
+		req.Policy = resolver.BlobRef{Bytes: b}
+	} else {
+		id, err := cid.Decode(policyCID)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid --policy-cid: %v\n", err)
+			return 2
+		}
+		req.Policy = resolver.BlobRef{CID: id}
+	}
 	for _, p := range attPaths {
 		b, rerr := os.ReadFile(p)
 		if rerr != nil {
 			fmt.Fprintf(errOut, "read att %s: %v\n", p, rerr)
 			return 1
 		}
-		attBytes = append(attBytes, b)
-		if a, perr := catf.Parse(b); perr == nil {
-			if cid, err := a.CID(); err == nil {
-				attCIDs = append(attCIDs, cid)
-				continue
-			}
+		req.Attestations = append(req.Attestations, resolver.BlobRef{Bytes: b})
+	}
+	for _, s := range attCIDs {
+		id, err := cid.Decode(s)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid --att-cid %q: %v\n", s, err)
+			return 2
 		}
-		sum := sha256.Sum256(b)
-		attCIDs = append(attCIDs, "sha256:"+hex.EncodeToString(sum[:]))
+		req.Attestations = append(req.Attestations, resolver.BlobRef{CID: id})
+	}
+
+	// A CAS is only needed when some input is referenced by CID.
+	if policyCID != "" || len(attCIDs) > 0 {
+		store, closeFn, err := cas.openCAS()
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		if closeFn != nil {
+			defer closeFn()
+		}
+		req.CAS = store
 	}
 
-	nameRes, err := resolver.ResolveNameWithOptions(attBytes, policyBytes, name, version, opts)
+	resOut, err := resolver.ResolveNameWithCAS(req)
 	if err != nil {
 		fmt.Fprintf(errOut, "resolve-name: %v\n", err)
 		return 1
 	}
 
-	subject := "name:" + name
-	if version != "" {
-		subject += "@" + version
-	}
-	res := &resolver.Resolution{
-		SubjectCID: subject,
-		State:      nameRes.State,
-		Confidence: nameRes.Confidence,
-		Exclusions: nameRes.Exclusions,
-		Verdicts:   nameRes.Verdicts,
-	}
-	if len(nameRes.Bindings) > 0 {
-		res.Paths = append(res.Paths, resolver.Path{ID: "path-1", CIDs: append([]string(nil), nameRes.Bindings...)})
-	}
-	if len(nameRes.Forks) > 0 {
-		res.Forks = append(res.Forks, resolver.Fork{ID: "fork-1", ConflictingPath: []string{"path-1"}})
-	}
-
-	crofBytes, err := crof.RenderWithCompliance(
-		res,
-		crof.PolicyCID(policyBytes),
-		attCIDs,
+	crofBytes, err := crof.RenderNameWithCompliance(
+		resOut.Resolution,
+		resOut.TrustPolicyCID,
+		resOut.AttestationIDs,
 		crof.RenderOptions{ResolverID: resolverID, ResolvedAt: resolvedAtTime, SupersedesCROFCID: supersedesCROF},
-		opts.Mode,
+		complianceMode,
 	)
 	if err != nil {
 		fmt.Fprintf(errOut, "crof: %v\n", err)
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"xdao.co/catf/resolver"
+)
+
+func cmdNames(args []string, out io.Writer, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf names <subcommand> ...")
+		fmt.Fprintln(errOut, "subcommands: list")
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return cmdNamesList(args[1:], out, errOut)
+	default:
+		fmt.Fprintf(errOut, "unknown names subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+// nameListEntryJSON is the --json shape of one names list entry.
+type nameListEntryJSON struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	State      string   `json:"state"`
+	Confidence string   `json:"confidence"`
+	PointsTo   string   `json:"pointsTo,omitempty"`
+	Bindings   []string `json:"bindings"`
+}
+
+func cmdNamesList(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("names list", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var policyPath string
+	var attPaths stringList
+	var asJSON bool
+
+	fs.StringVar(&policyPath, "policy", "", "TPDL policy file")
+	fs.Var(&attPaths, "att", "CATF attestation file (repeatable)")
+	fs.BoolVar(&asJSON, "json", false, "Print entries as a JSON array")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if policyPath == "" {
+		fmt.Fprintln(errOut, "missing --policy")
+		return 2
+	}
+	if len(attPaths) == 0 {
+		fmt.Fprintln(errOut, "missing --att")
+		return 2
+	}
+
+	policyBytes, err := os.ReadFile(policyPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	var atts [][]byte
+	for _, p := range attPaths {
+		b, rerr := os.ReadFile(p)
+		if rerr != nil {
+			fmt.Fprintf(errOut, "read att %s: %v\n", p, rerr)
+			return 1
+		}
+		atts = append(atts, b)
+	}
+
+	listing, err := resolver.ListNames(atts, policyBytes)
+	if err != nil {
+		fmt.Fprintf(errOut, "names list: %v\n", err)
+		return 1
+	}
+
+	if asJSON {
+		entries := make([]nameListEntryJSON, 0, len(listing.Entries))
+		for _, e := range listing.Entries {
+			entries = append(entries, nameListEntryJSON{
+				Name:       e.Name,
+				Version:    e.Version,
+				State:      string(e.State),
+				Confidence: string(e.Confidence),
+				PointsTo:   e.PointsTo,
+				Bindings:   append([]string{}, e.Bindings...),
+			})
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(errOut, "names list: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, e := range listing.Entries {
+		pointsTo := e.PointsTo
+		if pointsTo == "" {
+			pointsTo = "-"
+		}
+		_, _ = fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", e.Name, e.Version, e.State, pointsTo)
+	}
+	return 0
+}
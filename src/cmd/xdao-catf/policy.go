@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/index"
+	"xdao.co/catf/resolver"
+	"xdao.co/catf/tpdl"
+)
+
+func cmdPolicy(args []string, out io.Writer, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf policy <subcommand> ...")
+		fmt.Fprintln(errOut, "subcommands: cid, diff, effective, fmt, lint")
+		return 2
+	}
+	switch args[0] {
+	case "cid":
+		return cmdPolicyCID(args[1:], out, errOut)
+	case "diff":
+		return cmdPolicyDiff(args[1:], out, errOut)
+	case "effective":
+		return cmdPolicyEffective(args[1:], out, errOut)
+	case "fmt":
+		return cmdPolicyFmt(args[1:], out, errOut)
+	case "lint":
+		return cmdPolicyLint(args[1:], out, errOut)
+	default:
+		fmt.Fprintf(errOut, "unknown policy subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+// cmdPolicyCID prints the CID of a canonical policy; non-canonical input is
+// rejected (run `policy fmt` first).
+func cmdPolicyCID(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("policy cid", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(errOut, "usage: xdao-catf policy cid <file>")
+		return 2
+	}
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	id, err := tpdl.CID(b)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid policy: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, id)
+	return 0
+}
+
+// cmdPolicyFmt prints the canonical form of a policy.
+func cmdPolicyFmt(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("policy fmt", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	var check bool
+	fs.BoolVar(&check, "check", false, "Exit 1 if the policy is not canonical instead of printing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(errOut, "usage: xdao-catf policy fmt [--check] <file>")
+		return 2
+	}
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	if check {
+		if _, err := tpdl.CanonicalizeTPDL(b); err != nil {
+			fmt.Fprintf(errOut, "%s: %s %v\n", fs.Arg(0), tpdl.RuleID(err), err)
+			return 1
+		}
+		return 0
+	}
+	p, err := tpdl.Parse(b)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid policy: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
+	canon, err := tpdl.Render(p)
+	if err != nil {
+		fmt.Fprintf(errOut, "render policy: %v\n", err)
+		return 1
+	}
+	_, _ = out.Write(canon)
+	return 0
+}
+
+// cmdPolicyLint prints one line per lint warning and exits 1 when there are
+// any, so it can gate policy changes in CI.
+func cmdPolicyLint(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("policy lint", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(errOut, "usage: xdao-catf policy lint <file>")
+		return 2
+	}
+	b, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	warnings, err := tpdl.Lint(b)
+	if err != nil {
+		fmt.Fprintf(errOut, "%s:%d: %s %v\n", fs.Arg(0), tpdl.Line(err), tpdl.RuleID(err), err)
+		return 1
+	}
+	for _, w := range warnings {
+		_, _ = fmt.Fprintf(out, "%s:%d: %s %s\n", fs.Arg(0), w.Line, w.ID, w.Message)
+	}
+	if len(warnings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// cmdPolicyEffective follows policy-supersedes attestations from a root policy
+// and prints the effective policy and the supersession chain.
+func cmdPolicyEffective(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("policy effective", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	var rootPath string
+	var policyPaths stringList
+	var attPaths stringList
+	fs.StringVar(&rootPath, "root", "", "Root TPDL policy file")
+	fs.Var(&policyPaths, "policy", "Successor TPDL policy file (repeatable)")
+	fs.Var(&attPaths, "att", "CATF attestation file (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if rootPath == "" {
+		fmt.Fprintln(errOut, "missing --root")
+		return 2
+	}
+
+	root, err := os.ReadFile(rootPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	policies := [][]byte{root}
+	for _, p := range policyPaths {
+		b, rerr := os.ReadFile(p)
+		if rerr != nil {
+			fmt.Fprintf(errOut, "read policy %s: %v\n", p, rerr)
+			return 1
+		}
+		policies = append(policies, b)
+	}
+	var attBytes [][]byte
+	for _, p := range attPaths {
+		b, rerr := os.ReadFile(p)
+		if rerr != nil {
+			fmt.Fprintf(errOut, "read att %s: %v\n", p, rerr)
+			return 1
+		}
+		attBytes = append(attBytes, b)
+	}
+
+	rootCID, err := tpdl.CID(root)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid policy: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
+	res, err := resolver.ResolveEffectivePolicy(rootCID, policies, attBytes)
+	if err != nil {
+		fmt.Fprintf(errOut, "policy effective: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(out, "State: %s\n", res.State)
+	if res.Reason != "" {
+		_, _ = fmt.Fprintf(out, "Reason: %s\n", res.Reason)
+	}
+	_, _ = fmt.Fprintf(out, "Root-Policy-CID: %s\n", res.RootCID)
+	_, _ = fmt.Fprintf(out, "Effective-Policy-CID: %s\n", res.EffectiveCID)
+	for _, s := range res.Chain {
+		_, _ = fmt.Fprintf(out, "Supersession: %s -> %s (attestations: %s)\n", s.PriorCID, s.SuccessorCID, strings.Join(s.AttestationCIDs, ", "))
+	}
+	for _, c := range res.Candidates {
+		_, _ = fmt.Fprintf(out, "Candidate: %s\n", c)
+	}
+	for _, e := range res.Exclusions {
+		_, _ = fmt.Fprintf(out, "Excluded: %s (%s)\n", e.CID, e.Reason)
+	}
+	return 0
+}
+
+type policyVerdictChangeJSON struct {
+	Type string `json:"type"`
+	Role string `json:"role"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+type policyDiffEntryJSON struct {
+	Subject     string                    `json:"subject,omitempty"`
+	Name        string                    `json:"name,omitempty"`
+	Version     string                    `json:"version,omitempty"`
+	OldState    string                    `json:"old_state"`
+	NewState    string                    `json:"new_state"`
+	OldPointsTo string                    `json:"old_points_to,omitempty"`
+	NewPointsTo string                    `json:"new_points_to,omitempty"`
+	Verdicts    []policyVerdictChangeJSON `json:"verdicts,omitempty"`
+}
+
+// cmdPolicyDiff resolves the same attestations under two policies and prints
+// every subject and name whose resolution changes. It exits 1 when anything
+// changed, so it can gate policy changes in CI.
+func cmdPolicyDiff(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("policy diff", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	var oldPath, newPath, indexDir, asOf string
+	var attPaths stringList
+	var asJSON bool
+	var cas casFlags
+	fs.StringVar(&oldPath, "old", "", "Current TPDL policy file")
+	fs.StringVar(&newPath, "new", "", "Proposed TPDL policy file")
+	fs.Var(&attPaths, "att", "CATF attestation file (repeatable)")
+	fs.StringVar(&indexDir, "index", "", "Attestation index directory; every indexed attestation is read from CAS")
+	fs.StringVar(&asOf, "as-of", "", "Optional RFC3339 time or YYYY-MM-DD to resolve as of")
+	fs.BoolVar(&asJSON, "json", false, "Print changed entries as JSON")
+	cas.add(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if cas.listBackends {
+		printBackends(out)
+		return 0
+	}
+	if oldPath == "" || newPath == "" {
+		fmt.Fprintln(errOut, "missing --old or --new")
+		return 2
+	}
+	if len(attPaths) == 0 && indexDir == "" {
+		fmt.Fprintln(errOut, "missing --att or --index")
+		return 2
+	}
+
+	var opts resolver.Options
+	if asOf != "" {
+		t, err := resolver.ParseAsOf(asOf)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid --as-of: %v\n", err)
+			return 2
+		}
+		opts.AsOf = t
+	}
+	oldPolicy, err := os.ReadFile(oldPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	newPolicy, err := os.ReadFile(newPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "read policy: %v\n", err)
+		return 1
+	}
+	var attBytes [][]byte
+	for _, p := range attPaths {
+		b, rerr := os.ReadFile(p)
+		if rerr != nil {
+			fmt.Fprintf(errOut, "read att %s: %v\n", p, rerr)
+			return 1
+		}
+		attBytes = append(attBytes, b)
+	}
+	if indexDir != "" {
+		store, closeFn, err := cas.openCAS()
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		if closeFn != nil {
+			defer closeFn()
+		}
+		idx, err := index.Open(indexDir, store)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		for _, e := range idx.Query(index.Query{}) {
+			id, err := cid.Decode(e.CID)
+			if err != nil {
+				fmt.Fprintf(errOut, "policy diff: %v\n", err)
+				return 1
+			}
+			b, err := idx.Get(context.Background(), id)
+			if err != nil {
+				fmt.Fprintf(errOut, "policy diff: fetch %s: %v\n", e.CID, err)
+				return 1
+			}
+			attBytes = append(attBytes, b)
+		}
+	}
+
+	diff, err := resolver.DiffPolicies(attBytes, oldPolicy, newPolicy, opts)
+	if err != nil {
+		fmt.Fprintf(errOut, "policy diff: %s %v\n", tpdl.RuleID(err), err)
+		return 1
+	}
+
+	entries := []policyDiffEntryJSON{}
+	for _, d := range diff.Subjects {
+		if d.Changed() {
+			entries = append(entries, policyDiffEntryJSON{Subject: d.SubjectCID, OldState: string(d.OldState), NewState: string(d.NewState), Verdicts: verdictChangesJSON(d.VerdictChanges)})
+		}
+	}
+	for _, d := range diff.Names {
+		if d.Changed() {
+			entries = append(entries, policyDiffEntryJSON{Name: d.Name, Version: d.Version, OldState: string(d.OldState), NewState: string(d.NewState), OldPointsTo: d.OldPointsTo, NewPointsTo: d.NewPointsTo, Verdicts: verdictChangesJSON(d.VerdictChanges)})
+		}
+	}
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entries); err != nil {
+			fmt.Fprintf(errOut, "policy diff: %v\n", err)
+			return 1
+		}
+	} else {
+		for _, e := range entries {
+			if e.Subject != "" {
+				_, _ = fmt.Fprintf(out, "subject\t%s\t%s -> %s\n", e.Subject, e.OldState, e.NewState)
+			} else {
+				_, _ = fmt.Fprintf(out, "name\t%s\t%s\t%s %s -> %s %s\n", e.Name, e.Version, e.OldState, dash(e.OldPointsTo), e.NewState, dash(e.NewPointsTo))
+			}
+			for _, v := range e.Verdicts {
+				_, _ = fmt.Fprintf(out, "  %s/%s\t%s -> %s\n", v.Type, v.Role, v.Old, v.New)
+			}
+		}
+	}
+	if len(entries) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func verdictChangesJSON(changes []resolver.PolicyVerdictChange) []policyVerdictChangeJSON {
+	var out []policyVerdictChangeJSON
+	for _, c := range changes {
+		out = append(out, policyVerdictChangeJSON{Type: c.Type, Role: c.Role, Old: verdictSummary(c.Old), New: verdictSummary(c.New)})
+	}
+	return out
+}
+
+// verdictSummary renders a policy verdict as "satisfied 2/2", or "-" when the
+// rule is absent.
+func verdictSummary(v *resolver.PolicyVerdict) string {
+	if v == nil {
+		return "-"
+	}
+	status := "unsatisfied"
+	if v.Satisfied {
+		status = "satisfied"
+	}
+	return fmt.Sprintf("%s %d/%d", status, v.Observed, v.Quorum)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"xdao.co/catf/crof"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/service/grpcapi"
+	"xdao.co/catf/service/httpapi"
+	"xdao.co/catf/storage"
+	"xdao.co/catf/storage/casconfig"
+	"xdao.co/catf/storage/casregistry"
+)
+
+func cmdServe(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var addr string
+	var grpcAddr string
+	var casConfig string
+	var signerName string
+	var signerRole string
+	var resolverID string
+	var maxBodyBytes int64
+	var requestTimeout time.Duration
+
+	fs.StringVar(&addr, "addr", "127.0.0.1:8080", "Listen address")
+	fs.StringVar(&grpcAddr, "grpc-addr", "", "Optional listen address for the Resolver gRPC service")
+	fs.StringVar(&signerName, "signer", "", "Optional stored key name used to sign every CROF (from 'xdao-catf key init')")
+	fs.StringVar(&signerRole, "signer-role", "", "When using --signer, optionally use a derived role key")
+	fs.StringVar(&casConfig, "cas-config", "", "Optional CAS JSON config used to hydrate inputs referenced by CID")
+	fs.StringVar(&resolverID, "resolver-id", "xdao-resolver-reference", "Resolver-ID recorded in CROF")
+	fs.Int64Var(&maxBodyBytes, "max-body-bytes", httpapi.DefaultMaxBodyBytes, "Maximum request body (and gRPC message) size in bytes")
+	fs.DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "Per-request timeout, HTTP and gRPC (0 disables)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if maxBodyBytes <= 0 {
+		fmt.Fprintln(errOut, "invalid --max-body-bytes (must be positive)")
+		return 2
+	}
+
+	crofOpts := crof.RenderOptions{ResolverID: resolverID}
+	if signerName != "" {
+		ks, err := keys.CreateKeyStore("")
+		if err != nil {
+			fmt.Fprintf(errOut, "keys: %v\n", err)
+			return 1
+		}
+		seed, err := ks.LoadSeed("", signerName, signerRole, "")
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid signer: %v\n", err)
+			return 2
+		}
+		signer, err := keys.NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid signer: %v\n", err)
+			return 2
+		}
+		crofOpts.Signer = signer
+	}
+
+	var cas storage.CAS
+	if casConfig != "" {
+		cfg, err := casconfig.LoadFile(casConfig)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		store, closeFn, err := cfg.Open(casregistry.UsageCLI, "")
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		if closeFn != nil {
+			defer closeFn()
+		}
+		cas = store
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(errOut, "listen: %v\n", err)
+		return 1
+	}
+	srv := &http.Server{
+		Handler: httpapi.NewHandler(httpapi.Options{
+			CAS:            cas,
+			CROFOptions:    crofOpts,
+			MaxBodyBytes:   maxBodyBytes,
+			RequestTimeout: requestTimeout,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var gs *grpc.Server
+	if grpcAddr != "" {
+		gln, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			_ = ln.Close()
+			fmt.Fprintf(errOut, "listen: %v\n", err)
+			return 1
+		}
+		gs = grpc.NewServer(
+			grpc.MaxRecvMsgSize(int(maxBodyBytes)),
+			grpc.UnaryInterceptor(timeoutInterceptor(requestTimeout)),
+		)
+		grpcapi.RegisterResolverServer(gs, &grpcapi.Server{CAS: cas, CROFOptions: crofOpts})
+		go func() {
+			_ = gs.Serve(gln)
+		}()
+		_, _ = fmt.Fprintf(out, "gRPC resolver listening on %s\n", gln.Addr())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if gs != nil {
+			gs.GracefulStop()
+		}
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(out, "listening on http://%s\n", ln.Addr())
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(errOut, "serve: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// timeoutInterceptor bounds each unary gRPC call by d, as httpapi does for
+// HTTP requests; d <= 0 disables the limit.
+func timeoutInterceptor(d time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if d <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
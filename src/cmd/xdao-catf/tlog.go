@@ -0,0 +1,464 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/crof"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/tlog"
+)
+
+func cmdTlog(args []string, out io.Writer, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog <subcommand> ...")
+		fmt.Fprintln(errOut, "subcommands: append, sth, prove-inclusion, prove-consistency, verify-sth, verify-inclusion, verify-consistency")
+		return 2
+	}
+	switch args[0] {
+	case "append":
+		return cmdTlogAppend(args[1:], out, errOut)
+	case "sth":
+		return cmdTlogSTH(args[1:], out, errOut)
+	case "prove-inclusion":
+		return cmdTlogProveInclusion(args[1:], out, errOut)
+	case "prove-consistency":
+		return cmdTlogProveConsistency(args[1:], out, errOut)
+	case "verify-sth":
+		return cmdTlogVerifySTH(args[1:], out, errOut)
+	case "verify-inclusion":
+		return cmdTlogVerifyInclusion(args[1:], out, errOut)
+	case "verify-consistency":
+		return cmdTlogVerifyConsistency(args[1:], out, errOut)
+	default:
+		fmt.Fprintf(errOut, "unknown tlog subcommand: %s\n", args[0])
+		return 2
+	}
+}
+
+func cmdTlogAppend(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog append", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var catfFiles stringList
+	var crofFiles stringList
+	fs.StringVar(&dir, "dir", "", "Log directory")
+	fs.Var(&catfFiles, "catf", "CATF attestation file to log (repeatable)")
+	fs.Var(&crofFiles, "crof", "CROF file to log (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if dir == "" {
+		fmt.Fprintln(errOut, "missing --dir")
+		return 2
+	}
+	if len(catfFiles)+len(crofFiles)+fs.NArg() == 0 {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog append --dir <dir> [--catf <file> ...] [--crof <file> ...] [<CID> ...]")
+		return 2
+	}
+
+	var cids []string
+	for _, p := range catfFiles {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			fmt.Fprintf(errOut, "read catf %s: %v\n", p, err)
+			return 1
+		}
+		doc, err := catf.Parse(b)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid catf %s: %v\n", p, err)
+			return 1
+		}
+		s, err := doc.CID()
+		if err != nil {
+			fmt.Fprintf(errOut, "catf cid %s: %v\n", p, err)
+			return 1
+		}
+		cids = append(cids, s)
+	}
+	for _, p := range crofFiles {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			fmt.Fprintf(errOut, "read crof %s: %v\n", p, err)
+			return 1
+		}
+		s, err := crof.CID(b)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid crof %s: %v\n", p, err)
+			return 1
+		}
+		cids = append(cids, s)
+	}
+	var ids []cid.Cid
+	for _, s := range append(cids, fs.Args()...) {
+		id, err := cid.Decode(s)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid CID %s: %v\n", s, err)
+			return 2
+		}
+		ids = append(ids, id)
+	}
+
+	l, err := tlog.Open(dir)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	for _, id := range ids {
+		i, err := l.Append(id)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		_, _ = fmt.Fprintf(out, "%d\t%s\n", i, id)
+	}
+	return 0
+}
+
+func cmdTlogSTH(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog sth", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var signerName string
+	var signerRole string
+	var sigAlg string
+	var hashAlg string
+	var timestamp string
+	fs.StringVar(&dir, "dir", "", "Log directory")
+	fs.StringVar(&signerName, "signer", "", "Stored key name used to sign the tree head (from 'xdao-catf key init')")
+	fs.StringVar(&signerRole, "signer-role", "", "When using --signer, optionally use a derived role key")
+	fs.StringVar(&sigAlg, "signature-alg", "ed25519", "Signature algorithm: ed25519, or dilithium3 (a key derived from the stored seed)")
+	fs.StringVar(&hashAlg, "hash-alg", "sha256", "Signature digest: sha256, sha512 or sha3-256")
+	fs.StringVar(&timestamp, "timestamp", "", "Optional RFC3339 timestamp (default: now)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if dir == "" || signerName == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog sth --dir <dir> --signer <name> [--signer-role <role>] [--signature-alg <alg>] [--hash-alg <alg>] [--timestamp <RFC3339>]")
+		return 2
+	}
+	at := time.Now()
+	if timestamp != "" {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			fmt.Fprintf(errOut, "invalid --timestamp: %v\n", err)
+			return 2
+		}
+		at = t
+	}
+
+	ks, err := keys.CreateKeyStore("")
+	if err != nil {
+		fmt.Fprintf(errOut, "keys: %v\n", err)
+		return 1
+	}
+	seed, err := ks.LoadSeed("", signerName, signerRole, "")
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid signer: %v\n", err)
+		return 2
+	}
+	signer, err := treeHeadSigner(seed, sigAlg)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid signer: %v\n", err)
+		return 2
+	}
+
+	l, ok := openExistingLog(dir, errOut)
+	if !ok {
+		return 1
+	}
+	th, err := l.SignTreeHead(signer, hashAlg, at)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	_, _ = out.Write(th.Bytes())
+	return 0
+}
+
+// treeHeadSigner returns the Signer for a KMS-lite seed. A dilithium3 key is
+// derived from the seed (keys.DeriveDilithium3Key).
+func treeHeadSigner(seed []byte, sigAlg string) (keys.Signer, error) {
+	switch sigAlg {
+	case "ed25519":
+		return keys.NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+	case "dilithium3":
+		_, priv, err := keys.DeriveDilithium3Key(seed)
+		if err != nil {
+			return nil, err
+		}
+		return keys.NewDilithium3Signer(priv)
+	default:
+		return nil, fmt.Errorf("unsupported --signature-alg %q (expected ed25519 or dilithium3)", sigAlg)
+	}
+}
+
+func cmdTlogProveInclusion(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog prove-inclusion", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var cidStr string
+	var size uint64
+	fs.StringVar(&dir, "dir", "", "Log directory")
+	fs.StringVar(&cidStr, "cid", "", "Logged CID to prove")
+	fs.Uint64Var(&size, "size", 0, "Tree size to prove against (default: current size)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if dir == "" || cidStr == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog prove-inclusion --dir <dir> --cid <CID> [--size <n>]")
+		return 2
+	}
+	id, err := cid.Decode(cidStr)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid --cid: %v\n", err)
+		return 2
+	}
+	l, ok := openExistingLog(dir, errOut)
+	if !ok {
+		return 1
+	}
+	if size == 0 {
+		size = l.Size()
+	}
+	p, err := l.ProveInclusion(id, size)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return writeIndentedJSON(out, errOut, p)
+}
+
+func cmdTlogProveConsistency(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog prove-consistency", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var dir string
+	var oldSize uint64
+	var newSize uint64
+	fs.StringVar(&dir, "dir", "", "Log directory")
+	fs.Uint64Var(&oldSize, "old", 0, "Older tree size")
+	fs.Uint64Var(&newSize, "new", 0, "Newer tree size (default: current size)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if dir == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog prove-consistency --dir <dir> --old <n> [--new <m>]")
+		return 2
+	}
+	l, ok := openExistingLog(dir, errOut)
+	if !ok {
+		return 1
+	}
+	if newSize == 0 {
+		newSize = l.Size()
+	}
+	p, err := l.ProveConsistency(oldSize, newSize)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return writeIndentedJSON(out, errOut, p)
+}
+
+func cmdTlogVerifySTH(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog verify-sth", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var sthPath string
+	var logKey string
+	var skipLogKey bool
+	fs.StringVar(&sthPath, "sth", "", "Signed tree head file")
+	fs.StringVar(&logKey, "log-key", "", "Expected log key (<alg>:<base64>); required unless --insecure-skip-log-key")
+	fs.BoolVar(&skipLogKey, "insecure-skip-log-key", false, "Accept a tree head signed by any key")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if sthPath == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog verify-sth --sth <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
+		return 2
+	}
+	if !checkLogKeyFlags(logKey, skipLogKey, errOut) {
+		return 2
+	}
+	if _, ok := readTreeHead(sthPath, logKey, errOut); !ok {
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, "OK")
+	return 0
+}
+
+func cmdTlogVerifyInclusion(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog verify-inclusion", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var sthPath string
+	var proofPath string
+	var logKey string
+	var skipLogKey bool
+	fs.StringVar(&sthPath, "sth", "", "Signed tree head file")
+	fs.StringVar(&proofPath, "proof", "", "Inclusion proof JSON file (from 'tlog prove-inclusion')")
+	fs.StringVar(&logKey, "log-key", "", "Expected log key (<alg>:<base64>); required unless --insecure-skip-log-key")
+	fs.BoolVar(&skipLogKey, "insecure-skip-log-key", false, "Accept a tree head signed by any key")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if sthPath == "" || proofPath == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog verify-inclusion --sth <file> --proof <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
+		return 2
+	}
+	if !checkLogKeyFlags(logKey, skipLogKey, errOut) {
+		return 2
+	}
+	th, ok := readTreeHead(sthPath, logKey, errOut)
+	if !ok {
+		return 1
+	}
+	var p tlog.InclusionProof
+	if !readJSONFile(proofPath, &p, errOut) {
+		return 1
+	}
+	if err := p.VerifyTreeHead(th); err != nil {
+		fmt.Fprintf(errOut, "invalid: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, "OK")
+	return 0
+}
+
+func cmdTlogVerifyConsistency(args []string, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet("tlog verify-consistency", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+
+	var oldPath string
+	var newPath string
+	var proofPath string
+	var logKey string
+	var skipLogKey bool
+	fs.StringVar(&oldPath, "old-sth", "", "Older signed tree head file")
+	fs.StringVar(&newPath, "new-sth", "", "Newer signed tree head file")
+	fs.StringVar(&proofPath, "proof", "", "Consistency proof JSON file (from 'tlog prove-consistency')")
+	fs.StringVar(&logKey, "log-key", "", "Expected log key (<alg>:<base64>); required unless --insecure-skip-log-key")
+	fs.BoolVar(&skipLogKey, "insecure-skip-log-key", false, "Accept a tree head signed by any key")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if oldPath == "" || newPath == "" || proofPath == "" {
+		fmt.Fprintln(errOut, "usage: xdao-catf tlog verify-consistency --old-sth <file> --new-sth <file> --proof <file> (--log-key <alg:b64> | --insecure-skip-log-key)")
+		return 2
+	}
+	if !checkLogKeyFlags(logKey, skipLogKey, errOut) {
+		return 2
+	}
+	oldHead, ok := readTreeHead(oldPath, logKey, errOut)
+	if !ok {
+		return 1
+	}
+	newHead, ok := readTreeHead(newPath, logKey, errOut)
+	if !ok {
+		return 1
+	}
+	var p tlog.ConsistencyProof
+	if !readJSONFile(proofPath, &p, errOut) {
+		return 1
+	}
+	if err := p.VerifyTreeHeads(oldHead, newHead); err != nil {
+		fmt.Fprintf(errOut, "invalid: %v\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(out, "OK")
+	return 0
+}
+
+func openExistingLog(dir string, errOut io.Writer) (*tlog.Log, bool) {
+	if _, err := os.Stat(dir); err != nil {
+		fmt.Fprintf(errOut, "tlog: %v\n", err)
+		return nil, false
+	}
+	l, err := tlog.Open(dir)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return nil, false
+	}
+	return l, true
+}
+
+// checkLogKeyFlags requires exactly one of --log-key and
+// --insecure-skip-log-key: a tree head only proves anything when its Log-Key
+// is the key the verifier trusts.
+func checkLogKeyFlags(logKey string, skip bool, errOut io.Writer) bool {
+	switch {
+	case logKey == "" && !skip:
+		fmt.Fprintln(errOut, "missing --log-key (or pass --insecure-skip-log-key to accept any signing key)")
+		return false
+	case logKey != "" && skip:
+		fmt.Fprintln(errOut, "specify either --log-key or --insecure-skip-log-key, not both")
+		return false
+	}
+	return true
+}
+
+// readTreeHead parses and verifies a signed tree head, pinning its Log-Key
+// unless logKey is empty (--insecure-skip-log-key).
+func readTreeHead(path, logKey string, errOut io.Writer) (*tlog.TreeHead, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(errOut, "read tree head: %v\n", err)
+		return nil, false
+	}
+	th, err := tlog.ParseTreeHead(b)
+	if err != nil {
+		fmt.Fprintf(errOut, "invalid tree head %s: %v\n", path, err)
+		return nil, false
+	}
+	if err := th.Verify(); err != nil {
+		fmt.Fprintf(errOut, "invalid tree head %s: %v\n", path, err)
+		return nil, false
+	}
+	if logKey != "" && th.LogKey != logKey {
+		fmt.Fprintf(errOut, "invalid tree head %s: Log-Key %s does not match --log-key\n", path, th.LogKey)
+		return nil, false
+	}
+	return th, true
+}
+
+func readJSONFile(path string, v any, errOut io.Writer) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(errOut, "read proof: %v\n", err)
+		return false
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		fmt.Fprintf(errOut, "invalid proof %s: %v\n", path, err)
+		return false
+	}
+	return true
+}
+
+func writeIndentedJSON(out io.Writer, errOut io.Writer, v any) int {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,219 @@
+package crof
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/compliance"
+	"xdao.co/catf/resolver"
+	"xdao.co/catf/storage"
+)
+
+// AuditOptions configures a CROF replay audit.
+type AuditOptions struct {
+	// Compliance is the TPDL parsing mode used by the original resolver run.
+	Compliance compliance.ComplianceMode
+}
+
+// AuditReport is the outcome of replaying a CROF against its recorded inputs.
+type AuditReport struct {
+	CROFCID   string
+	ReplayCID string
+
+	// Match is true when the replayed CROF is byte-identical to the audited CROF.
+	Match bool
+
+	// Signed reports whether the audited CROF carries a CRYPTO signature.
+	// SignatureErr is set when a present signature fails verification.
+	Signed       bool
+	SignatureErr error
+
+	// Diffs lists per-section line differences when Match is false.
+	Diffs []SectionDiff
+
+	// Replay holds the replayed CROF bytes.
+	Replay []byte
+}
+
+// Passed reports whether the audit succeeded: the replay matches and any
+// signature present verifies. A CROF that replays identically but carries a
+// forged or corrupted signature does not pass.
+func (r *AuditReport) Passed() bool {
+	return r.Match && (!r.Signed || r.SignatureErr == nil)
+}
+
+// SectionDiff lists lines that differ between the audited and replayed CROF
+// within one section. Lines are compared as multisets and reported sorted.
+type SectionDiff struct {
+	Section  string
+	Original []string // present only in the audited CROF
+	Replayed []string // present only in the replayed CROF
+}
+
+// Audit re-resolves a CROF from its recorded inputs and reports whether the
+// output is reproducible.
+//
+// Every input listed in INPUTS (Trust-Policy-CID, Attestation-CID and Input-Hash)
+// is hydrated from cas and resolved again with resolver.ResolveWithCAS, as of
+// the recorded As-Of when present. The replay is rendered with the audited
+// CROF's META values (Resolver-ID, Resolved-At, Supersedes-CROF-CID) and CRYPTO
+// section, so a reproducible resolution yields byte-identical output.
+//
+// Audit returns an error only when the CROF cannot be parsed or an input cannot
+// be hydrated; resolution differences are reported in the AuditReport.
+func Audit(crofBytes []byte, cas storage.CAS, opts AuditOptions) (*AuditReport, error) {
+	return AuditContext(context.Background(), crofBytes, cas, opts)
+}
+
+// AuditContext is Audit with cancellation of input hydration.
+func AuditContext(ctx context.Context, crofBytes []byte, cas storage.CAS, opts AuditOptions) (*AuditReport, error) {
+	if cas == nil {
+		return nil, resolver.ErrMissingCAS
+	}
+	doc, err := Parse(crofBytes)
+	if err != nil {
+		return nil, fmt.Errorf("crof audit: %w", err)
+	}
+	original := doc.Render()
+
+	policyCID, err := cid.Decode(doc.Inputs.TrustPolicyCID)
+	if err != nil {
+		return nil, fmt.Errorf("crof audit: invalid Trust-Policy-CID: %w", err)
+	}
+	req := resolver.ResolveRequestCAS{
+		Policy:     resolver.BlobRef{CID: policyCID},
+		SubjectCID: doc.Result.SubjectCID,
+		Compliance: opts.Compliance,
+		AsOf:       doc.Inputs.AsOf,
+		CAS:        cas,
+	}
+	for _, s := range doc.Inputs.AttestationCIDs {
+		id, err := cid.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("crof audit: invalid Attestation-CID %q: %w", s, err)
+		}
+		req.Attestations = append(req.Attestations, resolver.BlobRef{CID: id})
+	}
+	for _, h := range doc.Inputs.InputHashes {
+		// Input-Hash inputs are bound by their sha256, not a CATF CID. They are
+		// hydrated as bytes so the replay derives the same Input-Hash binding.
+		b, err := hydrateInputHash(ctx, h, cas)
+		if err != nil {
+			return nil, fmt.Errorf("crof audit: hydrate %s: %w", h, err)
+		}
+		req.Attestations = append(req.Attestations, resolver.BlobRef{Bytes: b})
+	}
+
+	out, err := resolver.ResolveWithCASContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("crof audit: resolve: %w", err)
+	}
+
+	replay := &ParsedDocument{
+		Meta:   doc.Meta,
+		Crypto: doc.Crypto,
+	}
+	replay.Inputs.TrustPolicyCID = out.TrustPolicyCID
+	replay.Inputs.AsOf = out.Resolution.AsOf
+	for _, id := range out.AttestationIDs {
+		if strings.HasPrefix(id, "sha256:") {
+			replay.Inputs.InputHashes = append(replay.Inputs.InputHashes, id)
+			continue
+		}
+		replay.Inputs.AttestationCIDs = append(replay.Inputs.AttestationCIDs, id)
+	}
+	res := out.Resolution
+	replay.Result = Result{SubjectCID: res.SubjectCID, State: res.State, Confidence: res.Confidence, PolicyVerdicts: res.PolicyVerdicts}
+	replay.Paths = res.Paths
+	replay.Forks = res.Forks
+	replay.Exclusions = res.Exclusions
+	replay.Verdicts = res.Verdicts
+	replayBytes := replay.Render()
+
+	report := &AuditReport{
+		CROFCID:   cidutil.CIDv1RawSHA256(original),
+		ReplayCID: cidutil.CIDv1RawSHA256(replayBytes),
+		Match:     bytes.Equal(original, replayBytes),
+		Signed:    doc.Crypto.Signed(),
+		Replay:    replayBytes,
+	}
+	if report.Signed {
+		if _, err := VerifySignature(original); err != nil {
+			report.SignatureErr = err
+		}
+	}
+	if !report.Match {
+		report.Diffs = diffSections(original, replayBytes)
+	}
+	return report, nil
+}
+
+// hydrateInputHash fetches the bytes behind a "sha256:<hex>" Input-Hash.
+//
+// CAS objects are addressed by CIDv1 (raw + sha2-256), whose multihash carries
+// the same digest, so the Input-Hash maps to exactly one CID.
+func hydrateInputHash(ctx context.Context, h string, cas storage.CAS) ([]byte, error) {
+	digest, err := hex.DecodeString(strings.TrimPrefix(h, "sha256:"))
+	if err != nil || !strings.HasPrefix(h, "sha256:") {
+		return nil, errors.New("invalid Input-Hash")
+	}
+	mh, err := multihash.Encode(digest, multihash.SHA2_256)
+	if err != nil {
+		return nil, err
+	}
+	id := cid.NewCidV1(cid.Raw, mh)
+	b, err := storage.AsContextCAS(cas).GetContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cidutil.CIDv1RawSHA256(b) != id.String() {
+		return nil, storage.ErrCIDMismatch
+	}
+	return b, nil
+}
+
+func diffSections(original, replay []byte) []SectionDiff {
+	var out []SectionDiff
+	for _, sec := range crofSectionOrder {
+		a, _ := sectionLines(original, sec)
+		b, _ := sectionLines(replay, sec)
+		onlyA, onlyB := lineMultisetDiff(a, b)
+		if len(onlyA) == 0 && len(onlyB) == 0 {
+			continue
+		}
+		out = append(out, SectionDiff{Section: sec, Original: onlyA, Replayed: onlyB})
+	}
+	return out
+}
+
+func lineMultisetDiff(a, b []string) (onlyA, onlyB []string) {
+	counts := make(map[string]int, len(a))
+	for _, l := range a {
+		counts[l]++
+	}
+	for _, l := range b {
+		if counts[l] > 0 {
+			counts[l]--
+			continue
+		}
+		onlyB = append(onlyB, l)
+	}
+	for _, l := range a {
+		if counts[l] > 0 {
+			counts[l]--
+			onlyA = append(onlyA, l)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
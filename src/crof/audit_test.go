@@ -0,0 +1,273 @@
+package crof
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/resolver"
+	"xdao.co/catf/storage"
+)
+
+type memCAS struct {
+	mu sync.RWMutex
+	m  map[string][]byte
+}
+
+func newMemCAS() *memCAS {
+	return &memCAS{m: map[string][]byte{}}
+}
+
+func (c *memCAS) Put(b []byte) (cid.Cid, error) {
+	id, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m[id.String()] = append([]byte(nil), b...)
+	return id, nil
+}
+
+func (c *memCAS) Get(id cid.Cid) ([]byte, error) {
+	c.mu.RLock()
+	b, ok := c.m[id.String()]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func (c *memCAS) Has(id cid.Cid) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.m[id.String()]
+	return ok
+}
+
+// auditFixture resolves a small attestation set (including an unparseable
+// input bound by Input-Hash), stores every input in a CAS and returns the
+// signed CROF.
+func auditFixture(t *testing.T) ([]byte, *memCAS) {
+	t.Helper()
+
+	subject := "bafy-doc-audit-1"
+	pubA, privA := mustKeypair(t, 0xA1)
+	issuerA := issuerKey(pubA)
+	a1 := mustAttestation(t, subject, "Audit", map[string]string{"Type": "authorship", "Role": "author"}, issuerA, privA)
+	corrupt := []byte("not a catf")
+
+	policy := []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n\n" +
+		"TRUST\n" +
+		"Key: " + issuerA + "\n" +
+		"Role: author\n\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Type: authorship\n" +
+		"  Role: author\n\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+
+	cas := newMemCAS()
+	for _, b := range [][]byte{a1, corrupt, policy} {
+		if _, err := cas.Put(b); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	out, err := resolver.ResolveWithCAS(resolver.ResolveRequestCAS{
+		Attestations: []resolver.BlobRef{{Bytes: a1}, {Bytes: corrupt}},
+		Policy:       resolver.BlobRef{Bytes: policy},
+		SubjectCID:   subject,
+	})
+	if err != nil {
+		t.Fatalf("ResolveWithCAS: %v", err)
+	}
+	_, resolverPriv := mustKeypair(t, 0x5A)
+	signer, err := keys.NewEd25519Signer(resolverPriv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	crofBytes, err := RenderSigned(out.Resolution, out.TrustPolicyCID, out.AttestationIDs, RenderOptions{
+		ResolverID: "resolver-audit",
+		ResolvedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Signer:     signer,
+	})
+	if err != nil {
+		t.Fatalf("RenderSigned: %v", err)
+	}
+	return crofBytes, cas
+}
+
+func TestAudit_ReplayMatches(t *testing.T) {
+	crofBytes, cas := auditFixture(t)
+
+	doc, err := Parse(crofBytes)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Inputs.AttestationCIDs) != 1 || len(doc.Inputs.InputHashes) != 1 {
+		t.Fatalf("fixture must bind one CATF CID and one Input-Hash, got %+v", doc.Inputs)
+	}
+
+	report, err := Audit(crofBytes, cas, AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !report.Match || len(report.Diffs) != 0 {
+		t.Fatalf("expected match, got diffs %+v", report.Diffs)
+	}
+	if report.CROFCID != report.ReplayCID || !bytes.Equal(report.Replay, crofBytes) {
+		t.Fatalf("expected identical replay: %s vs %s", report.CROFCID, report.ReplayCID)
+	}
+	if !report.Signed || report.SignatureErr != nil {
+		t.Fatalf("expected valid signature, got signed=%v err=%v", report.Signed, report.SignatureErr)
+	}
+	if !report.Passed() {
+		t.Fatalf("expected audit to pass")
+	}
+}
+
+func TestAudit_ForgedSignatureFails(t *testing.T) {
+	crofBytes, cas := auditFixture(t)
+
+	// Keep the body, replace the signature: the replay still matches byte for
+	// byte (it reuses CRYPTO), but the audit must not pass.
+	doc, err := Parse(crofBytes)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	doc.Crypto.Signature = base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize))
+	forged := doc.Render()
+
+	report, err := Audit(forged, cas, AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !report.Match || report.SignatureErr == nil {
+		t.Fatalf("expected matching replay with invalid signature, got match=%v err=%v", report.Match, report.SignatureErr)
+	}
+	if report.Passed() {
+		t.Fatalf("audit passed with a forged signature")
+	}
+}
+
+func TestAudit_ReportsSectionDiff(t *testing.T) {
+	crofBytes, cas := auditFixture(t)
+
+	// Rewrite the recorded outcome; the replay must disagree in RESULT only.
+	doc, err := Parse(crofBytes)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	doc.Result.Confidence = resolver.ConfidenceLow
+	tampered := doc.Render()
+
+	report, err := Audit(tampered, cas, AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if report.Match {
+		t.Fatalf("expected mismatch")
+	}
+	if report.SignatureErr == nil {
+		t.Fatalf("expected signature failure on tampered CROF")
+	}
+	if len(report.Diffs) != 1 || report.Diffs[0].Section != "RESULT" {
+		t.Fatalf("expected RESULT diff only, got %+v", report.Diffs)
+	}
+	d := report.Diffs[0]
+	if len(d.Original) != 1 || d.Original[0] != "Confidence: Low" {
+		t.Fatalf("unexpected original lines: %v", d.Original)
+	}
+	if len(d.Replayed) != 1 || !strings.HasPrefix(d.Replayed[0], "Confidence: ") {
+		t.Fatalf("unexpected replayed lines: %v", d.Replayed)
+	}
+}
+
+func TestAudit_MissingInput(t *testing.T) {
+	crofBytes, _ := auditFixture(t)
+
+	_, err := Audit(crofBytes, newMemCAS(), AuditOptions{})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := Audit(crofBytes, nil, AuditOptions{}); !errors.Is(err, resolver.ErrMissingCAS) {
+		t.Fatalf("expected ErrMissingCAS, got %v", err)
+	}
+}
+
+func TestAudit_ReplaysAsOf(t *testing.T) {
+	subject := "bafy-doc-audit-asof"
+	pubA, privA := mustKeypair(t, 0xA2)
+	issuerA := issuerKey(pubA)
+	expired := mustAttestation(t, subject, "Audit", map[string]string{"Type": "authorship", "Role": "author", "Expires": "2026-02-01"}, issuerA, privA)
+	policy := []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n\n" +
+		"TRUST\n" +
+		"Key: " + issuerA + "\n" +
+		"Role: author\n\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Type: authorship\n" +
+		"  Role: author\n\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+	cas := newMemCAS()
+	for _, b := range [][]byte{expired, policy} {
+		if _, err := cas.Put(b); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	asOf := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	out, err := resolver.ResolveWithCAS(resolver.ResolveRequestCAS{
+		Attestations: []resolver.BlobRef{{Bytes: expired}},
+		Policy:       resolver.BlobRef{Bytes: policy},
+		SubjectCID:   subject,
+		AsOf:         asOf,
+	})
+	if err != nil {
+		t.Fatalf("ResolveWithCAS: %v", err)
+	}
+	if out.Resolution.State != resolver.StateUnresolved {
+		t.Fatalf("expected expired attestation to leave subject Unresolved, got %s", out.Resolution.State)
+	}
+	crofBytes := Render(out.Resolution, out.TrustPolicyCID, out.AttestationIDs, RenderOptions{})
+	if !bytes.Contains(crofBytes, []byte("INPUTS\nTrust-Policy-CID: "+out.TrustPolicyCID+"\nAs-Of: 2026-03-01T00:00:00Z\n")) {
+		t.Fatalf("As-Of not recorded in INPUTS:\n%s", crofBytes)
+	}
+	doc, err := Parse(crofBytes)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !doc.Inputs.AsOf.Equal(asOf) {
+		t.Fatalf("parsed As-Of = %v", doc.Inputs.AsOf)
+	}
+
+	report, err := Audit(crofBytes, cas, AuditOptions{})
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if !report.Match {
+		t.Fatalf("expected as-of replay to match, got diffs %+v", report.Diffs)
+	}
+
+	nonCanonical := bytes.Replace(crofBytes, []byte("As-Of: 2026-03-01T00:00:00Z"), []byte("As-Of: 2026-03-01"), 1)
+	if _, err := CanonicalizeCROF(nonCanonical); err == nil {
+		t.Fatalf("expected non-RFC3339 As-Of to be rejected")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -63,6 +64,7 @@ func validateCanonicalCROF(doc string) error {
 	}
 
 	i := 1
+	spec := ""
 	for _, sec := range crofSectionOrder {
 		if i >= len(lines)-2 {
 			return fmt.Errorf("missing section %q", sec)
@@ -79,9 +81,13 @@ func validateCanonicalCROF(doc string) error {
 			return fmt.Errorf("missing blank line after section %q", sec)
 		}
 		body := lines[start:i]
-		if err := validateSection(sec, body); err != nil {
+		if err := validateSection(sec, spec, body); err != nil {
 			return err
 		}
+		if sec == "META" {
+			// META is validated first; its Spec selects the profile for the rest.
+			spec = metaSpec(body)
+		}
 		// Consume the required section terminator blank line.
 		i++
 	}
@@ -92,17 +98,29 @@ func validateCanonicalCROF(doc string) error {
 	return nil
 }
 
-func validateSection(section string, body []string) error {
+func validateSection(section, spec string, body []string) error {
 	switch section {
 	case "META":
 		return validateMeta(body)
 	case "INPUTS":
 		return validateInputs(body)
 	case "RESULT":
+		if spec == specName {
+			return validateNameResult(body)
+		}
 		return validateResult(body)
 	case "PATHS":
+		if spec == specName {
+			if len(body) > 0 {
+				return errors.New("PATHS: must be empty for name resolution")
+			}
+			return nil
+		}
 		return validatePaths(body)
 	case "FORKS":
+		if spec == specName {
+			return validateNameForks(body)
+		}
 		return validateForks(body)
 	case "EXCLUSIONS":
 		return validateExclusions(body)
@@ -169,6 +187,15 @@ func validateMeta(body []string) error {
 	return nil
 }
 
+func metaSpec(body []string) string {
+	for _, l := range body {
+		if v, ok := strings.CutPrefix(l, "Spec: "); ok {
+			return v
+		}
+	}
+	return ""
+}
+
 func validateInputs(body []string) error {
 	if len(body) == 0 {
 		return errors.New("INPUTS: missing Trust-Policy-CID")
@@ -183,7 +210,16 @@ func validateInputs(body []string) error {
 	var att []string
 	var hashes []string
 	seenHash := false
-	for i := 1; i < len(body); i++ {
+	first := 1
+	if len(body) > 1 && strings.HasPrefix(body[1], "As-Of: ") {
+		_, v, err := validateKVLine(body[1])
+		t, perr := time.Parse(time.RFC3339, v)
+		if err != nil || perr != nil || t.UTC().Format(time.RFC3339) != v {
+			return errors.New("INPUTS: invalid As-Of")
+		}
+		first = 2
+	}
+	for i := first; i < len(body); i++ {
 		if strings.HasPrefix(body[i], "Attestation-CID: ") {
 			if seenHash {
 				return errors.New("INPUTS: Attestation-CID after Input-Hash")
@@ -408,6 +444,87 @@ func validateForks(body []string) error {
 	return nil
 }
 
+// validateNameResult validates RESULT for the name-resolution profile.
+func validateNameResult(body []string) error {
+	if err := validateSortedStrict(body); err != nil {
+		return fmt.Errorf("RESULT: %w", err)
+	}
+	need := map[string]bool{"Name": false, "Confidence": false, "State": false}
+	seen := make(map[string]bool)
+	for _, l := range body {
+		k, v, err := validateKVLine(l)
+		if err != nil {
+			return fmt.Errorf("RESULT: %w", err)
+		}
+		switch k {
+		case "Name", "Confidence", "State", "Version", "Points-To", "Version-Selector":
+			if seen[k] {
+				return fmt.Errorf("RESULT: duplicate %s", k)
+			}
+			seen[k] = true
+			if _, ok := need[k]; ok {
+				need[k] = true
+			}
+		case "Binding-CID", "Considered-Version":
+		case "Policy-Verdict":
+			if err := validatePolicyVerdictValue(v); err != nil {
+				return fmt.Errorf("RESULT: %w", err)
+			}
+		case "Policy-Issuer-Key":
+			if err := validatePolicyIssuerKeyValue(v); err != nil {
+				return fmt.Errorf("RESULT: %w", err)
+			}
+		case "Policy-Verdict-Reason":
+			if err := validatePolicyVerdictReasonValue(v); err != nil {
+				return fmt.Errorf("RESULT: %w", err)
+			}
+		default:
+			return fmt.Errorf("RESULT: unknown key %q", k)
+		}
+	}
+	for k, ok := range need {
+		if !ok {
+			return fmt.Errorf("RESULT: missing %s", k)
+		}
+	}
+	return nil
+}
+
+// validateNameForks validates FORKS for the name-resolution profile.
+func validateNameForks(body []string) error {
+	var lastID string
+	i := 0
+	for i < len(body) {
+		if !strings.HasPrefix(body[i], "Fork-ID: ") {
+			return errors.New("FORKS: expected Fork-ID")
+		}
+		_, id, err := validateKVLine(body[i])
+		if err != nil {
+			return fmt.Errorf("FORKS: %w", err)
+		}
+		if lastID != "" && !(lastID < id) {
+			return errors.New("FORKS: Fork-ID not sorted")
+		}
+		lastID = id
+		i++
+		var bindings []string
+		for i < len(body) && strings.HasPrefix(body[i], "Conflicting-Binding: ") {
+			_, v, err := validateKVLine(body[i])
+			if err != nil {
+				return errors.New("FORKS: invalid Conflicting-Binding")
+			}
+			bindings = append(bindings, v)
+			i++
+		}
+		for j := 1; j < len(bindings); j++ {
+			if !(bindings[j-1] < bindings[j]) {
+				return errors.New("FORKS: Conflicting-Binding not sorted")
+			}
+		}
+	}
+	return nil
+}
+
 type exclusionRecord struct {
 	cid    string
 	hash   string
@@ -497,6 +614,8 @@ type verdictRecord struct {
 	lineJoinRole       string
 	lineJoinReasons    string
 	lineJoinRevokedBy  string
+	signerKeys         []string
+	lineJoinSigners    string
 }
 
 func parseBoolLine(line, key string) (bool, error) {
@@ -574,6 +693,19 @@ func validateVerdicts(body []string) error {
 			vr.issuerKey = v
 			i++
 		}
+		for i < len(body) && strings.HasPrefix(body[i], "Signer-Key: ") {
+			_, v, err := validateKVLine(body[i])
+			if err != nil {
+				return fmt.Errorf("VERDICTS: %w", err)
+			}
+			vr.signerKeys = append(vr.signerKeys, v)
+			i++
+		}
+		for j := 1; j < len(vr.signerKeys); j++ {
+			if vr.signerKeys[j-1] >= vr.signerKeys[j] {
+				return errors.New("VERDICTS: Signer-Key not sorted")
+			}
+		}
 		if i < len(body) && strings.HasPrefix(body[i], "Claim-Type: ") {
 			_, v, err := validateKVLine(body[i])
 			if err != nil {
@@ -639,6 +771,24 @@ func validateVerdicts(body []string) error {
 			}
 		}
 
+		var chains []string
+		for i < len(body) && strings.HasPrefix(body[i], "Delegation-Chain: ") {
+			_, v, err := validateKVLine(body[i])
+			if err != nil {
+				return fmt.Errorf("VERDICTS: %w", err)
+			}
+			if _, ok := parseDelegationChain(v); !ok {
+				return errors.New("VERDICTS: invalid Delegation-Chain")
+			}
+			chains = append(chains, v)
+			i++
+		}
+		for j := 1; j < len(chains); j++ {
+			if chains[j-1] >= chains[j] {
+				return errors.New("VERDICTS: Delegation-Chain not sorted")
+			}
+		}
+
 		for i < len(body) && strings.HasPrefix(body[i], "Reason: ") {
 			_, v, err := validateKVLine(body[i])
 			if err != nil {
@@ -665,6 +815,7 @@ func validateVerdicts(body []string) error {
 		vr.lineJoinRole = strings.Join(vr.trustRoles, ",")
 		vr.lineJoinReasons = strings.Join(vr.reasons, ",")
 		vr.lineJoinRevokedBy = strings.Join(vr.revokedBy, ",")
+		vr.lineJoinSigners = strings.Join(vr.signerKeys, ",")
 		recs = append(recs, vr)
 	}
 
@@ -710,7 +861,10 @@ func verdictLess(a, b verdictRecord) bool {
 	if a.lineJoinReasons != b.lineJoinReasons {
 		return a.lineJoinReasons < b.lineJoinReasons
 	}
-	return a.lineJoinRevokedBy < b.lineJoinRevokedBy
+	if a.lineJoinRevokedBy != b.lineJoinRevokedBy {
+		return a.lineJoinRevokedBy < b.lineJoinRevokedBy
+	}
+	return a.lineJoinSigners < b.lineJoinSigners
 }
 
 func validateCrypto(body []string) error {
@@ -721,19 +875,31 @@ func validateCrypto(body []string) error {
 		return fmt.Errorf("CRYPTO: %w", err)
 	}
 	need := map[string]bool{"Hash-Alg": false, "Resolver-Key": false, "Signature-Alg": false, "Signature": false}
+	values := make(map[string]string, len(body))
 	for _, l := range body {
-		k, _, err := validateKVLine(l)
+		k, v, err := validateKVLine(l)
 		if err != nil {
 			return fmt.Errorf("CRYPTO: %w", err)
 		}
 		if _, ok := need[k]; ok {
 			need[k] = true
 		}
+		values[k] = v
 	}
 	for k, ok := range need {
 		if !ok {
 			return fmt.Errorf("CRYPTO: missing %s", k)
 		}
 	}
+	if !supportedHashAlgs[values["Hash-Alg"]] {
+		return fmt.Errorf("CRYPTO: unsupported Hash-Alg %q", values["Hash-Alg"])
+	}
+	sigAlg := values["Signature-Alg"]
+	if !supportedSignatureAlgs[sigAlg] {
+		return fmt.Errorf("CRYPTO: unsupported Signature-Alg %q", sigAlg)
+	}
+	if !strings.HasPrefix(values["Resolver-Key"], sigAlg+":") {
+		return errors.New("CRYPTO: Resolver-Key algorithm does not match Signature-Alg")
+	}
 	return nil
 }
@@ -29,7 +29,7 @@ func RenderWithCID(res *resolver.Resolution, trustPolicyCID string, attestationC
 	return b, cid, nil
 }
 
-// RenderSignedWithCID renders CROF with a required ed25519 signature and returns its CID.
+// RenderSignedWithCID renders CROF with a required signature and returns its CID.
 //
 // Unlike RenderWithCID, this fails explicitly if signing cannot be performed.
 func RenderSignedWithCID(res *resolver.Resolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) ([]byte, string, error) {
@@ -1,11 +1,13 @@
 package crof
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"xdao.co/catf/compliance"
 	"xdao.co/catf/resolver"
+	"xdao.co/catf/tpdl"
 )
 
 func TestRenderWithCompliance_StrictRejectsAmbiguityAndResolvedAt(t *testing.T) {
@@ -49,3 +51,22 @@ func TestRenderWithCompliance_PermissiveAllowsResolvedAt(t *testing.T) {
 		t.Fatalf("expected CROF bytes")
 	}
 }
+
+func TestPolicyCIDWithCompliance_StrictRequiresCanonical(t *testing.T) {
+	canon := "-----BEGIN XDAO TRUST POLICY-----\nMETA\nSpec: xdao-tpdl-1\nVersion: 1\n\nTRUST\nKey: ed25519:AAAA\nRole: author\n\nRULES\nRequire:\n  Type: authorship\n  Role: author\n  Quorum: 1\n-----END XDAO TRUST POLICY-----\n"
+	messy := strings.Replace(canon, "Spec: xdao-tpdl-1\nVersion: 1\n", "Version: 1\nSpec: xdao-tpdl-1\n", 1)
+
+	if got, err := PolicyCIDWithCompliance([]byte(messy), compliance.Permissive); err != nil || got != PolicyCID([]byte(messy)) {
+		t.Fatalf("permissive: got %q, %v", got, err)
+	}
+	if _, err := PolicyCIDWithCompliance([]byte(messy), compliance.Strict); tpdl.RuleID(err) != "TPDL-CANON-001" {
+		t.Fatalf("expected TPDL-CANON-001, got %v", err)
+	}
+	want, err := tpdl.CID([]byte(canon))
+	if err != nil {
+		t.Fatalf("tpdl.CID: %v", err)
+	}
+	if got, err := PolicyCIDWithCompliance([]byte(canon), compliance.Strict); err != nil || got != want {
+		t.Fatalf("strict: got %q, %v; want %q", got, err, want)
+	}
+}
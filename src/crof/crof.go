@@ -5,8 +5,6 @@ package crof
 
 import (
 	"crypto/ed25519"
-	"crypto/sha256"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"sort"
@@ -15,7 +13,9 @@ import (
 
 	"xdao.co/catf/cidutil"
 	"xdao.co/catf/compliance"
+	"xdao.co/catf/keys"
 	"xdao.co/catf/resolver"
+	"xdao.co/catf/tpdl"
 )
 
 const (
@@ -23,12 +23,33 @@ const (
 	Postamble = "-----END XDAO RESOLUTION-----"
 )
 
+// META Spec values. The Spec selects the CROF profile: subject resolution
+// (RESULT Subject-CID, PATHS/FORKS over attestation paths) or name resolution
+// (RESULT Name/Binding-CID, FORKS over competing bindings).
+const (
+	specSubject = "xdao-crof-1"
+	specName    = "xdao-crof-name-1"
+)
+
 // PolicyCID returns a deterministic local identifier for a trust policy document.
-// This is an IPFS-compatible CIDv1 (raw + sha2-256) derived from canonical bytes.
+// This is an IPFS-compatible CIDv1 (raw + sha2-256) of policyBytes as given;
+// use tpdl.CID (or PolicyCIDWithCompliance) to refuse policies that are not in
+// canonical form.
 func PolicyCID(policyBytes []byte) string {
 	return cidutil.CIDv1RawSHA256(policyBytes)
 }
 
+// PolicyCIDWithCompliance returns the Trust-Policy-CID for policyBytes.
+//
+// In strict mode this is tpdl.CID, which rejects non-canonical policies
+// (TPDL-CANON-001). Permissive mode hashes the bytes as given, like PolicyCID.
+func PolicyCIDWithCompliance(policyBytes []byte, mode compliance.ComplianceMode) (string, error) {
+	if mode == compliance.Strict {
+		return tpdl.CID(policyBytes)
+	}
+	return PolicyCID(policyBytes), nil
+}
+
 type RenderOptions struct {
 	ResolverID string
 	ResolvedAt time.Time // informational only; zero means omit
@@ -37,11 +58,18 @@ type RenderOptions struct {
 	// If set, the CROF asserts it supersedes a prior CROF identified by CID.
 	SupersedesCROFCID string
 
-	// Optional CROF signing. If PrivateKey is set, the output will include a CRYPTO
-	// section populated and Signature computed over the CROF bytes excluding the
-	// Signature: line.
+	// Optional CROF signing. If Signer (or PrivateKey) is set, the output will
+	// include a CRYPTO section populated and Signature computed over the CROF bytes
+	// excluding the Signature: line.
+	//
+	// Signer takes precedence over PrivateKey. When Signer is set, ResolverKey may
+	// be left empty and is derived from the signer's public key.
+	//
+	// HashAlg selects the CRYPTO Hash-Alg (sha256, sha512, sha3-256); empty means sha256.
 	ResolverKey string
 	PrivateKey  ed25519.PrivateKey
+	Signer      keys.Signer
+	HashAlg     string
 }
 
 // Render produces a canonical CROF document binding a resolution to its inputs.
@@ -52,57 +80,82 @@ func Render(res *resolver.Resolution, trustPolicyCID string, attestationCIDs []s
 		resolverID = "xdao-resolver-reference"
 	}
 
-	canSign := opts.ResolverKey != "" && len(opts.PrivateKey) == ed25519.PrivateKeySize
-	var cryptoLines []string
-	if canSign {
-		cryptoLines = []string{
-			"Hash-Alg: sha256",
-			"Resolver-Key: " + opts.ResolverKey,
-			"Signature-Alg: ed25519",
-			"Signature: 0",
-		}
-		sort.Strings(cryptoLines)
-	}
-
-	out := renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
-	if canSign {
-		sig, err := signCROF(out, opts.PrivateKey)
-		if err != nil {
-			// Never panic in library code; fall back to unsigned output.
-			return renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, nil)
+	if opts.Signer != nil || (opts.ResolverKey != "" && len(opts.PrivateKey) == ed25519.PrivateKeySize) {
+		if signer, resolverKey, err := signingConfig(opts); err == nil {
+			render := func(cryptoLines []string) []byte {
+				return renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
+			}
+			if out, err := renderSigned(render, opts, signer, resolverKey); err == nil {
+				return out
+			}
 		}
-		out = []byte(strings.Replace(string(out), "Signature: 0", "Signature: "+sig, 1))
+		// Never panic in library code; fall back to unsigned output.
 	}
-
-	return out
+	return renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, nil)
 }
 
-// RenderSigned renders CROF with a required ed25519 signature.
+// RenderSigned renders CROF with a required signature from opts.Signer or opts.PrivateKey.
 //
 // This returns an error instead of panicking if signing is requested but cannot be performed.
 func RenderSigned(res *resolver.Resolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) ([]byte, error) {
-	if opts.ResolverKey == "" {
-		return nil, errors.New("crof: signing requires ResolverKey")
-	}
-	if len(opts.PrivateKey) != ed25519.PrivateKeySize {
-		return nil, errors.New("crof: signing requires a valid ed25519 private key")
+	signer, resolverKey, err := signingConfig(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	resolverID := opts.ResolverID
 	if resolverID == "" {
 		resolverID = "xdao-resolver-reference"
 	}
+	render := func(cryptoLines []string) []byte {
+		return renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
+	}
+	return renderSigned(render, opts, signer, resolverKey)
+}
+
+// signingConfig selects the signer and Resolver-Key for opts.
+func signingConfig(opts RenderOptions) (keys.Signer, string, error) {
+	if hashAlg := opts.hashAlg(); !supportedHashAlgs[hashAlg] {
+		return nil, "", fmt.Errorf("crof: unsupported Hash-Alg %q", hashAlg)
+	}
+	if opts.Signer != nil {
+		if alg := opts.Signer.Algorithm(); !supportedSignatureAlgs[alg] {
+			return nil, "", fmt.Errorf("crof: unsupported signer algorithm %q", alg)
+		}
+		resolverKey := keys.IssuerKeyForSigner(opts.Signer)
+		if opts.ResolverKey != "" && opts.ResolverKey != resolverKey {
+			return nil, "", errors.New("crof: ResolverKey does not match signer public key")
+		}
+		return opts.Signer, resolverKey, nil
+	}
+	if opts.ResolverKey == "" {
+		return nil, "", errors.New("crof: signing requires ResolverKey")
+	}
+	signer, err := keys.NewEd25519Signer(opts.PrivateKey)
+	if err != nil {
+		return nil, "", errors.New("crof: signing requires a valid ed25519 private key")
+	}
+	return signer, opts.ResolverKey, nil
+}
 
+func (opts RenderOptions) hashAlg() string {
+	if opts.HashAlg == "" {
+		return "sha256"
+	}
+	return opts.HashAlg
+}
+
+func renderSigned(render func(cryptoLines []string) []byte, opts RenderOptions, signer keys.Signer, resolverKey string) ([]byte, error) {
 	cryptoLines := []string{
-		"Hash-Alg: sha256",
-		"Resolver-Key: " + opts.ResolverKey,
-		"Signature-Alg: ed25519",
+		"Hash-Alg: " + opts.hashAlg(),
+		"Resolver-Key: " + resolverKey,
+		"Signature-Alg: " + signer.Algorithm(),
 		"Signature: 0",
 	}
 	sort.Strings(cryptoLines)
 
-	out := renderWithCryptoLines(res, trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
-	sig, err := signCROF(out, opts.PrivateKey)
+	out := render(cryptoLines)
+	sig, err := signCROF(out, signer, opts.hashAlg())
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +171,109 @@ func renderWithCryptoLines(
 	opts RenderOptions,
 	cryptoLines []string,
 ) []byte {
+	return renderBody(subjectBody(res), trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
+}
+
+// crofBody holds the profile-specific content of a CROF document. META, INPUTS,
+// EXCLUSIONS, VERDICTS and CRYPTO are rendered identically for every profile.
+type crofBody struct {
+	spec        string
+	resultLines []string // sorted by renderBody
+	pathLines   []string
+	forkLines   []string
+	exclusions  []resolver.Exclusion
+	verdicts    []resolver.Verdict
+	asOf        time.Time // INPUTS As-Of; zero means omit
+}
+
+// subjectBody builds the subject-resolution (xdao-crof-1) profile.
+func subjectBody(res *resolver.Resolution) crofBody {
+	resultLines := []string{
+		"Subject-CID: " + res.SubjectCID,
+		"Confidence: " + string(res.Confidence),
+		"State: " + string(res.State),
+	}
+	resultLines = append(resultLines, policyVerdictLines(res.PolicyVerdicts)...)
+
+	var pathLines []string
+	paths := append([]resolver.Path(nil), res.Paths...)
+	sort.Slice(paths, func(i, j int) bool { return paths[i].ID < paths[j].ID })
+	for _, p := range paths {
+		pathLines = append(pathLines, "Path-ID: "+p.ID)
+		for _, cid := range p.CIDs {
+			pathLines = append(pathLines, "Attestation-CID: "+cid)
+		}
+	}
+
+	var forkLines []string
+	forks := append([]resolver.Fork(nil), res.Forks...)
+	sort.Slice(forks, func(i, j int) bool { return forks[i].ID < forks[j].ID })
+	for _, f := range forks {
+		forkLines = append(forkLines, "Fork-ID: "+f.ID)
+		paths := append([]string(nil), f.ConflictingPath...)
+		sort.Strings(paths)
+		for _, pid := range paths {
+			forkLines = append(forkLines, "Conflicting-Path: "+pid)
+		}
+	}
+
+	return crofBody{
+		spec:        specSubject,
+		resultLines: resultLines,
+		pathLines:   pathLines,
+		forkLines:   forkLines,
+		exclusions:  res.Exclusions,
+		verdicts:    res.Verdicts,
+		asOf:        res.AsOf,
+	}
+}
+
+func policyVerdictLines(policyVerdicts []resolver.PolicyVerdict) []string {
+	if len(policyVerdicts) == 0 {
+		return nil
+	}
+	var lines []string
+	pvs := append([]resolver.PolicyVerdict(nil), policyVerdicts...)
+	sort.Slice(pvs, func(i, j int) bool {
+		if pvs[i].Type == pvs[j].Type {
+			if pvs[i].Role == pvs[j].Role {
+				return pvs[i].Quorum < pvs[j].Quorum
+			}
+			return pvs[i].Role < pvs[j].Role
+		}
+		return pvs[i].Type < pvs[j].Type
+	})
+	for _, pv := range pvs {
+		lines = append(lines, fmt.Sprintf(
+			"Policy-Verdict: Type=%s; Role=%s; Quorum=%d; Observed=%d; Satisfied=%t",
+			pv.Type, pv.Role, pv.Quorum, pv.Observed, pv.Satisfied,
+		))
+		issuerKeys := uniqueSorted(pv.IssuerKeys)
+		for _, k := range issuerKeys {
+			lines = append(lines, fmt.Sprintf(
+				"Policy-Issuer-Key: Type=%s; Role=%s; Issuer-Key=%s",
+				pv.Type, pv.Role, k,
+			))
+		}
+		reasons := uniqueSorted(pv.Reasons)
+		for _, r := range reasons {
+			lines = append(lines, fmt.Sprintf(
+				"Policy-Verdict-Reason: Type=%s; Role=%s; Reason=%s",
+				pv.Type, pv.Role, r,
+			))
+		}
+	}
+	return lines
+}
+
+func renderBody(
+	body crofBody,
+	trustPolicyCID string,
+	attestationCIDs []string,
+	resolverID string,
+	opts RenderOptions,
+	cryptoLines []string,
+) []byte {
 
 	inputIDs := append([]string(nil), attestationCIDs...)
 	var attCIDs []string
@@ -140,7 +296,7 @@ func renderWithCryptoLines(
 	sb.WriteString("META\n")
 	metaLines := []string{
 		"Resolver-ID: " + resolverID,
-		"Spec: xdao-crof-1",
+		"Spec: " + body.spec,
 		"Version: 1",
 	}
 	if !opts.ResolvedAt.IsZero() {
@@ -161,6 +317,11 @@ func renderWithCryptoLines(
 	sb.WriteString("Trust-Policy-CID: ")
 	sb.WriteString(trustPolicyCID)
 	sb.WriteString("\n")
+	if !body.asOf.IsZero() {
+		sb.WriteString("As-Of: ")
+		sb.WriteString(body.asOf.UTC().Format(time.RFC3339))
+		sb.WriteString("\n")
+	}
 	for _, cid := range attCIDs {
 		sb.WriteString("Attestation-CID: ")
 		sb.WriteString(cid)
@@ -175,43 +336,7 @@ func renderWithCryptoLines(
 
 	// RESULT
 	sb.WriteString("RESULT\n")
-	resultLines := []string{
-		"Subject-CID: " + res.SubjectCID,
-		"Confidence: " + string(res.Confidence),
-		"State: " + string(res.State),
-	}
-	if len(res.PolicyVerdicts) > 0 {
-		pvs := append([]resolver.PolicyVerdict(nil), res.PolicyVerdicts...)
-		sort.Slice(pvs, func(i, j int) bool {
-			if pvs[i].Type == pvs[j].Type {
-				if pvs[i].Role == pvs[j].Role {
-					return pvs[i].Quorum < pvs[j].Quorum
-				}
-				return pvs[i].Role < pvs[j].Role
-			}
-			return pvs[i].Type < pvs[j].Type
-		})
-		for _, pv := range pvs {
-			resultLines = append(resultLines, fmt.Sprintf(
-				"Policy-Verdict: Type=%s; Role=%s; Quorum=%d; Observed=%d; Satisfied=%t",
-				pv.Type, pv.Role, pv.Quorum, pv.Observed, pv.Satisfied,
-			))
-			issuerKeys := uniqueSorted(pv.IssuerKeys)
-			for _, k := range issuerKeys {
-				resultLines = append(resultLines, fmt.Sprintf(
-					"Policy-Issuer-Key: Type=%s; Role=%s; Issuer-Key=%s",
-					pv.Type, pv.Role, k,
-				))
-			}
-			reasons := uniqueSorted(pv.Reasons)
-			for _, r := range reasons {
-				resultLines = append(resultLines, fmt.Sprintf(
-					"Policy-Verdict-Reason: Type=%s; Role=%s; Reason=%s",
-					pv.Type, pv.Role, r,
-				))
-			}
-		}
-	}
+	resultLines := append([]string(nil), body.resultLines...)
 	sort.Strings(resultLines)
 	for _, l := range resultLines {
 		sb.WriteString(l)
@@ -221,41 +346,23 @@ func renderWithCryptoLines(
 
 	// PATHS
 	sb.WriteString("PATHS\n")
-	paths := append([]resolver.Path(nil), res.Paths...)
-	sort.Slice(paths, func(i, j int) bool { return paths[i].ID < paths[j].ID })
-	for _, p := range paths {
-		sb.WriteString("Path-ID: ")
-		sb.WriteString(p.ID)
+	for _, l := range body.pathLines {
+		sb.WriteString(l)
 		sb.WriteString("\n")
-		for _, cid := range p.CIDs {
-			sb.WriteString("Attestation-CID: ")
-			sb.WriteString(cid)
-			sb.WriteString("\n")
-		}
 	}
 	sb.WriteString("\n")
 
 	// FORKS
 	sb.WriteString("FORKS\n")
-	forks := append([]resolver.Fork(nil), res.Forks...)
-	sort.Slice(forks, func(i, j int) bool { return forks[i].ID < forks[j].ID })
-	for _, f := range forks {
-		sb.WriteString("Fork-ID: ")
-		sb.WriteString(f.ID)
+	for _, l := range body.forkLines {
+		sb.WriteString(l)
 		sb.WriteString("\n")
-		paths := append([]string(nil), f.ConflictingPath...)
-		sort.Strings(paths)
-		for _, pid := range paths {
-			sb.WriteString("Conflicting-Path: ")
-			sb.WriteString(pid)
-			sb.WriteString("\n")
-		}
 	}
 	sb.WriteString("\n")
 
 	// EXCLUSIONS
 	sb.WriteString("EXCLUSIONS\n")
-	ex := append([]resolver.Exclusion(nil), res.Exclusions...)
+	ex := append([]resolver.Exclusion(nil), body.exclusions...)
 	sort.SliceStable(ex, func(i, j int) bool {
 		if ex[i].CID == ex[j].CID {
 			if ex[i].InputHash == ex[j].InputHash {
@@ -284,10 +391,11 @@ func renderWithCryptoLines(
 
 	// VERDICTS
 	sb.WriteString("VERDICTS\n")
-	verdicts := append([]resolver.Verdict(nil), res.Verdicts...)
+	verdicts := append([]resolver.Verdict(nil), body.verdicts...)
 	for i := range verdicts {
 		verdicts[i].TrustRoles = uniqueSorted(verdicts[i].TrustRoles)
 		verdicts[i].RevokedBy = uniqueSorted(verdicts[i].RevokedBy)
+		verdicts[i].SignerKeys = uniqueSorted(verdicts[i].SignerKeys)
 		verdicts[i].Reasons = uniqueSorted(verdicts[i].Reasons)
 		if len(verdicts[i].Reasons) == 0 && verdicts[i].ExcludedReason != "" {
 			verdicts[i].Reasons = []string{verdicts[i].ExcludedReason}
@@ -315,6 +423,11 @@ func renderWithCryptoLines(
 			sb.WriteString(v.IssuerKey)
 			sb.WriteString("\n")
 		}
+		for _, k := range v.SignerKeys {
+			sb.WriteString("Signer-Key: ")
+			sb.WriteString(k)
+			sb.WriteString("\n")
+		}
 		if v.ClaimType != "" {
 			sb.WriteString("Claim-Type: ")
 			sb.WriteString(v.ClaimType)
@@ -347,6 +460,15 @@ func renderWithCryptoLines(
 			sb.WriteString(r)
 			sb.WriteString("\n")
 		}
+		chains := make([]string, 0, len(v.Delegations))
+		for _, c := range v.Delegations {
+			chains = append(chains, formatDelegationChain(c))
+		}
+		for _, c := range uniqueSorted(chains) {
+			sb.WriteString("Delegation-Chain: ")
+			sb.WriteString(c)
+			sb.WriteString("\n")
+		}
 		for _, r := range v.Reasons {
 			sb.WriteString("Reason: ")
 			sb.WriteString(r)
@@ -374,6 +496,41 @@ func renderWithCryptoLines(
 	return []byte(sb.String())
 }
 
+// formatDelegationChain renders a VERDICTS Delegation-Chain value:
+// "<role> <root-key>" followed by one "<delegation-cid> <delegate-key>" pair
+// per link, so the last key is the signer holding the role.
+func formatDelegationChain(c resolver.DelegationChain) string {
+	if len(c.Links) == 0 {
+		return c.Role + " " + c.SignerKey
+	}
+	parts := []string{c.Role, c.Links[0].Delegator}
+	for _, l := range c.Links {
+		parts = append(parts, l.CID, l.Delegate)
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseDelegationChain is the inverse of formatDelegationChain.
+func parseDelegationChain(v string) (resolver.DelegationChain, bool) {
+	fields := strings.Split(v, " ")
+	if len(fields) < 4 || len(fields)%2 != 0 {
+		return resolver.DelegationChain{}, false
+	}
+	for _, f := range fields {
+		if f == "" {
+			return resolver.DelegationChain{}, false
+		}
+	}
+	c := resolver.DelegationChain{Role: fields[0]}
+	prev := fields[1]
+	for i := 2; i < len(fields); i += 2 {
+		c.Links = append(c.Links, resolver.DelegationLink{CID: fields[i], Delegator: prev, Delegate: fields[i+1]})
+		prev = fields[i+1]
+	}
+	c.SignerKey = prev
+	return c, true
+}
+
 func uniqueSorted(items []string) []string {
 	if len(items) == 0 {
 		return nil
@@ -430,7 +587,10 @@ func verdictLessV2(a, b resolver.Verdict) bool {
 	if strings.Join(a.Reasons, ",") != strings.Join(b.Reasons, ",") {
 		return strings.Join(a.Reasons, ",") < strings.Join(b.Reasons, ",")
 	}
-	return strings.Join(a.RevokedBy, ",") < strings.Join(b.RevokedBy, ",")
+	if strings.Join(a.RevokedBy, ",") != strings.Join(b.RevokedBy, ",") {
+		return strings.Join(a.RevokedBy, ",") < strings.Join(b.RevokedBy, ",")
+	}
+	return strings.Join(a.SignerKeys, ",") < strings.Join(b.SignerKeys, ",")
 }
 
 // RenderWithCompliance renders CROF and enforces compliance-mode constraints.
@@ -461,17 +621,12 @@ func RenderWithCompliance(res *resolver.Resolution, trustPolicyCID string, attes
 	return Render(res, trustPolicyCID, attestationCIDs, opts), nil
 }
 
-func signCROF(crofBytes []byte, privateKey ed25519.PrivateKey) (string, error) {
-	if len(privateKey) != ed25519.PrivateKeySize {
-		return "", errors.New("invalid ed25519 private key length")
-	}
+func signCROF(crofBytes []byte, signer keys.Signer, hashAlg string) (string, error) {
 	scope, err := crofSignatureScope(crofBytes)
 	if err != nil {
 		return "", err
 	}
-	digest := sha256.Sum256(scope)
-	sig := ed25519.Sign(privateKey, digest[:])
-	return base64.StdEncoding.EncodeToString(sig), nil
+	return keys.SignMessage(signer, hashAlg, scope)
 }
 
 func crofSignatureScope(crofBytes []byte) ([]byte, error) {
@@ -1,12 +1,14 @@
 package crof
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base64"
 	"strings"
 	"testing"
 
+	"xdao.co/catf/keys"
 	"xdao.co/catf/resolver"
 )
 
@@ -126,3 +128,36 @@ func TestRenderSigned_ProducesVerifiableSignature(t *testing.T) {
 		t.Fatalf("expected signed CROF")
 	}
 }
+
+func TestRenderSigned_WithSignerMatchesPrivateKeyOutput(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = 0x5A
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	signer, err := keys.NewEd25519Signer(priv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	resolverKey := keys.IssuerKeyForSigner(signer)
+
+	res := &resolver.Resolution{SubjectCID: "bafy-doc-1", State: resolver.StateResolved, Confidence: resolver.ConfidenceHigh}
+	viaSigner, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{Signer: signer})
+	if err != nil {
+		t.Fatalf("RenderSigned (Signer): %v", err)
+	}
+	viaKey, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{ResolverKey: resolverKey, PrivateKey: priv})
+	if err != nil {
+		t.Fatalf("RenderSigned (PrivateKey): %v", err)
+	}
+	if !bytes.Equal(viaSigner, viaKey) {
+		t.Fatalf("expected identical CROF bytes for Signer and PrivateKey")
+	}
+	if signed, err := VerifySignature(viaSigner); err != nil || !signed {
+		t.Fatalf("VerifySignature: signed=%v err=%v", signed, err)
+	}
+
+	if _, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{Signer: signer, ResolverKey: "ed25519:AA=="}); err == nil {
+		t.Fatalf("expected error for ResolverKey not matching signer")
+	}
+}
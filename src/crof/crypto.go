@@ -0,0 +1,31 @@
+package crof
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Supported CROF CRYPTO algorithms. These mirror the CATF algorithm set so that
+// resolver outputs remain verifiable for as long as the attestations they bind.
+var (
+	supportedSignatureAlgs = map[string]bool{"ed25519": true, "dilithium3": true}
+	supportedHashAlgs      = map[string]bool{"sha256": true, "sha512": true, "sha3-256": true}
+)
+
+// parseResolverKey decodes an algorithm-qualified Resolver-Key and checks it
+// matches sigAlg. Key length is checked by keys.VerifyMessage.
+func parseResolverKey(s, sigAlg string) ([]byte, error) {
+	alg, b64, ok := strings.Cut(s, ":")
+	if !ok || !supportedSignatureAlgs[alg] {
+		return nil, fmt.Errorf("CRYPTO: unsupported Resolver-Key %q", s)
+	}
+	if alg != sigAlg {
+		return nil, fmt.Errorf("CRYPTO: Resolver-Key algorithm %q does not match Signature-Alg %q", alg, sigAlg)
+	}
+	b, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("CRYPTO: invalid Resolver-Key encoding: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,100 @@
+package crof
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"xdao.co/catf/keys"
+	"xdao.co/catf/resolver"
+)
+
+type deterministicReader struct{}
+
+func (deterministicReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0x42
+	}
+	return len(p), nil
+}
+
+func mustDilithium3Signer(t *testing.T) keys.Signer {
+	t.Helper()
+	_, sk, err := keys.GenerateDilithium3Keypair(io.Reader(deterministicReader{}))
+	if err != nil {
+		t.Fatalf("GenerateDilithium3Keypair: %v", err)
+	}
+	s, err := keys.NewDilithium3Signer(sk)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+	return s
+}
+
+func TestRenderSigned_Dilithium3_AllHashAlgsVerify(t *testing.T) {
+	signer := mustDilithium3Signer(t)
+	res := &resolver.Resolution{SubjectCID: "bafy-doc-pq", State: resolver.StateResolved, Confidence: resolver.ConfidenceHigh}
+
+	for _, hashAlg := range []string{"sha256", "sha512", "sha3-256"} {
+		t.Run(hashAlg, func(t *testing.T) {
+			out, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{Signer: signer, HashAlg: hashAlg})
+			if err != nil {
+				t.Fatalf("RenderSigned: %v", err)
+			}
+			if _, err := CanonicalizeCROF(out); err != nil {
+				t.Fatalf("CanonicalizeCROF: %v", err)
+			}
+			body := sectionBody(out, "CRYPTO")
+			if !strings.Contains(body, "Hash-Alg: "+hashAlg+"\n") || !strings.Contains(body, "Signature-Alg: dilithium3") {
+				t.Fatalf("unexpected CRYPTO section:\n%s", body)
+			}
+			ok, err := VerifySignature(out)
+			if err != nil {
+				t.Fatalf("VerifySignature: %v", err)
+			}
+			if !ok {
+				t.Fatalf("expected signed CROF")
+			}
+
+			// Tampering with the signed scope must be detected.
+			bad := []byte(strings.Replace(string(out), "bafy-doc-pq", "bafy-doc-px", 1))
+			if ok, err := VerifySignature(bad); err == nil || ok {
+				t.Fatalf("expected tampered CROF to fail verification")
+			}
+		})
+	}
+}
+
+func TestRenderSigned_RejectsUnsupportedHashAlg(t *testing.T) {
+	signer := mustDilithium3Signer(t)
+	res := &resolver.Resolution{SubjectCID: "bafy-doc-pq", State: resolver.StateResolved, Confidence: resolver.ConfidenceHigh}
+	if _, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{Signer: signer, HashAlg: "md5"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestCanonicalizeCROF_RejectsUnsupportedOrMismatchedCryptoAlgs(t *testing.T) {
+	signer := mustDilithium3Signer(t)
+	res := &resolver.Resolution{SubjectCID: "bafy-doc-pq", State: resolver.StateResolved, Confidence: resolver.ConfidenceHigh}
+	out, err := RenderSigned(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{Signer: signer, HashAlg: "sha512"})
+	if err != nil {
+		t.Fatalf("RenderSigned: %v", err)
+	}
+
+	cases := map[string]string{
+		"hash":     strings.Replace(string(out), "Hash-Alg: sha512", "Hash-Alg: md5", 1),
+		"sig":      strings.Replace(string(out), "Signature-Alg: dilithium3", "Signature-Alg: rsa", 1),
+		"mismatch": strings.Replace(string(out), "Signature-Alg: dilithium3", "Signature-Alg: ed25519", 1),
+	}
+	for name, doc := range cases {
+		if doc == string(out) {
+			t.Fatalf("%s: failed to mutate CROF", name)
+		}
+		if _, err := CanonicalizeCROF([]byte(doc)); err == nil {
+			t.Fatalf("%s: expected CanonicalizeCROF error", name)
+		}
+		if ok, err := VerifySignature([]byte(doc)); err == nil || ok {
+			t.Fatalf("%s: expected VerifySignature error", name)
+		}
+	}
+}
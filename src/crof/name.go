@@ -0,0 +1,148 @@
+package crof
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+
+	"xdao.co/catf/compliance"
+	"xdao.co/catf/resolver"
+)
+
+// RenderName produces a canonical name-resolution CROF (Spec: xdao-crof-name-1)
+// binding a name resolution to its inputs.
+//
+// The profile shares META, INPUTS, EXCLUSIONS, VERDICTS and CRYPTO with subject
+// CROF. RESULT records Name, Version, Points-To and one Binding-CID per head
+// binding, plus Version-Selector and Considered-Version lines when a semver
+// selector chose the version; PATHS is empty; FORKS lists name forks as Conflicting-Binding CIDs.
+func RenderName(res *resolver.NameResolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) []byte {
+	resolverID := opts.ResolverID
+	if resolverID == "" {
+		resolverID = "xdao-resolver-reference"
+	}
+	render := func(cryptoLines []string) []byte {
+		return renderBody(nameBody(res), trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
+	}
+
+	if opts.Signer != nil || (opts.ResolverKey != "" && len(opts.PrivateKey) == ed25519.PrivateKeySize) {
+		if signer, resolverKey, err := signingConfig(opts); err == nil {
+			if out, err := renderSigned(render, opts, signer, resolverKey); err == nil {
+				return out
+			}
+		}
+		// Never panic in library code; fall back to unsigned output.
+	}
+	return render(nil)
+}
+
+// RenderNameSigned renders a name-resolution CROF with a required signature.
+func RenderNameSigned(res *resolver.NameResolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) ([]byte, error) {
+	signer, resolverKey, err := signingConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolverID := opts.ResolverID
+	if resolverID == "" {
+		resolverID = "xdao-resolver-reference"
+	}
+	render := func(cryptoLines []string) []byte {
+		return renderBody(nameBody(res), trustPolicyCID, attestationCIDs, resolverID, opts, cryptoLines)
+	}
+	return renderSigned(render, opts, signer, resolverKey)
+}
+
+// RenderNameWithCID renders a name-resolution CROF and returns its CID.
+func RenderNameWithCID(res *resolver.NameResolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) ([]byte, string, error) {
+	b := RenderName(res, trustPolicyCID, attestationCIDs, opts)
+	cid, err := CID(b)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, cid, nil
+}
+
+// RenderNameSignedWithCID renders a signed name-resolution CROF and returns its CID.
+func RenderNameSignedWithCID(res *resolver.NameResolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions) ([]byte, string, error) {
+	b, err := RenderNameSigned(res, trustPolicyCID, attestationCIDs, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	cid, err := CID(b)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, cid, nil
+}
+
+// RenderNameWithCompliance renders a name-resolution CROF and enforces the same
+// compliance-mode constraints as RenderWithCompliance.
+func RenderNameWithCompliance(res *resolver.NameResolution, trustPolicyCID string, attestationCIDs []string, opts RenderOptions, mode compliance.ComplianceMode) ([]byte, error) {
+	if mode == compliance.Strict {
+		if res == nil {
+			return nil, errors.New("strict mode: nil name resolution")
+		}
+		if opts.ResolverID == "" {
+			return nil, errors.New("strict mode: Resolver-ID required")
+		}
+		if len(res.Exclusions) > 0 {
+			return nil, fmt.Errorf("strict mode: exclusions present (%d)", len(res.Exclusions))
+		}
+		if len(res.Forks) > 0 {
+			return nil, fmt.Errorf("strict mode: forks present (%d)", len(res.Forks))
+		}
+		if res.State != resolver.StateResolved {
+			return nil, fmt.Errorf("strict mode: expected StateResolved, got %s", res.State)
+		}
+		if !opts.ResolvedAt.IsZero() {
+			return nil, errors.New("strict mode: Resolved-At not permitted")
+		}
+	}
+	return RenderName(res, trustPolicyCID, attestationCIDs, opts), nil
+}
+
+// nameBody builds the name-resolution (xdao-crof-name-1) profile.
+func nameBody(res *resolver.NameResolution) crofBody {
+	resultLines := []string{
+		"Name: " + res.Name,
+		"Confidence: " + string(res.Confidence),
+		"State: " + string(res.State),
+	}
+	if res.Version != "" {
+		resultLines = append(resultLines, "Version: "+res.Version)
+	}
+	if res.Selector != "" {
+		resultLines = append(resultLines, "Version-Selector: "+res.Selector)
+	}
+	for _, v := range uniqueSorted(res.ConsideredVersions) {
+		resultLines = append(resultLines, "Considered-Version: "+v)
+	}
+	if res.PointsTo != "" {
+		resultLines = append(resultLines, "Points-To: "+res.PointsTo)
+	}
+	for _, b := range uniqueSorted(res.Bindings) {
+		resultLines = append(resultLines, "Binding-CID: "+b)
+	}
+	resultLines = append(resultLines, policyVerdictLines(res.PolicyVerdicts)...)
+
+	var forkLines []string
+	forks := append([]resolver.NameFork(nil), res.Forks...)
+	sort.Slice(forks, func(i, j int) bool { return forks[i].ID < forks[j].ID })
+	for _, f := range forks {
+		forkLines = append(forkLines, "Fork-ID: "+f.ID)
+		for _, b := range uniqueSorted(f.ConflictingBinding) {
+			forkLines = append(forkLines, "Conflicting-Binding: "+b)
+		}
+	}
+
+	return crofBody{
+		spec:        specName,
+		resultLines: resultLines,
+		forkLines:   forkLines,
+		exclusions:  res.Exclusions,
+		verdicts:    res.Verdicts,
+		asOf:        res.AsOf,
+	}
+}
@@ -0,0 +1,192 @@
+package crof
+
+import (
+	"strings"
+	"testing"
+
+	"xdao.co/catf/keys"
+	"xdao.co/catf/resolver"
+)
+
+func nameFixture(t *testing.T, pointsTo ...string) ([][]byte, []byte, []string) {
+	t.Helper()
+	pub, priv := mustKeypair(t, 0xB1)
+	issuer := issuerKey(pub)
+
+	var atts [][]byte
+	var ids []string
+	for _, p := range pointsTo {
+		b := mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+			"Name":      "contracts.purchase",
+			"Points-To": p,
+			"Type":      "name-binding",
+			"Version":   "1.0.0",
+		}, issuer, priv)
+		atts = append(atts, b)
+		ids = append(ids, mustCATFCID(t, b))
+	}
+	policy := []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n\n" +
+		"TRUST\n" +
+		"Key: " + issuer + "\n" +
+		"Role: registrar\n\n" +
+		"RULES\n\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+	return atts, policy, ids
+}
+
+func TestRenderName_ResolvedProfile(t *testing.T) {
+	atts, policy, ids := nameFixture(t, "bafy-doc-1")
+	res, err := resolver.ResolveName(atts, policy, "contracts.purchase", "1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveName: %v", err)
+	}
+
+	out, cid, err := RenderNameWithCID(res, PolicyCID(policy), ids, RenderOptions{ResolverID: "resolver-name"})
+	if err != nil {
+		t.Fatalf("RenderNameWithCID: %v", err)
+	}
+	if cid == "" {
+		t.Fatalf("expected CID")
+	}
+	if _, err := CanonicalizeCROF(out); err != nil {
+		t.Fatalf("name CROF not canonical: %v", err)
+	}
+
+	result, _ := sectionLines(out, "RESULT")
+	want := []string{
+		"Binding-CID: " + ids[0],
+		"Confidence: High",
+		"Name: contracts.purchase",
+		"Points-To: bafy-doc-1",
+		"State: Resolved",
+		"Version: 1.0.0",
+	}
+	if strings.Join(result, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected RESULT:\n%s", strings.Join(result, "\n"))
+	}
+	if !strings.Contains(string(out), "Spec: xdao-crof-name-1\n") {
+		t.Fatalf("expected name profile Spec")
+	}
+	if _, err := Parse(out); err == nil {
+		t.Fatalf("expected Parse to reject name-resolution CROF")
+	}
+}
+
+func TestRenderName_ForkedProfileSigned(t *testing.T) {
+	atts, policy, ids := nameFixture(t, "bafy-doc-1", "bafy-doc-2")
+	res, err := resolver.ResolveName(atts, policy, "contracts.purchase", "1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveName: %v", err)
+	}
+	if res.State != resolver.StateForked {
+		t.Fatalf("expected Forked, got %s", res.State)
+	}
+
+	_, priv := mustKeypair(t, 0x5B)
+	signer, err := keys.NewEd25519Signer(priv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	out, err := RenderNameSigned(res, PolicyCID(policy), ids, RenderOptions{ResolverID: "resolver-name", Signer: signer})
+	if err != nil {
+		t.Fatalf("RenderNameSigned: %v", err)
+	}
+	ok, err := VerifySignature(out)
+	if err != nil || !ok {
+		t.Fatalf("VerifySignature: ok=%v err=%v", ok, err)
+	}
+
+	forks, _ := sectionLines(out, "FORKS")
+	if len(forks) != 3 || forks[0] != "Fork-ID: name-fork-1" || !strings.HasPrefix(forks[1], "Conflicting-Binding: ") {
+		t.Fatalf("unexpected FORKS: %v", forks)
+	}
+	if got, _ := sectionLines(out, "PATHS"); len(got) != 0 {
+		t.Fatalf("expected empty PATHS, got %v", got)
+	}
+}
+
+func TestCanonicalizeCROF_NameProfileRejectsSubjectFields(t *testing.T) {
+	atts, policy, ids := nameFixture(t, "bafy-doc-1")
+	res, err := resolver.ResolveName(atts, policy, "contracts.purchase", "")
+	if err != nil {
+		t.Fatalf("ResolveName: %v", err)
+	}
+	out := string(RenderName(res, PolicyCID(policy), ids, RenderOptions{}))
+
+	bad := strings.Replace(out, "Name: contracts.purchase\n", "Name: contracts.purchase\nSubject-CID: bafy-x\n", 1)
+	if _, err := CanonicalizeCROF([]byte(bad)); err == nil {
+		t.Fatalf("expected Subject-CID to be rejected in name profile")
+	}
+	missing := strings.Replace(out, "Name: contracts.purchase\n", "", 1)
+	if _, err := CanonicalizeCROF([]byte(missing)); err == nil {
+		t.Fatalf("expected missing Name to be rejected")
+	}
+}
+
+func TestValidateSupersession_NameProfile(t *testing.T) {
+	atts, policy, ids := nameFixture(t, "bafy-doc-1")
+	res, err := resolver.ResolveName(atts, policy, "contracts.purchase", "1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveName: %v", err)
+	}
+	opts := RenderOptions{ResolverID: "resolver-name"}
+	oldCROF, oldCID, err := RenderNameWithCID(res, PolicyCID(policy), ids, opts)
+	if err != nil {
+		t.Fatalf("RenderNameWithCID: %v", err)
+	}
+
+	opts.SupersedesCROFCID = oldCID
+	newCROF := RenderName(res, PolicyCID(policy), ids, opts)
+	if err := ValidateSupersession(newCROF, oldCROF); err != nil {
+		t.Fatalf("ValidateSupersession: %v", err)
+	}
+
+	other := *res
+	other.Version = "2.0.0"
+	mismatch := RenderName(&other, PolicyCID(policy), ids, opts)
+	if err := ValidateSupersession(mismatch, oldCROF); err == nil || !strings.Contains(err.Error(), "version mismatch") {
+		t.Fatalf("expected version mismatch, got %v", err)
+	}
+
+	subject := Render(&resolver.Resolution{SubjectCID: "name:contracts.purchase@1.0.0", State: res.State, Confidence: res.Confidence}, PolicyCID(policy), ids, opts)
+	if err := ValidateSupersession(subject, oldCROF); err == nil || !strings.Contains(err.Error(), "spec mismatch") {
+		t.Fatalf("expected spec mismatch, got %v", err)
+	}
+}
+
+func TestRenderName_SelectorLines(t *testing.T) {
+	atts, policy, ids := nameFixture(t, "bafy-doc-1")
+	res, err := resolver.ResolveNameSelect(atts, policy, "contracts.purchase", resolver.LatestVersion())
+	if err != nil {
+		t.Fatalf("ResolveNameSelect: %v", err)
+	}
+	opts := RenderOptions{ResolverID: "resolver-name"}
+	out, cid, err := RenderNameWithCID(res, PolicyCID(policy), ids, opts)
+	if err != nil {
+		t.Fatalf("RenderNameWithCID: %v", err)
+	}
+	if _, err := CanonicalizeCROF(out); err != nil {
+		t.Fatalf("selector CROF not canonical: %v", err)
+	}
+	for _, want := range []string{"Considered-Version: 1.0.0\n", "Version-Selector: latest\n", "Version: 1.0.0\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("expected %q in RESULT", want)
+		}
+	}
+
+	// A selector CROF may only be superseded by one recording the same selector.
+	opts.SupersedesCROFCID = cid
+	exact, err := resolver.ResolveName(atts, policy, "contracts.purchase", "1.0.0")
+	if err != nil {
+		t.Fatalf("ResolveName: %v", err)
+	}
+	if err := ValidateSupersession(RenderName(exact, PolicyCID(policy), ids, opts), out); err == nil || !strings.Contains(err.Error(), "version selector mismatch") {
+		t.Fatalf("expected version selector mismatch, got %v", err)
+	}
+	if err := ValidateSupersession(RenderName(res, PolicyCID(policy), ids, opts), out); err != nil {
+		t.Fatalf("ValidateSupersession: %v", err)
+	}
+}
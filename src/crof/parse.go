@@ -0,0 +1,379 @@
+package crof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"xdao.co/catf/resolver"
+)
+
+// ParsedDocument is a typed view of a canonical CROF document.
+//
+// Parse guarantees that Render on the returned value reproduces the input bytes
+// exactly, so a ParsedDocument can stand in for the CROF it was read from.
+type ParsedDocument struct {
+	Meta       Meta
+	Inputs     Inputs
+	Result     Result
+	Paths      []resolver.Path
+	Forks      []resolver.Fork
+	Exclusions []resolver.Exclusion
+	Verdicts   []resolver.Verdict
+	Crypto     Crypto
+}
+
+// Meta holds the CROF META section.
+type Meta struct {
+	ResolverID        string
+	Spec              string
+	Version           string
+	ResolvedAt        time.Time // zero when omitted
+	SupersedesCROFCID string
+}
+
+// Inputs holds the CROF INPUTS section.
+type Inputs struct {
+	TrustPolicyCID  string
+	AsOf            time.Time // zero when omitted
+	AttestationCIDs []string
+	InputHashes     []string
+}
+
+// Result holds the CROF RESULT section, including Policy-Verdict evidence.
+type Result struct {
+	SubjectCID     string
+	State          resolver.State
+	Confidence     resolver.Confidence
+	PolicyVerdicts []resolver.PolicyVerdict
+}
+
+// Crypto holds the CROF CRYPTO section. All fields are empty for unsigned CROF.
+type Crypto struct {
+	HashAlg      string
+	ResolverKey  string
+	SignatureAlg string
+	Signature    string
+}
+
+// Signed reports whether the CRYPTO section is populated.
+func (c Crypto) Signed() bool {
+	return c.Signature != ""
+}
+
+// Parse parses canonical CROF bytes into a typed document.
+//
+// Input must be canonical subject CROF (see CanonicalizeCROF); name-resolution
+// CROF (Spec: xdao-crof-name-1) is rejected. Parse additionally
+// re-renders the typed value and rejects inputs whose bytes it cannot reproduce,
+// so Render(Parse(x)) == x holds for every accepted x.
+func Parse(crofBytes []byte) (*ParsedDocument, error) {
+	canon, err := CanonicalizeCROF(crofBytes)
+	if err != nil {
+		return nil, fmt.Errorf("canonical CROF required: %w", err)
+	}
+
+	sections := make(map[string][]string, len(crofSectionOrder))
+	for _, sec := range crofSectionOrder {
+		body, err := sectionLines(canon, sec)
+		if err != nil {
+			return nil, err
+		}
+		sections[sec] = body
+	}
+
+	if metaSpec(sections["META"]) == specName {
+		return nil, errors.New("name-resolution CROF is not a subject CROF")
+	}
+
+	p := &ParsedDocument{}
+	if err := p.parseMeta(sections["META"]); err != nil {
+		return nil, err
+	}
+	p.parseInputs(sections["INPUTS"])
+	if err := p.parseResult(sections["RESULT"]); err != nil {
+		return nil, err
+	}
+	p.parsePaths(sections["PATHS"])
+	p.parseForks(sections["FORKS"])
+	p.parseExclusions(sections["EXCLUSIONS"])
+	p.parseVerdicts(sections["VERDICTS"])
+	p.parseCrypto(sections["CRYPTO"])
+
+	if !bytes.Equal(p.Render(), canon) {
+		return nil, errors.New("CROF not reproducible from parsed fields")
+	}
+	return p, nil
+}
+
+// Render renders the typed document back to canonical CROF bytes.
+func (p *ParsedDocument) Render() []byte {
+	inputs := make([]string, 0, len(p.Inputs.AttestationCIDs)+len(p.Inputs.InputHashes))
+	inputs = append(inputs, p.Inputs.AttestationCIDs...)
+	inputs = append(inputs, p.Inputs.InputHashes...)
+
+	var cryptoLines []string
+	if p.Crypto != (Crypto{}) {
+		cryptoLines = []string{
+			"Hash-Alg: " + p.Crypto.HashAlg,
+			"Resolver-Key: " + p.Crypto.ResolverKey,
+			"Signature-Alg: " + p.Crypto.SignatureAlg,
+			"Signature: " + p.Crypto.Signature,
+		}
+		sort.Strings(cryptoLines)
+	}
+
+	opts := RenderOptions{
+		ResolverID:        p.Meta.ResolverID,
+		ResolvedAt:        p.Meta.ResolvedAt,
+		SupersedesCROFCID: p.Meta.SupersedesCROFCID,
+	}
+	return renderWithCryptoLines(p.Resolution(), p.Inputs.TrustPolicyCID, inputs, p.Meta.ResolverID, opts, cryptoLines)
+}
+
+// Resolution returns the resolver view of the parsed document.
+func (p *ParsedDocument) Resolution() *resolver.Resolution {
+	return &resolver.Resolution{
+		SubjectCID:     p.Result.SubjectCID,
+		State:          p.Result.State,
+		Confidence:     p.Result.Confidence,
+		Paths:          p.Paths,
+		Forks:          p.Forks,
+		Exclusions:     p.Exclusions,
+		Verdicts:       p.Verdicts,
+		PolicyVerdicts: p.Result.PolicyVerdicts,
+		AsOf:           p.Inputs.AsOf,
+	}
+}
+
+func (p *ParsedDocument) parseMeta(body []string) error {
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Resolver-ID":
+			p.Meta.ResolverID = v
+		case "Spec":
+			p.Meta.Spec = v
+		case "Version":
+			p.Meta.Version = v
+		case "Resolved-At":
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fmt.Errorf("META: invalid Resolved-At: %w", err)
+			}
+			p.Meta.ResolvedAt = t
+		case "Supersedes-CROF-CID":
+			p.Meta.SupersedesCROFCID = v
+		default:
+			return fmt.Errorf("META: unknown key %q", k)
+		}
+	}
+	return nil
+}
+
+func (p *ParsedDocument) parseInputs(body []string) {
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Trust-Policy-CID":
+			p.Inputs.TrustPolicyCID = v
+		case "As-Of":
+			// Format already checked by CanonicalizeCROF.
+			p.Inputs.AsOf, _ = time.Parse(time.RFC3339, v)
+		case "Attestation-CID":
+			p.Inputs.AttestationCIDs = append(p.Inputs.AttestationCIDs, v)
+		case "Input-Hash":
+			p.Inputs.InputHashes = append(p.Inputs.InputHashes, v)
+		}
+	}
+}
+
+func (p *ParsedDocument) parseResult(body []string) error {
+	type typeRole struct{ typ, role string }
+	type entry struct {
+		tr    typeRole
+		value string
+	}
+	var issuerKeys, reasons []entry
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Subject-CID":
+			p.Result.SubjectCID = v
+		case "State":
+			p.Result.State = resolver.State(v)
+		case "Confidence":
+			p.Result.Confidence = resolver.Confidence(v)
+		case "Policy-Verdict":
+			f := policyFields(v, "Satisfied")
+			quorum, err := strconv.Atoi(f["Quorum"])
+			if err != nil {
+				return fmt.Errorf("RESULT: invalid Quorum: %w", err)
+			}
+			observed, err := strconv.Atoi(f["Observed"])
+			if err != nil {
+				return fmt.Errorf("RESULT: invalid Observed: %w", err)
+			}
+			p.Result.PolicyVerdicts = append(p.Result.PolicyVerdicts, resolver.PolicyVerdict{
+				Type:      f["Type"],
+				Role:      f["Role"],
+				Quorum:    quorum,
+				Observed:  observed,
+				Satisfied: f["Satisfied"] == "true",
+			})
+		case "Policy-Issuer-Key":
+			f := policyFields(v, "Issuer-Key")
+			issuerKeys = append(issuerKeys, entry{typeRole{f["Type"], f["Role"]}, f["Issuer-Key"]})
+		case "Policy-Verdict-Reason":
+			f := policyFields(v, "Reason")
+			reasons = append(reasons, entry{typeRole{f["Type"], f["Role"]}, f["Reason"]})
+		}
+	}
+
+	// Issuer keys and reasons are keyed by Type/Role only, so they belong to
+	// every Policy-Verdict with that Type/Role.
+	for i := range p.Result.PolicyVerdicts {
+		pv := &p.Result.PolicyVerdicts[i]
+		tr := typeRole{pv.Type, pv.Role}
+		for _, e := range issuerKeys {
+			if e.tr == tr {
+				pv.IssuerKeys = append(pv.IssuerKeys, e.value)
+			}
+		}
+		for _, e := range reasons {
+			if e.tr == tr {
+				pv.Reasons = append(pv.Reasons, e.value)
+			}
+		}
+		pv.IssuerKeys = uniqueSorted(pv.IssuerKeys)
+		pv.Reasons = uniqueSorted(pv.Reasons)
+	}
+	return nil
+}
+
+// policyFields splits a "K=V; K=V" RESULT value. The last field (named by
+// tail) takes the remainder of the line so free text may contain "; ".
+func policyFields(value, tail string) map[string]string {
+	out := make(map[string]string)
+	rest := value
+	for rest != "" {
+		k, v, _ := strings.Cut(rest, "=")
+		if k == tail {
+			out[k] = v
+			break
+		}
+		v, rest, _ = strings.Cut(v, "; ")
+		out[k] = v
+	}
+	return out
+}
+
+func (p *ParsedDocument) parsePaths(body []string) {
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Path-ID":
+			p.Paths = append(p.Paths, resolver.Path{ID: v})
+		case "Attestation-CID":
+			last := &p.Paths[len(p.Paths)-1]
+			last.CIDs = append(last.CIDs, v)
+		}
+	}
+}
+
+func (p *ParsedDocument) parseForks(body []string) {
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Fork-ID":
+			p.Forks = append(p.Forks, resolver.Fork{ID: v})
+		case "Conflicting-Path":
+			last := &p.Forks[len(p.Forks)-1]
+			last.ConflictingPath = append(last.ConflictingPath, v)
+		}
+	}
+}
+
+func (p *ParsedDocument) parseExclusions(body []string) {
+	var cur resolver.Exclusion
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Attestation-CID":
+			cur.CID = v
+		case "Input-Hash":
+			cur.InputHash = v
+		case "Reason":
+			cur.Reason = v
+			p.Exclusions = append(p.Exclusions, cur)
+			cur = resolver.Exclusion{}
+		}
+	}
+}
+
+func (p *ParsedDocument) parseVerdicts(body []string) {
+	var cur *resolver.Verdict
+	inHeader := false
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		// Each record opens with Attestation-CID and/or Input-Hash (see validateVerdicts).
+		isHeader := k == "Attestation-CID" || k == "Input-Hash"
+		if isHeader && !inHeader {
+			p.Verdicts = append(p.Verdicts, resolver.Verdict{})
+			cur = &p.Verdicts[len(p.Verdicts)-1]
+		}
+		inHeader = isHeader
+		switch k {
+		case "Attestation-CID":
+			cur.CID = v
+		case "Input-Hash":
+			cur.InputHash = v
+		case "Attested-Subject-CID":
+			cur.AttestedSubjectCID = v
+		case "Issuer-Key":
+			cur.IssuerKey = v
+		case "Signer-Key":
+			cur.SignerKeys = append(cur.SignerKeys, v)
+		case "Claim-Type":
+			cur.ClaimType = v
+		case "Status":
+			cur.Status = resolver.VerdictStatus(v)
+		case "Trusted":
+			cur.Trusted = v == "true"
+		case "Revoked":
+			cur.Revoked = v == "true"
+		case "Revoked-By":
+			cur.RevokedBy = append(cur.RevokedBy, v)
+		case "Trust-Role":
+			cur.TrustRoles = append(cur.TrustRoles, v)
+		case "Delegation-Chain":
+			if c, ok := parseDelegationChain(v); ok {
+				cur.Delegations = append(cur.Delegations, c)
+			}
+		case "Reason":
+			cur.Reasons = append(cur.Reasons, v)
+		case "Excluded-Reason":
+			cur.ExcludedReason = v
+		}
+	}
+}
+
+func (p *ParsedDocument) parseCrypto(body []string) {
+	for _, l := range body {
+		k, v, _ := strings.Cut(l, ": ")
+		switch k {
+		case "Hash-Alg":
+			p.Crypto.HashAlg = v
+		case "Resolver-Key":
+			p.Crypto.ResolverKey = v
+		case "Signature-Alg":
+			p.Crypto.SignatureAlg = v
+		case "Signature":
+			p.Crypto.Signature = v
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package crof
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"xdao.co/catf/keys"
+	"xdao.co/catf/resolver"
+)
+
+func TestParse_RoundTripsConformanceVectors(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "testdata", "conformance", "resolver", "*", "*.crof"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no CROF conformance vectors found")
+	}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("read %s: %v", p, err)
+		}
+		doc, err := Parse(b)
+		if err != nil {
+			t.Fatalf("Parse %s: %v", p, err)
+		}
+		if !bytes.Equal(doc.Render(), b) {
+			t.Fatalf("%s: Render(Parse(x)) != x", p)
+		}
+	}
+}
+
+func TestParse_TypedFieldsAndRoundTrip(t *testing.T) {
+	subject := "bafy-doc-parse-1"
+	pubA, privA := mustKeypair(t, 0xA1)
+	pubX, privX := mustKeypair(t, 0xC3)
+	issuerA := issuerKey(pubA)
+
+	a1 := mustAttestation(t, subject, "Parse", map[string]string{"Type": "authorship", "Role": "author"}, issuerA, privA)
+	u1 := mustAttestation(t, subject, "Parse", map[string]string{"Type": "authorship", "Role": "author"}, issuerKey(pubX), privX)
+	corrupt := []byte("not a catf")
+
+	policy := []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n\n" +
+		"TRUST\n" +
+		"Key: " + issuerA + "\n" +
+		"Role: author\n\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Type: authorship\n" +
+		"  Role: author\n\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+
+	res, err := resolver.Resolve([][]byte{a1, u1, corrupt}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	_, resolverPriv := mustKeypair(t, 0x5A)
+	signer, err := keys.NewEd25519Signer(resolverPriv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	resolvedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	inputs := []string{mustCATFCID(t, a1), mustCATFCID(t, u1)}
+	for _, ex := range res.Exclusions {
+		if ex.InputHash != "" {
+			inputs = append(inputs, ex.InputHash)
+		}
+	}
+	out, err := RenderSigned(res, PolicyCID(policy), inputs, RenderOptions{
+		ResolverID:        "resolver-test",
+		ResolvedAt:        resolvedAt,
+		SupersedesCROFCID: "bafy-prior-crof",
+		Signer:            signer,
+	})
+	if err != nil {
+		t.Fatalf("RenderSigned: %v", err)
+	}
+
+	doc, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !bytes.Equal(doc.Render(), out) {
+		t.Fatalf("Render(Parse(x)) != x")
+	}
+
+	if doc.Meta.ResolverID != "resolver-test" || !doc.Meta.ResolvedAt.Equal(resolvedAt) || doc.Meta.SupersedesCROFCID != "bafy-prior-crof" {
+		t.Fatalf("unexpected META: %+v", doc.Meta)
+	}
+	if doc.Inputs.TrustPolicyCID != PolicyCID(policy) || len(doc.Inputs.AttestationCIDs) != 2 || len(doc.Inputs.InputHashes) != 1 {
+		t.Fatalf("unexpected INPUTS: %+v", doc.Inputs)
+	}
+	if doc.Result.SubjectCID != subject || doc.Result.State != res.State || doc.Result.Confidence != res.Confidence {
+		t.Fatalf("unexpected RESULT: %+v", doc.Result)
+	}
+	if len(doc.Result.PolicyVerdicts) != 1 {
+		t.Fatalf("expected 1 policy verdict, got %d", len(doc.Result.PolicyVerdicts))
+	}
+	pv := doc.Result.PolicyVerdicts[0]
+	if pv.Type != "authorship" || pv.Role != "author" || pv.Quorum != 1 || pv.Observed != 1 || !pv.Satisfied {
+		t.Fatalf("unexpected policy verdict: %+v", pv)
+	}
+	if !reflect.DeepEqual(pv.IssuerKeys, []string{issuerA}) {
+		t.Fatalf("unexpected policy issuer keys: %v", pv.IssuerKeys)
+	}
+	if len(doc.Paths) != len(res.Paths) || len(doc.Exclusions) != len(res.Exclusions) || len(doc.Verdicts) != len(res.Verdicts) {
+		t.Fatalf("unexpected record counts: paths=%d exclusions=%d verdicts=%d", len(doc.Paths), len(doc.Exclusions), len(doc.Verdicts))
+	}
+	if !doc.Crypto.Signed() || doc.Crypto.ResolverKey != keys.IssuerKeyForSigner(signer) || doc.Crypto.SignatureAlg != "ed25519" {
+		t.Fatalf("unexpected CRYPTO: %+v", doc.Crypto)
+	}
+
+	// The typed view re-renders to the same bytes through the public Render entry point.
+	again, err := RenderSigned(doc.Resolution(), doc.Inputs.TrustPolicyCID, append(doc.Inputs.AttestationCIDs, doc.Inputs.InputHashes...), RenderOptions{
+		ResolverID:        doc.Meta.ResolverID,
+		ResolvedAt:        doc.Meta.ResolvedAt,
+		SupersedesCROFCID: doc.Meta.SupersedesCROFCID,
+		Signer:            signer,
+	})
+	if err != nil {
+		t.Fatalf("RenderSigned again: %v", err)
+	}
+	if !bytes.Equal(again, out) {
+		t.Fatalf("re-rendered Resolution differs from original CROF")
+	}
+}
+
+func TestParse_RejectsNonCanonicalOrIrreproducible(t *testing.T) {
+	res := &resolver.Resolution{SubjectCID: "bafy-doc-1", State: resolver.StateResolved, Confidence: resolver.ConfidenceHigh}
+	out := Render(res, "bafy-policy", []string{"bafy-a1"}, RenderOptions{})
+
+	if _, err := Parse([]byte(strings.Replace(string(out), "\n", "\r\n", 1))); err == nil {
+		t.Fatalf("expected non-canonical CROF to be rejected")
+	}
+
+	// Canonical per the validator, but META carries a key Render never emits.
+	extra := []byte(strings.Replace(string(out), "Spec: xdao-crof-1\n", "Spec: xdao-crof-1\nUnknown: x\n", 1))
+	if _, err := CanonicalizeCROF(extra); err != nil {
+		t.Fatalf("expected validator to accept extra META key: %v", err)
+	}
+	if _, err := Parse(extra); err == nil {
+		t.Fatalf("expected irreproducible CROF to be rejected")
+	}
+}
@@ -22,7 +22,8 @@ func SupersedesCROFCID(crofBytes []byte) (string, bool, error) {
 //
 // A CROF B supersedes CROF A when:
 // - B's META includes Supersedes-CROF-CID equal to CID(A)
-// - B and A bind the same Subject-CID
+// - B and A use the same Spec (CROF profile)
+// - B and A bind the same Subject-CID, or for name-resolution CROF the same Name and Version
 // - B and A use the same Resolver-ID
 // - B and A use the same Trust-Policy-CID
 func ValidateSupersession(newCROF, oldCROF []byte) error {
@@ -58,16 +59,34 @@ func ValidateSupersession(newCROF, oldCROF []byte) error {
 		return fmt.Errorf("supersession invalid: Supersedes-CROF-CID=%q does not match old CID=%q", sup, oldCID)
 	}
 
-	oldSubject, err := requiredFieldFromSection(oldCanon, "RESULT", "Subject-CID")
+	oldSpec, err := requiredFieldFromSection(oldCanon, "META", "Spec")
 	if err != nil {
 		return err
 	}
-	newSubject, err := requiredFieldFromSection(newCanon, "RESULT", "Subject-CID")
+	newSpec, err := requiredFieldFromSection(newCanon, "META", "Spec")
 	if err != nil {
 		return err
 	}
-	if oldSubject != newSubject {
-		return fmt.Errorf("supersession invalid: subject mismatch old=%q new=%q", oldSubject, newSubject)
+	if oldSpec != newSpec {
+		return fmt.Errorf("supersession invalid: spec mismatch old=%q new=%q", oldSpec, newSpec)
+	}
+
+	if newSpec == specName {
+		if err := sameNameSubject(oldCanon, newCanon); err != nil {
+			return err
+		}
+	} else {
+		oldSubject, err := requiredFieldFromSection(oldCanon, "RESULT", "Subject-CID")
+		if err != nil {
+			return err
+		}
+		newSubject, err := requiredFieldFromSection(newCanon, "RESULT", "Subject-CID")
+		if err != nil {
+			return err
+		}
+		if oldSubject != newSubject {
+			return fmt.Errorf("supersession invalid: subject mismatch old=%q new=%q", oldSubject, newSubject)
+		}
 	}
 
 	oldResolverID, err := requiredFieldFromSection(oldCanon, "META", "Resolver-ID")
@@ -97,6 +116,49 @@ func ValidateSupersession(newCROF, oldCROF []byte) error {
 	return nil
 }
 
+// sameNameSubject requires two name-resolution CROFs to resolve the same Name and
+// version request: the same Version-Selector when either CROF records one,
+// otherwise the same Version.
+func sameNameSubject(oldCanon, newCanon []byte) error {
+	oldName, err := requiredFieldFromSection(oldCanon, "RESULT", "Name")
+	if err != nil {
+		return err
+	}
+	newName, err := requiredFieldFromSection(newCanon, "RESULT", "Name")
+	if err != nil {
+		return err
+	}
+	if oldName != newName {
+		return fmt.Errorf("supersession invalid: name mismatch old=%q new=%q", oldName, newName)
+	}
+	oldSelector, _, err := singleFieldFromSection(oldCanon, "RESULT", "Version-Selector")
+	if err != nil {
+		return err
+	}
+	newSelector, _, err := singleFieldFromSection(newCanon, "RESULT", "Version-Selector")
+	if err != nil {
+		return err
+	}
+	if oldSelector != "" || newSelector != "" {
+		if oldSelector != newSelector {
+			return fmt.Errorf("supersession invalid: version selector mismatch old=%q new=%q", oldSelector, newSelector)
+		}
+		return nil
+	}
+	oldVersion, _, err := singleFieldFromSection(oldCanon, "RESULT", "Version")
+	if err != nil {
+		return err
+	}
+	newVersion, _, err := singleFieldFromSection(newCanon, "RESULT", "Version")
+	if err != nil {
+		return err
+	}
+	if oldVersion != newVersion {
+		return fmt.Errorf("supersession invalid: version mismatch old=%q new=%q", oldVersion, newVersion)
+	}
+	return nil
+}
+
 func sectionLines(crofBytes []byte, section string) ([]string, error) {
 	lines := strings.Split(string(crofBytes), "\n")
 	idx := -1
@@ -2,6 +2,7 @@ package crof
 
 import (
 	"crypto/ed25519"
+	"strings"
 	"testing"
 
 	"xdao.co/catf/catf"
@@ -258,3 +259,76 @@ func containsLine(body, line string) bool {
 	}
 	return false
 }
+
+func TestVerdicts_SignerKeysRenderedSortedAndValidated(t *testing.T) {
+	res := &resolver.Resolution{
+		SubjectCID: "bafy-doc-cosigned",
+		State:      resolver.StateResolved,
+		Confidence: resolver.ConfidenceHigh,
+		Verdicts: []resolver.Verdict{{
+			CID:        "bafy-att-1",
+			SignerKeys: []string{"ed25519:bbb", "ed25519:aaa"},
+			ClaimType:  "approval",
+			Status:     resolver.VerdictTrusted,
+			Trusted:    true,
+			TrustRoles: []string{"party"},
+			Reasons:    []string{"Issuer trusted by policy"},
+		}},
+	}
+	b := Render(res, "bafy-policy", []string{"bafy-att-1"}, RenderOptions{})
+	if _, err := CanonicalizeCROF(b); err != nil {
+		t.Fatalf("expected canonical output, got: %v", err)
+	}
+	body := sectionBody(b, "VERDICTS")
+	want := "Signer-Key: ed25519:aaa\nSigner-Key: ed25519:bbb\nClaim-Type: approval"
+	if !strings.Contains(body, want) {
+		t.Fatalf("expected sorted Signer-Key lines before Claim-Type, got:\n%s", body)
+	}
+
+	bad := []byte(strings.Replace(string(b),
+		"Signer-Key: ed25519:aaa\nSigner-Key: ed25519:bbb\n",
+		"Signer-Key: ed25519:bbb\nSigner-Key: ed25519:aaa\n", 1))
+	if _, err := CanonicalizeCROF(bad); err == nil {
+		t.Fatalf("expected unsorted Signer-Key lines to be rejected")
+	}
+}
+
+func TestVerdicts_DelegationChainsRoundTrip(t *testing.T) {
+	chain := resolver.DelegationChain{SignerKey: "ed25519:deputy", Role: "recorder", Links: []resolver.DelegationLink{
+		{CID: "bafy-del-1", Delegator: "ed25519:root", Delegate: "ed25519:clerk"},
+		{CID: "bafy-del-2", Delegator: "ed25519:clerk", Delegate: "ed25519:deputy"},
+	}}
+	res := &resolver.Resolution{
+		SubjectCID: "bafy-doc-delegated",
+		State:      resolver.StateResolved,
+		Confidence: resolver.ConfidenceHigh,
+		Verdicts: []resolver.Verdict{{
+			CID:         "bafy-att-1",
+			IssuerKey:   "ed25519:deputy",
+			ClaimType:   "approval",
+			Status:      resolver.VerdictTrusted,
+			Trusted:     true,
+			TrustRoles:  []string{"recorder"},
+			Reasons:     []string{"Issuer trusted by delegation"},
+			Delegations: []resolver.DelegationChain{chain},
+		}},
+	}
+	b := Render(res, "bafy-policy", []string{"bafy-att-1"}, RenderOptions{})
+	body := sectionBody(b, "VERDICTS")
+	if !containsLine(body, "Delegation-Chain: recorder ed25519:root bafy-del-1 ed25519:clerk bafy-del-2 ed25519:deputy") {
+		t.Fatalf("missing Delegation-Chain line:\n%s", body)
+	}
+	doc, err := Parse(b)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got := doc.Verdicts[0].Delegations
+	if len(got) != 1 || got[0].SignerKey != chain.SignerKey || got[0].Role != chain.Role || len(got[0].Links) != 2 || got[0].Links[1] != chain.Links[1] {
+		t.Fatalf("Delegations not round-tripped: %+v", got)
+	}
+
+	bad := []byte(strings.Replace(string(b), "bafy-del-2 ed25519:deputy\n", "bafy-del-2\n", 1))
+	if _, err := CanonicalizeCROF(bad); err == nil {
+		t.Fatalf("expected malformed Delegation-Chain to be rejected")
+	}
+}
@@ -1,12 +1,11 @@
 package crof
 
 import (
-	"crypto/ed25519"
-	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"strings"
+
+	"xdao.co/catf/keys"
 )
 
 // VerifySignature verifies the CROF CRYPTO signature, if present.
@@ -51,14 +50,14 @@ func VerifySignature(crofBytes []byte) (bool, error) {
 	if !(hasKey && hasAlg && hasHash && hasSig) {
 		return false, errors.New("CRYPTO: incomplete signature fields")
 	}
-	if sigAlg != "ed25519" {
+	if !supportedSignatureAlgs[sigAlg] {
 		return false, fmt.Errorf("CRYPTO: unsupported Signature-Alg %q", sigAlg)
 	}
-	if hashAlg != "sha256" {
+	if !supportedHashAlgs[hashAlg] {
 		return false, fmt.Errorf("CRYPTO: unsupported Hash-Alg %q", hashAlg)
 	}
 
-	pub, err := parseEd25519PublicKey(resolverKey)
+	pub, err := parseResolverKey(resolverKey, sigAlg)
 	if err != nil {
 		return false, err
 	}
@@ -67,33 +66,13 @@ func VerifySignature(crofBytes []byte) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("CRYPTO: invalid Signature encoding: %w", err)
 	}
-	if len(sig) != ed25519.SignatureSize {
-		return false, errors.New("CRYPTO: invalid Signature length")
-	}
 
 	scope, err := crofSignatureScope(canon)
 	if err != nil {
 		return false, err
 	}
-	digest := sha256.Sum256(scope)
-	if !ed25519.Verify(pub, digest[:], sig) {
-		return false, errors.New("CRYPTO: signature did not verify")
+	if err := keys.VerifyMessage(sigAlg, hashAlg, pub, scope, sig); err != nil {
+		return false, fmt.Errorf("CRYPTO: %w", err)
 	}
 	return true, nil
 }
-
-func parseEd25519PublicKey(s string) (ed25519.PublicKey, error) {
-	const prefix = "ed25519:"
-	if !strings.HasPrefix(s, prefix) {
-		return nil, fmt.Errorf("CRYPTO: unsupported Resolver-Key %q", s)
-	}
-	b64 := strings.TrimPrefix(s, prefix)
-	b, err := base64.StdEncoding.DecodeString(b64)
-	if err != nil {
-		return nil, fmt.Errorf("CRYPTO: invalid Resolver-Key encoding: %w", err)
-	}
-	if len(b) != ed25519.PublicKeySize {
-		return nil, errors.New("CRYPTO: invalid Resolver-Key length")
-	}
-	return ed25519.PublicKey(b), nil
-}
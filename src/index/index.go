@@ -0,0 +1,311 @@
+// Package index maintains a persistent, local index of CATF attestations.
+//
+// Attestation bytes live in a storage.CAS; the index only records, per
+// attestation CID, the fields it can be queried by: Subject CID, Issuer-Key,
+// claim Type, Name, and the CIDs and keys referenced from CLAIMS (Supersedes,
+// Target-Attestation, Points-To; Old-Key, New-Key, Revoked-Key,
+// Delegator-Key, Delegate-Key).
+//
+// On disk the index is a directory holding one JSON entry file per
+// attestation (entries/<CID>.json). Entries are derived from content
+// addressed bytes, so adding the same attestation twice is a no-op and
+// concurrent writers cannot produce conflicting entries.
+//
+// Query results are always sorted by CID so resolver inputs built from them
+// are reproducible.
+//
+// API stability: see STABILITY.md (repository root) for Stable vs Experimental tiers.
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/storage"
+)
+
+// FormatVersion is the current entry file schema version.
+const FormatVersion = 1
+
+// ErrNoCAS is returned by Add and Get when the index was opened without a CAS.
+var ErrNoCAS = errors.New("index: no CAS configured")
+
+// referenceClaims are the CLAIMS keys whose values are indexed as references:
+// attestation and subject CIDs, and the issuer keys named by key-rotation,
+// key-revocation and delegation attestations.
+var referenceClaims = []string{
+	"Points-To", "Supersedes", "Target-Attestation",
+	"Old-Key", "New-Key", "Revoked-Key", "Delegator-Key", "Delegate-Key",
+}
+
+// Entry is the indexed view of one attestation.
+type Entry struct {
+	Format  int    `json:"format"`
+	CID     string `json:"cid"`
+	Subject string `json:"subject"`
+	// Issuers holds every signer's Issuer-Key, in canonical CRYPTO order.
+	Issuers []string `json:"issuers"`
+	Type    string   `json:"type"`
+	Name    string   `json:"name,omitempty"`
+	// References are the CLAIMS Points-To, Supersedes, Target-Attestation,
+	// Old-Key, New-Key, Revoked-Key, Delegator-Key and Delegate-Key values,
+	// sorted and de-duplicated.
+	References []string `json:"references,omitempty"`
+}
+
+// Query selects entries. Empty fields match everything; set fields must all
+// match.
+type Query struct {
+	Subject   string
+	Issuer    string
+	Type      string
+	Name      string
+	Reference string
+}
+
+// Index is an on-disk attestation index. It is safe for concurrent use.
+type Index struct {
+	dir string
+	cas storage.ContextCAS
+
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// Open opens (creating if needed) the index stored in dir.
+//
+// cas receives attestation bytes on Add and serves Get; it may be nil for a
+// query-only index.
+func Open(dir string, cas storage.CAS) (*Index, error) {
+	if dir == "" {
+		return nil, errors.New("index: empty directory")
+	}
+	entriesDir := filepath.Join(dir, "entries")
+	if err := os.MkdirAll(entriesDir, 0o755); err != nil {
+		return nil, fmt.Errorf("index: %w", err)
+	}
+	names, err := os.ReadDir(entriesDir)
+	if err != nil {
+		return nil, fmt.Errorf("index: %w", err)
+	}
+
+	x := &Index{dir: dir, cas: storage.AsContextCAS(cas), entries: make(map[string]Entry)}
+	for _, de := range names {
+		name := de.Name()
+		if de.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(entriesDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("index: %w", err)
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return nil, fmt.Errorf("index: entry %s: %w", name, err)
+		}
+		if e.Format != FormatVersion {
+			return nil, fmt.Errorf("index: entry %s: unsupported format %d", name, e.Format)
+		}
+		if e.CID+".json" != name {
+			return nil, fmt.Errorf("index: entry %s: CID mismatch", name)
+		}
+		x.entries[e.CID] = e
+	}
+	return x, nil
+}
+
+// Add verifies attestation bytes, stores them in the CAS and indexes them.
+//
+// The bytes must parse as canonical CATF and every signature must verify.
+// Adding an already indexed attestation returns its existing entry; an entry
+// written before its key references were indexed is rewritten.
+func (x *Index) Add(ctx context.Context, attestation []byte) (Entry, error) {
+	if x.cas == nil {
+		return Entry{}, ErrNoCAS
+	}
+	doc, err := catf.Parse(attestation)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := doc.Verify(); err != nil {
+		return Entry{}, err
+	}
+	e, err := entryFor(doc)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	id, err := x.cas.PutContext(ctx, attestation)
+	if err != nil {
+		return Entry{}, fmt.Errorf("index: store attestation: %w", err)
+	}
+	if id.String() != e.CID {
+		return Entry{}, storage.ErrCIDMismatch
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if existing, ok := x.entries[e.CID]; ok && equalStrings(existing.References, e.References) {
+		return existing, nil
+	}
+	if err := x.writeEntry(e); err != nil {
+		return Entry{}, err
+	}
+	x.entries[e.CID] = e
+	return e, nil
+}
+
+// Get returns the attestation bytes for an indexed CID from the CAS.
+func (x *Index) Get(ctx context.Context, id cid.Cid) ([]byte, error) {
+	if x.cas == nil {
+		return nil, ErrNoCAS
+	}
+	return x.cas.GetContext(ctx, id)
+}
+
+// Query returns the entries matching q, sorted by CID.
+func (x *Index) Query(q Query) []Entry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var out []Entry
+	for _, e := range x.entries {
+		if q.matches(e) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CID < out[j].CID })
+	return out
+}
+
+// BySubject returns the CIDs of attestations about subjectCID, sorted.
+//
+// Together with ByReference it implements resolver.AttestationIndex.
+func (x *Index) BySubject(_ context.Context, subjectCID string) ([]cid.Cid, error) {
+	return cids(x.Query(Query{Subject: subjectCID}))
+}
+
+// ByReference returns the CIDs of attestations whose CLAIMS reference target,
+// sorted. target is an attestation or subject CID, or an issuer key.
+func (x *Index) ByReference(_ context.Context, target string) ([]cid.Cid, error) {
+	return cids(x.Query(Query{Reference: target}))
+}
+
+func (q Query) matches(e Entry) bool {
+	if q.Subject != "" && e.Subject != q.Subject {
+		return false
+	}
+	if q.Type != "" && e.Type != q.Type {
+		return false
+	}
+	if q.Name != "" && e.Name != q.Name {
+		return false
+	}
+	if q.Issuer != "" && !contains(e.Issuers, q.Issuer) {
+		return false
+	}
+	if q.Reference != "" && !contains(e.References, q.Reference) {
+		return false
+	}
+	return true
+}
+
+func entryFor(doc *catf.CATF) (Entry, error) {
+	id, err := doc.CID()
+	if err != nil {
+		return Entry{}, err
+	}
+	signers, err := doc.SignatureEntries()
+	if err != nil {
+		return Entry{}, err
+	}
+	e := Entry{
+		Format:  FormatVersion,
+		CID:     id,
+		Subject: doc.SubjectCID(),
+		Type:    doc.ClaimType(),
+		Issuers: make([]string, 0, len(signers)),
+	}
+	for _, s := range signers {
+		e.Issuers = append(e.Issuers, s.IssuerKey)
+	}
+	claims := doc.Sections["CLAIMS"].Pairs
+	e.Name = claims["Name"]
+	for _, k := range referenceClaims {
+		if v := claims[k]; v != "" && !contains(e.References, v) {
+			e.References = append(e.References, v)
+		}
+	}
+	sort.Strings(e.References)
+	return e, nil
+}
+
+// writeEntry writes e atomically (temp file and rename).
+func (x *Index) writeEntry(e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(x.dir, "entries", e.CID+".json")
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("index: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, werr := tmp.Write(append(b, '\n'))
+	cerr := tmp.Close()
+	if werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmpPath, dst)
+	}
+	if werr != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("index: write entry: %w", werr)
+	}
+	return nil
+}
+
+func cids(entries []Entry) ([]cid.Cid, error) {
+	out := make([]cid.Cid, 0, len(entries))
+	for _, e := range entries {
+		id, err := cid.Decode(e.CID)
+		if err != nil {
+			return nil, fmt.Errorf("index: entry %s: %w", e.CID, err)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
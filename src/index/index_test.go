@@ -0,0 +1,230 @@
+package index
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/resolver"
+	"xdao.co/catf/storage"
+)
+
+var _ resolver.AttestationIndex = (*Index)(nil)
+
+type mapCAS map[string][]byte
+
+func (m mapCAS) Put(b []byte) (cid.Cid, error) {
+	id, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return cid.Undef, err
+	}
+	m[id.String()] = append([]byte(nil), b...)
+	return id, nil
+}
+
+func (m mapCAS) Get(id cid.Cid) ([]byte, error) {
+	b, ok := m[id.String()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return b, nil
+}
+
+func (m mapCAS) Has(id cid.Cid) bool {
+	_, ok := m[id.String()]
+	return ok
+}
+
+func mustAttestation(t *testing.T, seed byte, subject string, claims map[string]string) []byte {
+	t.Helper()
+	s := make([]byte, ed25519.SeedSize)
+	for i := range s {
+		s[i] = seed
+	}
+	priv := ed25519.NewKeyFromSeed(s)
+	issuer := "ed25519:" + base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey))
+	doc := catf.Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": subject, "Description": "index test"},
+		Claims:  claims,
+		Crypto: map[string]string{
+			"Hash-Alg":      "sha256",
+			"Issuer-Key":    issuer,
+			"Signature":     "0",
+			"Signature-Alg": "ed25519",
+		},
+	}
+	pre, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	parsed, err := catf.Parse(pre)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	doc.Crypto["Signature"] = keys.SignEd25519SHA256(parsed.SignedBytes(), priv)
+	out, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return out
+}
+
+func TestIndex_AddQueryReopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cas := mapCAS{}
+
+	x, err := Open(dir, cas)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	approval := mustAttestation(t, 1, "bafy-doc", map[string]string{"Effective-Date": "2026-01-10", "Role": "buyer", "Type": "approval"})
+	a, err := x.Add(ctx, approval)
+	if err != nil {
+		t.Fatalf("Add approval: %v", err)
+	}
+	revocation := mustAttestation(t, 2, "bafy-doc", map[string]string{"Target-Attestation": a.CID, "Type": "revocation"})
+	r, err := x.Add(ctx, revocation)
+	if err != nil {
+		t.Fatalf("Add revocation: %v", err)
+	}
+	binding := mustAttestation(t, 1, "bafy-name", map[string]string{"Name": "pkg", "Points-To": "bafy-doc", "Type": "name-binding", "Version": "1.0.0"})
+	n, err := x.Add(ctx, binding)
+	if err != nil {
+		t.Fatalf("Add binding: %v", err)
+	}
+	if _, err := x.Add(ctx, approval); err != nil {
+		t.Fatalf("re-Add: %v", err)
+	}
+	if !cas.Has(mustDecode(t, a.CID)) {
+		t.Fatalf("attestation not stored in CAS")
+	}
+
+	// Reopen from disk and query.
+	x, err = Open(dir, nil)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := x.Query(Query{}); len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	assertCIDs(t, x.Query(Query{Subject: "bafy-doc"}), sorted(a.CID, r.CID))
+	assertCIDs(t, x.Query(Query{Type: "revocation"}), []string{r.CID})
+	assertCIDs(t, x.Query(Query{Issuer: a.Issuers[0]}), sorted(a.CID, n.CID))
+	assertCIDs(t, x.Query(Query{Name: "pkg"}), []string{n.CID})
+	assertCIDs(t, x.Query(Query{Reference: "bafy-doc"}), []string{n.CID})
+	assertCIDs(t, x.Query(Query{Subject: "bafy-doc", Issuer: a.Issuers[0]}), []string{a.CID})
+
+	refs, err := x.ByReference(ctx, a.CID)
+	if err != nil || len(refs) != 1 || refs[0].String() != r.CID {
+		t.Fatalf("ByReference = %v, %v", refs, err)
+	}
+	if _, err := x.Add(ctx, approval); err != ErrNoCAS {
+		t.Fatalf("expected ErrNoCAS, got %v", err)
+	}
+}
+
+func TestIndex_RejectsInvalidSignature(t *testing.T) {
+	x, err := Open(t.TempDir(), mapCAS{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	att := mustAttestation(t, 1, "bafy-doc", map[string]string{"Role": "author", "Type": "authorship"})
+	doc, err := catf.Parse(att)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// Re-render with the subject changed so the signature no longer matches.
+	tampered, err := catf.Render(catf.Document{
+		Meta:    doc.Sections["META"].Pairs,
+		Subject: map[string]string{"CID": "bafy-other", "Description": "index test"},
+		Claims:  doc.Sections["CLAIMS"].Pairs,
+		Crypto:  doc.Sections["CRYPTO"].Pairs,
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if _, err := x.Add(context.Background(), tampered); err == nil {
+		t.Fatalf("expected verification error")
+	}
+	if got := x.Query(Query{}); len(got) != 0 {
+		t.Fatalf("expected empty index, got %v", got)
+	}
+}
+
+func mustDecode(t *testing.T, s string) cid.Cid {
+	t.Helper()
+	id, err := cid.Decode(s)
+	if err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return id
+}
+
+func sorted(a, b string) []string {
+	if b < a {
+		return []string{b, a}
+	}
+	return []string{a, b}
+}
+
+func assertCIDs(t *testing.T, got []Entry, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %v", len(got), want)
+	}
+	for i := range got {
+		if got[i].CID != want[i] {
+			t.Fatalf("entry %d: got %s want %s", i, got[i].CID, want[i])
+		}
+	}
+}
+
+func TestIndex_KeyReferences(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	x, err := Open(dir, mapCAS{})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	a, err := x.Add(ctx, mustAttestation(t, 1, "bafy-doc", map[string]string{"Effective-Date": "2026-01-10", "Role": "buyer", "Type": "approval"}))
+	if err != nil {
+		t.Fatalf("Add approval: %v", err)
+	}
+	key := a.Issuers[0]
+	revocation := mustAttestation(t, 1, "bafy-key-revocation", map[string]string{"Revoked-Key": key, "Type": "key-revocation"})
+	r, err := x.Add(ctx, revocation)
+	if err != nil {
+		t.Fatalf("Add key-revocation: %v", err)
+	}
+	refs, err := x.ByReference(ctx, key)
+	if err != nil || len(refs) != 1 || refs[0].String() != r.CID {
+		t.Fatalf("ByReference(issuer) = %v, %v", refs, err)
+	}
+
+	// Entries written before key references were indexed are refreshed on
+	// re-Add.
+	stale := r
+	stale.References = nil
+	if err := x.writeEntry(stale); err != nil {
+		t.Fatalf("writeEntry: %v", err)
+	}
+	x, err = Open(dir, mapCAS{})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := x.Query(Query{Reference: key}); len(got) != 0 {
+		t.Fatalf("stale entry matched: %v", got)
+	}
+	if _, err := x.Add(ctx, revocation); err != nil {
+		t.Fatalf("re-Add: %v", err)
+	}
+	assertCIDs(t, x.Query(Query{Reference: key}), []string{r.CID})
+}
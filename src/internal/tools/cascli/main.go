@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -56,7 +57,7 @@ func printUsage(w io.Writer) {
 	fmt.Fprintln(w, "  cascli plugin install --plugin localfs|ipfs [--version vX.Y.Z] [--install-dir <dir>] [--os <goos>] [--arch <goarch>]")
 	fmt.Fprintln(w, "  cascli plugin list [--plugin <name>] [--with-latest] [--os <goos>] [--arch <goarch>] [--json]")
 	fmt.Fprintln(w, "  cascli plugin verify --plugin localfs|ipfs [--version vX.Y.Z] [--install-dir <dir> | --binary-path <path>] [--os <goos>] [--arch <goarch>]")
-	fmt.Fprintln(w, "  cascli put --backend grpc --grpc-target <host:port> <file>")
+	fmt.Fprintln(w, "  cascli put --backend grpc --grpc-target <host:port> [--chunked] <file>")
 	fmt.Fprintln(w, "  cascli put --cas-config <file.json> [--backend <preferred>] [--emit-backend-cids] <file>")
 	fmt.Fprintln(w, "  cascli get --backend grpc --grpc-target <host:port> --cid <cid> [--out <file>]")
 	fmt.Fprintln(w, "  cascli resolve --backend grpc --grpc-target <host:port> --subject <cid> --policy <cid> --att <cid> [--att ...] [--mode strict|permissive]")
@@ -111,7 +112,9 @@ func cmdPut(args []string, out io.Writer, errOut io.Writer) int {
 	var common commonFlags
 	common.add(fs)
 	var emitBackendCIDs bool
+	var chunked bool
 	fs.BoolVar(&emitBackendCIDs, "emit-backend-cids", false, "Emit JSON including per-backend CID map (requires write_policy=all)")
+	fs.BoolVar(&chunked, "chunked", false, "Stream the file using the chunked blob layout (prints the chunked root CID)")
 	if err := fs.Parse(args); err != nil {
 		return 2
 	}
@@ -134,6 +137,25 @@ func cmdPut(args []string, out io.Writer, errOut io.Writer) int {
 	}
 
 	p := fs.Arg(0)
+	if chunked {
+		if emitBackendCIDs {
+			fmt.Fprintln(errOut, "--chunked cannot be combined with --emit-backend-cids")
+			return 2
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			fmt.Fprintf(errOut, "read %s: %v\n", filepath.Base(p), err)
+			return 1
+		}
+		defer f.Close()
+		id, err := storage.PutReader(context.Background(), cas, f)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		_, _ = fmt.Fprintln(out, id.String())
+		return 0
+	}
 	b, err := os.ReadFile(p)
 	if err != nil {
 		fmt.Fprintf(errOut, "read %s: %v\n", filepath.Base(p), err)
@@ -223,6 +245,10 @@ func cmdGet(args []string, out io.Writer, errOut io.Writer) int {
 		return 1
 	}
 
+	if id.Type() == cid.DagJSON {
+		return getChunked(cas, id, outPath, out, errOut)
+	}
+
 	b, err := cas.Get(id)
 	if err != nil {
 		fmt.Fprintln(errOut, err)
@@ -240,6 +266,32 @@ func cmdGet(args []string, out io.Writer, errOut io.Writer) int {
 	return 0
 }
 
+// getChunked streams a chunked blob to outPath (or out) one chunk at a time.
+func getChunked(cas storage.CAS, id cid.Cid, outPath string, out io.Writer, errOut io.Writer) int {
+	rc, err := storage.GetReader(context.Background(), cas, id)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	defer rc.Close()
+
+	w := out
+	if outPath != "" {
+		f, err := os.OpenFile(outPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			fmt.Fprintf(errOut, "write %s: %v\n", outPath, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, rc); err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return 0
+}
+
 func cmdResolve(args []string, out io.Writer, errOut io.Writer) int {
 	fs := flag.NewFlagSet("resolve", flag.ContinueOnError)
 	fs.SetOutput(errOut)
@@ -6,6 +6,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
 )
 
 // GenerateIssuerKeyFromSeed returns the CATF issuer key string for an Ed25519 seed.
@@ -43,3 +45,25 @@ func DeriveRoleSeed(rootSeed []byte, role string) ([]byte, error) {
 	copy(out, sum[:ed25519.SeedSize])
 	return out, nil
 }
+
+// DeriveDilithium3Key deterministically derives a dilithium3 keypair from an
+// Ed25519 seed, so that a KMS-lite key can also sign with dilithium3.
+//
+// The dilithium3 seed is domain-separated from the seed itself, so the two
+// keys share no key material.
+func DeriveDilithium3Key(seed []byte) (*mode3.PublicKey, *mode3.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, fmt.Errorf("seed must be %d bytes", ed25519.SeedSize)
+	}
+
+	h := sha256.New()
+	_, _ = h.Write(seed)
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte("xdao-catf-kms-lite-v1"))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte("alg:dilithium3"))
+	var d [mode3.SeedSize]byte
+	copy(d[:], h.Sum(nil))
+	pub, priv := mode3.NewKeyFromSeed(&d)
+	return pub, priv, nil
+}
@@ -52,3 +52,48 @@ func TestGenerateIssuerKeyFromSeedFormat(t *testing.T) {
 		t.Fatalf("expected %d pubkey bytes, got %d", ed25519.PublicKeySize, len(pubBytes))
 	}
 }
+
+func TestDeriveDilithium3KeyDeterministic(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	pubA, privA, err := DeriveDilithium3Key(seed)
+	if err != nil {
+		t.Fatalf("DeriveDilithium3Key: %v", err)
+	}
+	pubB, _, err := DeriveDilithium3Key(seed)
+	if err != nil {
+		t.Fatalf("DeriveDilithium3Key: %v", err)
+	}
+	if !pubA.Equal(pubB) {
+		t.Fatalf("expected deterministic derivation")
+	}
+
+	seed[0] ^= 0xff
+	pubC, _, err := DeriveDilithium3Key(seed)
+	if err != nil {
+		t.Fatalf("DeriveDilithium3Key: %v", err)
+	}
+	if pubA.Equal(pubC) {
+		t.Fatalf("expected different seeds to derive different keys")
+	}
+
+	s, err := NewDilithium3Signer(privA)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+	sigB64, err := SignMessage(s, "sha256", []byte("hello"))
+	if err != nil {
+		t.Fatalf("SignMessage: %v", err)
+	}
+	sig, _ := base64.StdEncoding.DecodeString(sigB64)
+	if err := VerifyMessage("dilithium3", "sha256", pubA.Bytes(), []byte("hello"), sig); err != nil {
+		t.Fatalf("VerifyMessage: %v", err)
+	}
+
+	if _, _, err := DeriveDilithium3Key(seed[:4]); err == nil {
+		t.Fatalf("expected error for short seed")
+	}
+}
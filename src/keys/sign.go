@@ -4,7 +4,6 @@ import (
 	"crypto/ed25519"
 	"crypto/sha256"
 	"crypto/sha512"
-	"encoding/base64"
 	"fmt"
 	"io"
 
@@ -12,7 +11,9 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
-func digestFor(hashAlg string, message []byte) ([]byte, error) {
+// Digest returns hashAlg(message), the digest that Signature-Alg schemes sign.
+// hashAlg must be one of: sha256, sha512, sha3-256.
+func Digest(hashAlg string, message []byte) ([]byte, error) {
 	switch hashAlg {
 	case "sha256":
 		s := sha256.Sum256(message)
@@ -24,30 +25,28 @@ func digestFor(hashAlg string, message []byte) ([]byte, error) {
 		s := sha3.Sum256(message)
 		return s[:], nil
 	default:
-		return nil, fmt.Errorf("unsupported hash algorithm: %q", hashAlg)
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedHash, hashAlg)
 	}
 }
 
 // SignEd25519SHA256 returns a base64 signature over sha256(message).
+//
+// It is equivalent to SignMessage with an ed25519 Signer and Hash-Alg sha256.
 func SignEd25519SHA256(message []byte, privateKey ed25519.PrivateKey) string {
-	digest := sha256.Sum256(message)
-	sig := ed25519.Sign(privateKey, digest[:])
-	return base64.StdEncoding.EncodeToString(sig)
+	sig, _ := SignMessage(ed25519Signer{priv: privateKey}, "sha256", message)
+	return sig
 }
 
 // SignDilithium3 returns a base64 dilithium3 signature over hash(message).
 // hashAlg must be one of: sha256, sha512, sha3-256.
+//
+// It is equivalent to SignMessage with a dilithium3 Signer.
 func SignDilithium3(message []byte, hashAlg string, privateKey *mode3.PrivateKey) (string, error) {
-	if privateKey == nil {
-		return "", fmt.Errorf("missing private key")
-	}
-	digest, err := digestFor(hashAlg, message)
+	s, err := NewDilithium3Signer(privateKey)
 	if err != nil {
 		return "", err
 	}
-	sig := make([]byte, mode3.SignatureSize)
-	mode3.SignTo(privateKey, digest, sig)
-	return base64.StdEncoding.EncodeToString(sig), nil
+	return SignMessage(s, hashAlg, message)
 }
 
 // GenerateDilithium3Keypair returns a new Dilithium3 keypair.
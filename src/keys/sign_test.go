@@ -60,9 +60,9 @@ func TestSignDilithium3_Verifies_SHA3_256(t *testing.T) {
 		t.Fatalf("unexpected signature size: got %d want %d", len(sig), mode3.SignatureSize)
 	}
 
-	digest, err := digestFor("sha3-256", msg)
+	digest, err := Digest("sha3-256", msg)
 	if err != nil {
-		t.Fatalf("digestFor: %v", err)
+		t.Fatalf("Digest: %v", err)
 	}
 	if !mode3.Verify(pk, digest, sig) {
 		t.Fatalf("signature did not verify")
@@ -0,0 +1,195 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+)
+
+// Signer produces CATF/CROF signatures without exposing private key material.
+//
+// Implementations may hold keys in-process, in an HSM, in a cloud KMS, or behind
+// an agent. Callers compute the message digest (per Hash-Alg) and pass it to Sign;
+// the signature is always computed over the digest, never over raw bytes.
+type Signer interface {
+	// Algorithm returns the Signature-Alg identifier (e.g. "ed25519", "dilithium3").
+	Algorithm() string
+
+	// PublicKey returns the raw public key bytes.
+	PublicKey() []byte
+
+	// Sign returns the raw signature over digest.
+	Sign(digest []byte) ([]byte, error)
+}
+
+// IssuerKeyForSigner returns the algorithm-qualified key string (<alg>:<base64>)
+// used as CATF Issuer-Key and CROF Resolver-Key.
+func IssuerKeyForSigner(s Signer) string {
+	return s.Algorithm() + ":" + base64.StdEncoding.EncodeToString(s.PublicKey())
+}
+
+// SignMessage returns a base64 signature over hashAlg(message) using s.
+// hashAlg must be one of: sha256, sha512, sha3-256.
+func SignMessage(s Signer, hashAlg string, message []byte) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("missing signer")
+	}
+	digest, err := Digest(hashAlg, message)
+	if err != nil {
+		return "", err
+	}
+	sig, err := s.Sign(digest)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Errors reported by Digest, ParsePublicKey and VerifyMessage. Callers that
+// report their own error codes (such as CATF rule IDs) match them with
+// errors.Is.
+var (
+	ErrUnsupportedHash      = errors.New("unsupported hash algorithm")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+	ErrKeyEncoding          = errors.New("key is not <alg>:<base64>")
+	ErrKeyBase64            = errors.New("invalid key base64")
+	ErrPublicKey            = errors.New("invalid public key")
+	ErrSignatureLength      = errors.New("invalid signature length")
+	ErrSignatureInvalid     = errors.New("signature did not verify")
+)
+
+// ParsePublicKey decodes an algorithm-qualified public key (<alg>:<base64>, as
+// in CATF Issuer-Key and CROF Resolver-Key) and checks it is a valid key for
+// alg. Unpadded base64 is accepted.
+func ParsePublicKey(s string) (alg string, pub []byte, err error) {
+	alg, enc, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", nil, ErrKeyEncoding
+	}
+	pub, err = base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		if pub, err = base64.RawStdEncoding.DecodeString(enc); err != nil {
+			return "", nil, ErrKeyBase64
+		}
+	}
+	if err := checkPublicKey(alg, pub); err != nil {
+		return "", nil, err
+	}
+	return alg, pub, nil
+}
+
+// CheckSignatureLength reports ErrSignatureLength when sig cannot be an alg
+// signature, and ErrUnsupportedAlgorithm for an unknown alg.
+func CheckSignatureLength(alg string, sig []byte) error {
+	want := 0
+	switch alg {
+	case "ed25519":
+		want = ed25519.SignatureSize
+	case "dilithium3":
+		want = mode3.SignatureSize
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+	if len(sig) != want {
+		return fmt.Errorf("%w: %s signature must be %d bytes", ErrSignatureLength, alg, want)
+	}
+	return nil
+}
+
+func checkPublicKey(alg string, pub []byte) error {
+	want := 0
+	switch alg {
+	case "ed25519":
+		want = ed25519.PublicKeySize
+	case "dilithium3":
+		want = mode3.PublicKeySize
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+	if len(pub) != want {
+		return fmt.Errorf("%w: %s key must be %d bytes", ErrPublicKey, alg, want)
+	}
+	return nil
+}
+
+// VerifyMessage verifies a raw signature over hashAlg(message), the
+// counterpart of SignMessage. alg is the Signature-Alg (ed25519 or
+// dilithium3) and pub the raw public key bytes; hashAlg must be one of:
+// sha256, sha512, sha3-256.
+func VerifyMessage(alg, hashAlg string, pub, message, sig []byte) error {
+	digest, err := Digest(hashAlg, message)
+	if err != nil {
+		return err
+	}
+	if err := checkPublicKey(alg, pub); err != nil {
+		return err
+	}
+	if err := CheckSignatureLength(alg, sig); err != nil {
+		return err
+	}
+	switch alg {
+	case "ed25519":
+		if !ed25519.Verify(ed25519.PublicKey(pub), digest, sig) {
+			return ErrSignatureInvalid
+		}
+	case "dilithium3":
+		var pk mode3.PublicKey
+		if err := pk.UnmarshalBinary(pub); err != nil {
+			return fmt.Errorf("%w: %v", ErrPublicKey, err)
+		}
+		if !mode3.Verify(&pk, digest, sig) {
+			return ErrSignatureInvalid
+		}
+	}
+	return nil
+}
+
+type ed25519Signer struct {
+	priv ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns an in-process Signer for an ed25519 private key.
+func NewEd25519Signer(priv ed25519.PrivateKey) (Signer, error) {
+	if l := len(priv); l != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("ed25519 private key must be %d bytes, got %d", ed25519.PrivateKeySize, l)
+	}
+	return ed25519Signer{priv: priv}, nil
+}
+
+func (s ed25519Signer) Algorithm() string { return "ed25519" }
+
+func (s ed25519Signer) PublicKey() []byte {
+	return append([]byte(nil), s.priv.Public().(ed25519.PublicKey)...)
+}
+
+func (s ed25519Signer) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(s.priv, digest), nil
+}
+
+type dilithium3Signer struct {
+	priv *mode3.PrivateKey
+}
+
+// NewDilithium3Signer returns an in-process Signer for a dilithium3 private key.
+func NewDilithium3Signer(priv *mode3.PrivateKey) (Signer, error) {
+	if priv == nil {
+		return nil, fmt.Errorf("missing private key")
+	}
+	return dilithium3Signer{priv: priv}, nil
+}
+
+func (s dilithium3Signer) Algorithm() string { return "dilithium3" }
+
+func (s dilithium3Signer) PublicKey() []byte {
+	return s.priv.Public().(*mode3.PublicKey).Bytes()
+}
+
+func (s dilithium3Signer) Sign(digest []byte) ([]byte, error) {
+	sig := make([]byte, mode3.SignatureSize)
+	mode3.SignTo(s.priv, digest, sig)
+	return sig, nil
+}
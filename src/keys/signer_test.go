@@ -0,0 +1,169 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+)
+
+func TestEd25519Signer_MatchesSignEd25519SHA256(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	s, err := NewEd25519Signer(priv)
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	if got, want := IssuerKeyForSigner(s), GenerateIssuerKeyFromSeed(seed); got != want {
+		t.Fatalf("issuer key mismatch: got %q want %q", got, want)
+	}
+
+	msg := []byte("hello")
+	got, err := SignMessage(s, "sha256", msg)
+	if err != nil {
+		t.Fatalf("SignMessage: %v", err)
+	}
+	if want := SignEd25519SHA256(msg, priv); got != want {
+		t.Fatalf("signature mismatch")
+	}
+}
+
+func TestNewEd25519Signer_RejectsInvalidKey(t *testing.T) {
+	if _, err := NewEd25519Signer(ed25519.PrivateKey{0x01}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestDilithium3Signer_Verifies(t *testing.T) {
+	pk, sk, err := GenerateDilithium3Keypair(io.Reader(&deterministicReader{}))
+	if err != nil {
+		t.Fatalf("GenerateDilithium3Keypair: %v", err)
+	}
+	s, err := NewDilithium3Signer(sk)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+	if !strings.HasPrefix(IssuerKeyForSigner(s), "dilithium3:") {
+		t.Fatalf("unexpected issuer key prefix: %q", IssuerKeyForSigner(s))
+	}
+
+	msg := []byte("hello")
+	sigB64, err := SignMessage(s, "sha512", msg)
+	if err != nil {
+		t.Fatalf("SignMessage: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	digest := sha512.Sum512(msg)
+	if !mode3.Verify(pk, digest[:], sig) {
+		t.Fatalf("signature did not verify")
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Algorithm() string           { return "ed25519" }
+func (failingSigner) PublicKey() []byte           { return make([]byte, ed25519.PublicKeySize) }
+func (failingSigner) Sign([]byte) ([]byte, error) { return nil, errors.New("device unavailable") }
+
+func TestSignMessage_PropagatesSignerError(t *testing.T) {
+	if _, err := SignMessage(failingSigner{}, "sha256", []byte("hello")); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := SignMessage(failingSigner{}, "md5", []byte("hello")); err == nil {
+		t.Fatalf("expected unsupported hash error")
+	}
+}
+
+func TestVerifyMessage_RoundTripsSignMessage(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	edSigner, err := NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	_, sk, err := GenerateDilithium3Keypair(io.Reader(&deterministicReader{}))
+	if err != nil {
+		t.Fatalf("GenerateDilithium3Keypair: %v", err)
+	}
+	dlSigner, err := NewDilithium3Signer(sk)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+
+	msg := []byte("hello")
+	for _, s := range []Signer{edSigner, dlSigner} {
+		for _, hashAlg := range []string{"sha256", "sha512", "sha3-256"} {
+			sigB64, err := SignMessage(s, hashAlg, msg)
+			if err != nil {
+				t.Fatalf("SignMessage(%s, %s): %v", s.Algorithm(), hashAlg, err)
+			}
+			sig, err := base64.StdEncoding.DecodeString(sigB64)
+			if err != nil {
+				t.Fatalf("decode signature: %v", err)
+			}
+			if err := VerifyMessage(s.Algorithm(), hashAlg, s.PublicKey(), msg, sig); err != nil {
+				t.Fatalf("VerifyMessage(%s, %s): %v", s.Algorithm(), hashAlg, err)
+			}
+			if err := VerifyMessage(s.Algorithm(), hashAlg, s.PublicKey(), []byte("hellO"), sig); err == nil {
+				t.Fatalf("VerifyMessage(%s, %s) accepted a different message", s.Algorithm(), hashAlg)
+			}
+		}
+	}
+
+	sigB64, _ := SignMessage(edSigner, "sha256", msg)
+	sig, _ := base64.StdEncoding.DecodeString(sigB64)
+	for name, err := range map[string]error{
+		"hash mismatch":   VerifyMessage("ed25519", "sha512", edSigner.PublicKey(), msg, sig),
+		"unknown hash":    VerifyMessage("ed25519", "md5", edSigner.PublicKey(), msg, sig),
+		"unknown alg":     VerifyMessage("rsa", "sha256", edSigner.PublicKey(), msg, sig),
+		"alg mismatch":    VerifyMessage("dilithium3", "sha256", edSigner.PublicKey(), msg, sig),
+		"short key":       VerifyMessage("ed25519", "sha256", edSigner.PublicKey()[1:], msg, sig),
+		"short signature": VerifyMessage("ed25519", "sha256", edSigner.PublicKey(), msg, sig[1:]),
+	} {
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	s, err := NewEd25519Signer(ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	issuer := IssuerKeyForSigner(s)
+	alg, pub, err := ParsePublicKey(issuer)
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+	if alg != "ed25519" || string(pub) != string(s.PublicKey()) {
+		t.Fatalf("ParsePublicKey = %q, %x", alg, pub)
+	}
+	if _, _, err := ParsePublicKey(strings.TrimRight(issuer, "=")); err != nil {
+		t.Fatalf("ParsePublicKey(unpadded): %v", err)
+	}
+
+	enc := base64.StdEncoding.EncodeToString(s.PublicKey())
+	for key, want := range map[string]error{
+		"ed25519" + enc:      ErrKeyEncoding,
+		"ed25519:%%%":        ErrKeyBase64,
+		"rsa:" + enc:         ErrUnsupportedAlgorithm,
+		"ed25519:" + enc[4:]: ErrPublicKey,
+		"dilithium3:" + enc:  ErrPublicKey,
+	} {
+		if _, _, err := ParsePublicKey(key); !errors.Is(err, want) {
+			t.Fatalf("ParsePublicKey(%q) = %v, want %v", key, err, want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// DecodeRequest reads exactly one JSON object from r into v, rejecting
+// unknown fields and trailing data. Every transport decodes requests this
+// way, so a body is accepted or rejected identically over HTTP and gRPC.
+//
+// Errors from r (for example http.MaxBytesError) are returned unwrapped.
+func DecodeRequest(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return errors.New("trailing data after JSON object")
+	}
+	return nil
+}
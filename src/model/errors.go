@@ -5,12 +5,15 @@ import "fmt"
 type ErrorCode string
 
 const (
-	ErrInvalidRequest ErrorCode = "INVALID_REQUEST"
-	ErrInvalidCID     ErrorCode = "INVALID_CID"
-	ErrMissingCAS     ErrorCode = "MISSING_CAS"
-	ErrNotFound       ErrorCode = "NOT_FOUND"
-	ErrCIDMismatch    ErrorCode = "CID_MISMATCH"
-	ErrInternal       ErrorCode = "INTERNAL"
+	ErrInvalidRequest   ErrorCode = "INVALID_REQUEST"
+	ErrInvalidCID       ErrorCode = "INVALID_CID"
+	ErrMissingCAS       ErrorCode = "MISSING_CAS"
+	ErrNotFound         ErrorCode = "NOT_FOUND"
+	ErrCIDMismatch      ErrorCode = "CID_MISMATCH"
+	ErrCanceled         ErrorCode = "CANCELED"
+	ErrDeadlineExceeded ErrorCode = "DEADLINE_EXCEEDED"
+	ErrRequestTooLarge  ErrorCode = "REQUEST_TOO_LARGE"
+	ErrInternal         ErrorCode = "INTERNAL"
 )
 
 // CodedError is a stable error with a machine-readable code and a human message.
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/ipfs/go-cid"
 
@@ -21,7 +23,12 @@ type ResolveOptions struct {
 // ResolveResult runs the resolver (hydrating by CID via CAS when needed) and returns a compact,
 // Go-friendly view of the outcome.
 func ResolveResult(req ResolverRequest, opts ResolveOptions) (*ResolutionResult, error) {
-	out, crofBytes, _, crofCID, err := resolveAndRender(req, opts)
+	return ResolveResultContext(context.Background(), req, opts)
+}
+
+// ResolveResultContext is ResolveResult with cancellation of CAS hydration.
+func ResolveResultContext(ctx context.Context, req ResolverRequest, opts ResolveOptions) (*ResolutionResult, error) {
+	out, crofBytes, _, crofCID, err := resolveAndRender(ctx, req, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +46,12 @@ func ResolveResult(req ResolverRequest, opts ResolveOptions) (*ResolutionResult,
 // ResolveAndRenderCROF runs the resolver (hydrating by CID via CAS when needed) and renders
 // canonical CROF bytes bound to the inputs.
 func ResolveAndRenderCROF(req ResolverRequest, opts ResolveOptions) (*ResolverResponse, error) {
-	out, crofBytes, crofCIDStr, _, err := resolveAndRender(req, opts)
+	return ResolveAndRenderCROFContext(context.Background(), req, opts)
+}
+
+// ResolveAndRenderCROFContext is ResolveAndRenderCROF with cancellation of CAS hydration.
+func ResolveAndRenderCROFContext(ctx context.Context, req ResolverRequest, opts ResolveOptions) (*ResolverResponse, error) {
+	out, crofBytes, crofCIDStr, _, err := resolveAndRender(ctx, req, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -56,19 +68,85 @@ func ResolveAndRenderCROF(req ResolverRequest, opts ResolveOptions) (*ResolverRe
 	return resp, nil
 }
 
-func resolveAndRender(req ResolverRequest, opts ResolveOptions) (*resolver.ResolveOutputCAS, []byte, string, cid.Cid, error) {
+// ResolveNameAndRenderCROF resolves a symbolic name (hydrating by CID via CAS when needed)
+// and renders a canonical name-resolution CROF bound to the inputs.
+func ResolveNameAndRenderCROF(req NameResolverRequest, opts ResolveOptions) (*NameResolverResponse, error) {
+	return ResolveNameAndRenderCROFContext(context.Background(), req, opts)
+}
+
+// ResolveNameAndRenderCROFContext is ResolveNameAndRenderCROF with cancellation of CAS hydration.
+func ResolveNameAndRenderCROFContext(ctx context.Context, req NameResolverRequest, opts ResolveOptions) (*NameResolverResponse, error) {
+	if req.Name == "" {
+		return nil, NewError(ErrInvalidRequest, "missing name")
+	}
 	policyRef, err := toBlobRef(req.Policy)
 	if err != nil {
-		return nil, nil, "", cid.Undef, err
+		return nil, err
 	}
-
-	attRefs := make([]resolver.BlobRef, 0, len(req.Attestations))
-	for i, a := range req.Attestations {
-		ref, err := toBlobRef(a)
+	attRefs, err := toAttestationRefs(req.Attestations)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := toCompliance(req.Compliance)
+	if err != nil {
+		return nil, err
+	}
+	var sel resolver.VersionSelector
+	if req.Selector != "" {
+		if req.Version != "" {
+			return nil, NewError(ErrInvalidRequest, "version and selector are mutually exclusive")
+		}
+		sel, err = resolver.ParseVersionSelector(req.Selector)
 		if err != nil {
-			return nil, nil, "", cid.Undef, NewError(ErrInvalidRequest, "invalid attestation["+itoa(i)+"]: "+err.Error())
+			return nil, NewError(ErrInvalidRequest, err.Error())
 		}
-		attRefs = append(attRefs, ref)
+	}
+
+	asOf, err := toAsOf(req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := resolver.ResolveNameWithCASContext(ctx, resolver.ResolveNameRequestCAS{
+		Attestations: attRefs,
+		Policy:       policyRef,
+		Name:         req.Name,
+		Version:      req.Version,
+		Selector:     sel,
+		Compliance:   mode,
+		AsOf:         asOf,
+		CAS:          opts.CAS,
+		CASAdapters:  opts.CASAdapters,
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	crofBytes, crofCID, err := crof.RenderNameWithCID(out.Resolution, out.TrustPolicyCID, out.AttestationIDs, opts.CROFOptions)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &NameResolverResponse{
+		Resolution:     fromNameResolution(out.Resolution),
+		TrustPolicyCID: out.TrustPolicyCID,
+		AttestationIDs: append([]string(nil), out.AttestationIDs...),
+		CROF: CROFDocument{
+			Bytes: crofBytes,
+			CID:   crofCID,
+		},
+	}, nil
+}
+
+func resolveAndRender(ctx context.Context, req ResolverRequest, opts ResolveOptions) (*resolver.ResolveOutputCAS, []byte, string, cid.Cid, error) {
+	policyRef, err := toBlobRef(req.Policy)
+	if err != nil {
+		return nil, nil, "", cid.Undef, err
+	}
+
+	attRefs, err := toAttestationRefs(req.Attestations)
+	if err != nil {
+		return nil, nil, "", cid.Undef, err
 	}
 
 	mode, err := toCompliance(req.Compliance)
@@ -76,11 +154,17 @@ func resolveAndRender(req ResolverRequest, opts ResolveOptions) (*resolver.Resol
 		return nil, nil, "", cid.Undef, err
 	}
 
-	out, err := resolver.ResolveWithCAS(resolver.ResolveRequestCAS{
+	asOf, err := toAsOf(req.AsOf)
+	if err != nil {
+		return nil, nil, "", cid.Undef, err
+	}
+
+	out, err := resolver.ResolveWithCASContext(ctx, resolver.ResolveRequestCAS{
 		Attestations: attRefs,
 		Policy:       policyRef,
 		SubjectCID:   req.SubjectCID,
 		Compliance:   mode,
+		AsOf:         asOf,
 		CAS:          opts.CAS,
 		CASAdapters:  opts.CASAdapters,
 	})
@@ -101,6 +185,18 @@ func resolveAndRender(req ResolverRequest, opts ResolveOptions) (*resolver.Resol
 	return out, crofBytes, crofCIDStr, crofCID, nil
 }
 
+func toAttestationRefs(atts []BlobRef) ([]resolver.BlobRef, error) {
+	refs := make([]resolver.BlobRef, 0, len(atts))
+	for i, a := range atts {
+		ref, err := toBlobRef(a)
+		if err != nil {
+			return nil, NewError(ErrInvalidRequest, "invalid attestation["+itoa(i)+"]: "+err.Error())
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
 func toBlobRef(b BlobRef) (resolver.BlobRef, error) {
 	if len(b.Bytes) > 0 && b.CID != "" {
 		return resolver.BlobRef{}, NewError(ErrInvalidRequest, "blob ref has both bytes and cid")
@@ -131,6 +227,25 @@ func toCompliance(m ComplianceMode) (compliance.ComplianceMode, error) {
 	}
 }
 
+func toAsOf(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := resolver.ParseAsOf(s)
+	if err != nil {
+		return time.Time{}, NewError(ErrInvalidRequest, err.Error())
+	}
+	return t, nil
+}
+
+// formatAsOf renders a resolution's AsOf for the DTOs; zero means "".
+func formatAsOf(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func mapErr(err error) error {
 	if err == nil {
 		return nil
@@ -139,6 +254,12 @@ func mapErr(err error) error {
 	if errors.As(err, &ce) {
 		return ce
 	}
+	if errors.Is(err, context.Canceled) {
+		return NewError(ErrCanceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewError(ErrDeadlineExceeded, err.Error())
+	}
 	if errors.Is(err, resolver.ErrMissingCAS) {
 		return NewError(ErrMissingCAS, err.Error())
 	}
@@ -161,9 +282,10 @@ func fromResolution(r *resolver.Resolution) Resolution {
 		Confidence:     string(r.Confidence),
 		Paths:          make([]Path, 0, len(r.Paths)),
 		Forks:          make([]Fork, 0, len(r.Forks)),
-		Exclusions:     make([]Exclusion, 0, len(r.Exclusions)),
-		Verdicts:       make([]Verdict, 0, len(r.Verdicts)),
-		PolicyVerdicts: make([]PolicyVerdict, 0, len(r.PolicyVerdicts)),
+		Exclusions:     fromExclusions(r.Exclusions),
+		Verdicts:       fromVerdicts(r.Verdicts),
+		PolicyVerdicts: fromPolicyVerdicts(r.PolicyVerdicts),
+		AsOf:           formatAsOf(r.AsOf),
 	}
 	for _, p := range r.Paths {
 		out.Paths = append(out.Paths, Path{ID: p.ID, CIDs: append([]string(nil), p.CIDs...)})
@@ -171,15 +293,50 @@ func fromResolution(r *resolver.Resolution) Resolution {
 	for _, f := range r.Forks {
 		out.Forks = append(out.Forks, Fork{ID: f.ID, ConflictingPath: append([]string(nil), f.ConflictingPath...)})
 	}
-	for _, e := range r.Exclusions {
-		out.Exclusions = append(out.Exclusions, Exclusion{CID: e.CID, InputHash: e.InputHash, Reason: e.Reason})
+	return out
+}
+
+func fromNameResolution(r *resolver.NameResolution) NameResolution {
+	out := NameResolution{
+		Name:           r.Name,
+		Version:        r.Version,
+		Selector:       r.Selector,
+		State:          string(r.State),
+		Confidence:     string(r.Confidence),
+		PointsTo:       r.PointsTo,
+		Bindings:       append([]string{}, r.Bindings...),
+		Forks:          make([]NameFork, 0, len(r.Forks)),
+		Exclusions:     fromExclusions(r.Exclusions),
+		Verdicts:       fromVerdicts(r.Verdicts),
+		PolicyVerdicts: fromPolicyVerdicts(r.PolicyVerdicts),
+		AsOf:           formatAsOf(r.AsOf),
+	}
+	if len(r.ConsideredVersions) > 0 {
+		out.ConsideredVersions = append([]string(nil), r.ConsideredVersions...)
+	}
+	for _, f := range r.Forks {
+		out.Forks = append(out.Forks, NameFork{ID: f.ID, ConflictingBinding: append([]string(nil), f.ConflictingBinding...)})
+	}
+	return out
+}
+
+func fromExclusions(in []resolver.Exclusion) []Exclusion {
+	out := make([]Exclusion, 0, len(in))
+	for _, e := range in {
+		out = append(out, Exclusion{CID: e.CID, InputHash: e.InputHash, Reason: e.Reason})
 	}
-	for _, v := range r.Verdicts {
-		out.Verdicts = append(out.Verdicts, Verdict{
+	return out
+}
+
+func fromVerdicts(in []resolver.Verdict) []Verdict {
+	out := make([]Verdict, 0, len(in))
+	for _, v := range in {
+		out = append(out, Verdict{
 			CID:                v.CID,
 			InputHash:          v.InputHash,
 			AttestedSubjectCID: v.AttestedSubjectCID,
 			IssuerKey:          v.IssuerKey,
+			SignerKeys:         append([]string(nil), v.SignerKeys...),
 			ClaimType:          v.ClaimType,
 			Trusted:            v.Trusted,
 			TrustRoles:         append([]string(nil), v.TrustRoles...),
@@ -188,10 +345,31 @@ func fromResolution(r *resolver.Resolution) Resolution {
 			Status:             string(v.Status),
 			Reasons:            append([]string(nil), v.Reasons...),
 			ExcludedReason:     v.ExcludedReason,
+			Delegations:        fromDelegationChains(v.Delegations),
 		})
 	}
-	for _, pv := range r.PolicyVerdicts {
-		out.PolicyVerdicts = append(out.PolicyVerdicts, PolicyVerdict{
+	return out
+}
+
+func fromDelegationChains(in []resolver.DelegationChain) []DelegationChain {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]DelegationChain, 0, len(in))
+	for _, c := range in {
+		links := make([]DelegationLink, 0, len(c.Links))
+		for _, l := range c.Links {
+			links = append(links, DelegationLink{CID: l.CID, Delegator: l.Delegator, Delegate: l.Delegate})
+		}
+		out = append(out, DelegationChain{SignerKey: c.SignerKey, Role: c.Role, Links: links})
+	}
+	return out
+}
+
+func fromPolicyVerdicts(in []resolver.PolicyVerdict) []PolicyVerdict {
+	out := make([]PolicyVerdict, 0, len(in))
+	for _, pv := range in {
+		out = append(out, PolicyVerdict{
 			Type:       pv.Type,
 			Role:       pv.Role,
 			Quorum:     pv.Quorum,
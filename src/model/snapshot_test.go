@@ -96,3 +96,36 @@ func TestSnapshot_ResolverResponse_JSONShape(t *testing.T) {
 		t.Fatalf("snapshot mismatch:\n%s", string(b))
 	}
 }
+
+func TestSnapshot_NameResolverRequest_JSONShape(t *testing.T) {
+	req := NameResolverRequest{
+		Name:         "contracts.purchase",
+		Version:      "1.0.0",
+		Policy:       BlobRef{CID: "bafy-policy-1"},
+		Attestations: []BlobRef{{CID: "bafy-binding-1"}},
+		Compliance:   CompliancePermissive,
+	}
+
+	b, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent failed: %v", err)
+	}
+
+	const want = "{\n" +
+		"  \"name\": \"contracts.purchase\",\n" +
+		"  \"version\": \"1.0.0\",\n" +
+		"  \"policy\": {\n" +
+		"    \"cid\": \"bafy-policy-1\"\n" +
+		"  },\n" +
+		"  \"attestations\": [\n" +
+		"    {\n" +
+		"      \"cid\": \"bafy-binding-1\"\n" +
+		"    }\n" +
+		"  ],\n" +
+		"  \"compliance\": \"permissive\"\n" +
+		"}"
+
+	if string(b) != want {
+		t.Fatalf("snapshot mismatch:\n%s", string(b))
+	}
+}
@@ -16,11 +16,31 @@ const (
 	ComplianceStrict     ComplianceMode = "strict"
 )
 
+// ResolverRequest is a subject-resolution request.
+//
+// AsOf is optional (RFC3339 or YYYY-MM-DD). When set, attestations outside
+// their Effective-Date/Expires window at that instant are excluded, and the
+// instant is recorded in the CROF INPUTS section.
 type ResolverRequest struct {
 	SubjectCID   string         `json:"subjectCID"`
 	Policy       BlobRef        `json:"policy"`
 	Attestations []BlobRef      `json:"attestations"`
 	Compliance   ComplianceMode `json:"compliance"`
+	AsOf         string         `json:"asOf,omitempty"`
+}
+
+// NameResolverRequest is the name-resolution counterpart of ResolverRequest.
+// Version is optional; empty means any version. Selector is an optional semver
+// selector ("latest" or a range such as "^1.2.0") and is mutually exclusive
+// with Version. AsOf is as for ResolverRequest.
+type NameResolverRequest struct {
+	Name         string         `json:"name"`
+	Version      string         `json:"version,omitempty"`
+	Selector     string         `json:"selector,omitempty"`
+	Policy       BlobRef        `json:"policy"`
+	Attestations []BlobRef      `json:"attestations"`
+	Compliance   ComplianceMode `json:"compliance"`
+	AsOf         string         `json:"asOf,omitempty"`
 }
 
 type Path struct {
@@ -44,6 +64,7 @@ type Verdict struct {
 	InputHash          string   `json:"inputHash"`
 	AttestedSubjectCID string   `json:"attestedSubjectCID"`
 	IssuerKey          string   `json:"issuerKey"`
+	SignerKeys         []string `json:"signerKeys,omitempty"`
 	ClaimType          string   `json:"claimType"`
 	Trusted            bool     `json:"trusted"`
 	TrustRoles         []string `json:"trustRoles"`
@@ -52,6 +73,21 @@ type Verdict struct {
 	Status             string   `json:"status"`
 	Reasons            []string `json:"reasons"`
 	ExcludedReason     string   `json:"excludedReason"`
+
+	Delegations []DelegationChain `json:"delegations,omitempty"`
+}
+
+// DelegationChain explains a trust role held through delegation; see resolver.DelegationChain.
+type DelegationChain struct {
+	SignerKey string           `json:"signerKey"`
+	Role      string           `json:"role"`
+	Links     []DelegationLink `json:"links"`
+}
+
+type DelegationLink struct {
+	CID       string `json:"cid"`
+	Delegator string `json:"delegator"`
+	Delegate  string `json:"delegate"`
 }
 
 type PolicyVerdict struct {
@@ -73,6 +109,28 @@ type Resolution struct {
 	Exclusions     []Exclusion     `json:"exclusions"`
 	Verdicts       []Verdict       `json:"verdicts"`
 	PolicyVerdicts []PolicyVerdict `json:"policyVerdicts"`
+	AsOf           string          `json:"asOf,omitempty"`
+}
+
+type NameFork struct {
+	ID                 string   `json:"id"`
+	ConflictingBinding []string `json:"conflictingBinding"`
+}
+
+type NameResolution struct {
+	Name               string          `json:"name"`
+	Version            string          `json:"version,omitempty"`
+	Selector           string          `json:"selector,omitempty"`
+	ConsideredVersions []string        `json:"consideredVersions,omitempty"`
+	State              string          `json:"state"`
+	Confidence         string          `json:"confidence"`
+	PointsTo           string          `json:"pointsTo"`
+	Bindings           []string        `json:"bindings"`
+	Forks              []NameFork      `json:"forks"`
+	Exclusions         []Exclusion     `json:"exclusions"`
+	Verdicts           []Verdict       `json:"verdicts"`
+	PolicyVerdicts     []PolicyVerdict `json:"policyVerdicts"`
+	AsOf               string          `json:"asOf,omitempty"`
 }
 
 type CROFDocument struct {
@@ -86,3 +144,59 @@ type ResolverResponse struct {
 	AttestationIDs []string     `json:"attestationIDs"`
 	CROF           CROFDocument `json:"crof"`
 }
+
+type NameResolverResponse struct {
+	Resolution     NameResolution `json:"resolution"`
+	TrustPolicyCID string         `json:"trustPolicyCID"`
+	AttestationIDs []string       `json:"attestationIDs"`
+	CROF           CROFDocument   `json:"crof"`
+}
+
+// DocumentRequest carries a single document for verification.
+type DocumentRequest struct {
+	Bytes []byte `json:"bytes"`
+}
+
+// CATFVerifyResponse reports whether bytes are canonical CATF with valid
+// signatures. Invalid documents are a result, not an error: Valid is false and
+// Error/RuleID describe the first failure.
+type CATFVerifyResponse struct {
+	Valid      bool     `json:"valid"`
+	CID        string   `json:"cid,omitempty"`
+	SubjectCID string   `json:"subjectCID,omitempty"`
+	ClaimType  string   `json:"claimType,omitempty"`
+	SignerKeys []string `json:"signerKeys,omitempty"`
+	RuleID     string   `json:"ruleID,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// CROFVerifyResponse reports whether bytes are canonical CROF and, when
+// signed, whether the resolver signature verifies.
+type CROFVerifyResponse struct {
+	Valid  bool   `json:"valid"`
+	CID    string `json:"cid,omitempty"`
+	Signed bool   `json:"signed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CIDKind selects how a CIDRequest derives its CID.
+type CIDKind string
+
+const (
+	// CIDKindRaw hashes the bytes as-is (document CID, as doc-cid).
+	CIDKindRaw CIDKind = "raw"
+	// CIDKindCATF requires canonical CATF bytes.
+	CIDKindCATF CIDKind = "catf"
+	// CIDKindCROF requires canonical CROF bytes.
+	CIDKindCROF CIDKind = "crof"
+)
+
+type CIDRequest struct {
+	Kind  CIDKind `json:"kind"`
+	Bytes []byte  `json:"bytes"`
+}
+
+type CIDResponse struct {
+	Kind CIDKind `json:"kind"`
+	CID  string  `json:"cid"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"xdao.co/catf/catf"
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/crof"
+)
+
+// VerifyCATF parses canonical CATF bytes and verifies every signature.
+func VerifyCATF(req DocumentRequest) (*CATFVerifyResponse, error) {
+	if len(req.Bytes) == 0 {
+		return nil, NewError(ErrInvalidRequest, "missing bytes")
+	}
+	doc, err := catf.Parse(req.Bytes)
+	if err != nil {
+		return &CATFVerifyResponse{RuleID: catf.RuleID(err), Error: err.Error()}, nil
+	}
+	out := &CATFVerifyResponse{SubjectCID: doc.SubjectCID(), ClaimType: doc.ClaimType()}
+	if id, err := doc.CID(); err == nil {
+		out.CID = id
+	}
+	if entries, err := doc.SignatureEntries(); err == nil {
+		for _, e := range entries {
+			out.SignerKeys = append(out.SignerKeys, e.IssuerKey)
+		}
+	}
+	if err := doc.Verify(); err != nil {
+		out.RuleID = catf.RuleID(err)
+		out.Error = err.Error()
+		return out, nil
+	}
+	out.Valid = true
+	return out, nil
+}
+
+// VerifyCROF checks that bytes are canonical CROF (either profile) and
+// verifies the resolver signature when one is present.
+func VerifyCROF(req DocumentRequest) (*CROFVerifyResponse, error) {
+	if len(req.Bytes) == 0 {
+		return nil, NewError(ErrInvalidRequest, "missing bytes")
+	}
+	id, err := crof.CID(req.Bytes)
+	if err != nil {
+		return &CROFVerifyResponse{Error: err.Error()}, nil
+	}
+	out := &CROFVerifyResponse{CID: id}
+	signed, err := crof.VerifySignature(req.Bytes)
+	if err != nil {
+		out.Error = err.Error()
+		return out, nil
+	}
+	out.Signed = signed
+	out.Valid = true
+	return out, nil
+}
+
+// ComputeCID derives the CID of req.Bytes according to req.Kind.
+func ComputeCID(req CIDRequest) (*CIDResponse, error) {
+	if len(req.Bytes) == 0 && req.Kind != CIDKindRaw {
+		return nil, NewError(ErrInvalidRequest, "missing bytes")
+	}
+	switch req.Kind {
+	case CIDKindRaw:
+		return &CIDResponse{Kind: req.Kind, CID: cidutil.CIDv1RawSHA256(req.Bytes)}, nil
+	case CIDKindCATF:
+		doc, err := catf.Parse(req.Bytes)
+		if err != nil {
+			return nil, NewError(ErrInvalidRequest, err.Error())
+		}
+		id, err := doc.CID()
+		if err != nil {
+			return nil, NewError(ErrInvalidRequest, err.Error())
+		}
+		return &CIDResponse{Kind: req.Kind, CID: id}, nil
+	case CIDKindCROF:
+		id, err := crof.CID(req.Bytes)
+		if err != nil {
+			return nil, NewError(ErrInvalidRequest, err.Error())
+		}
+		return &CIDResponse{Kind: req.Kind, CID: id}, nil
+	case "":
+		return nil, NewError(ErrInvalidRequest, "missing kind")
+	default:
+		return nil, NewError(ErrInvalidRequest, "invalid kind")
+	}
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"fmt"
+	"time"
+
+	"xdao.co/catf/catf"
+)
+
+// Stable reasons recorded when an as-of resolution excludes an attestation
+// because of its validity window (CLAIMS Effective-Date and Expires).
+const (
+	ReasonNotYetEffective      = "Not yet effective as of As-Of"
+	ReasonExpired              = "Expired as of As-Of"
+	ReasonInvalidEffectiveDate = "Effective-Date invalid"
+	ReasonInvalidExpires       = "Expires invalid"
+)
+
+// ParseAsOf parses an as-of instant: either RFC 3339 or a date (YYYY-MM-DD),
+// which means 00:00:00 UTC on that day. The result is UTC, truncated to whole
+// seconds, matching how it is recorded in CROF.
+func ParseAsOf(s string) (time.Time, error) {
+	t, err := parseClaimTime(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("resolver: invalid as-of %q (want RFC3339 or YYYY-MM-DD)", s)
+	}
+	return normalizeAsOf(t), nil
+}
+
+// normalizeAsOf returns asOf in UTC at whole-second precision so the instant
+// used for evaluation is exactly the one a CROF records. The zero time stays
+// zero (time-independent resolution).
+func normalizeAsOf(asOf time.Time) time.Time {
+	if asOf.IsZero() {
+		return asOf
+	}
+	return asOf.UTC().Truncate(time.Second)
+}
+
+func parseClaimTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// validityReason reports why an attestation is outside its validity window at
+// asOf, or "" when it is valid (or asOf is zero).
+//
+// An attestation is valid from Effective-Date (inclusive) until Expires
+// (exclusive). Either claim may be absent. Date-only values mean 00:00:00 UTC.
+func validityReason(a *catf.CATF, asOf time.Time) string {
+	if asOf.IsZero() {
+		return ""
+	}
+	claims := a.Sections["CLAIMS"].Pairs
+	if s, ok := claims["Effective-Date"]; ok {
+		t, err := parseClaimTime(s)
+		if err != nil {
+			return ReasonInvalidEffectiveDate
+		}
+		if t.After(asOf) {
+			return ReasonNotYetEffective
+		}
+	}
+	if s, ok := claims["Expires"]; ok {
+		t, err := parseClaimTime(s)
+		if err != nil {
+			return ReasonInvalidExpires
+		}
+		if !t.After(asOf) {
+			return ReasonExpired
+		}
+	}
+	return ""
+}
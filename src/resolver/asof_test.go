@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"xdao.co/catf/catf"
+)
+
+func TestResolveAsOf_EffectiveDateAndExpires(t *testing.T) {
+	subject := "bafy-closing-doc"
+	buyerPub, buyerPriv := mustKeypair(t, 0x31)
+	buyer := issuerKey(buyerPub)
+
+	approval := mustAttestation(t, subject, "Deed", map[string]string{
+		"Effective-Date": "2026-03-01",
+		"Expires":        "2026-09-01T00:00:00Z",
+		"Role":           "buyer",
+		"Type":           "approval",
+	}, buyer, buyerPriv)
+	revocation := mustAttestation(t, subject, "Deed", map[string]string{
+		"Effective-Date":     "2026-05-01T12:00:00Z",
+		"Target-Attestation": mustCID(t, approval),
+		"Type":               "revocation",
+	}, buyer, buyerPriv)
+	policy := []byte(trustPolicy([]trustEntry{{buyer, "buyer"}}, []requireRule{{"approval", "buyer", 1}}))
+	atts := [][]byte{approval, revocation}
+
+	resolveAt := func(asOf time.Time) *Resolution {
+		t.Helper()
+		res, err := ResolveWithOptions(atts, policy, subject, Options{AsOf: asOf})
+		if err != nil {
+			t.Fatalf("ResolveWithOptions(%v): %v", asOf, err)
+		}
+		return res
+	}
+
+	// Without AsOf time is ignored: the revocation applies.
+	if res := resolveAt(time.Time{}); res.State != StateRevoked || !res.AsOf.IsZero() {
+		t.Fatalf("no AsOf: got %s (AsOf %v)", res.State, res.AsOf)
+	}
+
+	// Between approval and revocation: the revocation does not exist yet.
+	closing := time.Date(2026, 4, 15, 9, 30, 0, 500, time.FixedZone("EST", -5*3600))
+	res := resolveAt(closing)
+	if res.State != StateResolved {
+		t.Fatalf("closing day: expected Resolved, got %s", res.State)
+	}
+	if want := time.Date(2026, 4, 15, 14, 30, 0, 0, time.UTC); !res.AsOf.Equal(want) || res.AsOf.Location() != time.UTC {
+		t.Fatalf("AsOf not normalized: %v", res.AsOf)
+	}
+	assertExclusion(t, res, mustCID(t, revocation), ReasonNotYetEffective)
+
+	// Before the approval is effective nothing applies.
+	res = resolveAt(time.Date(2026, 2, 28, 23, 59, 59, 0, time.UTC))
+	if res.State != StateUnresolved {
+		t.Fatalf("before approval: expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, approval), ReasonNotYetEffective)
+
+	// Effective-Date is inclusive.
+	if res := resolveAt(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)); res.State != StateResolved {
+		t.Fatalf("on Effective-Date: expected Resolved, got %s", res.State)
+	}
+
+	// Expires is exclusive; once the approval expires the revocation has no target.
+	res = resolveAt(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	if res.State != StateUnresolved {
+		t.Fatalf("after expiry: expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, approval), ReasonExpired)
+}
+
+func TestResolveAsOf_InvalidDatesAreExcluded(t *testing.T) {
+	subject := "bafy-bad-dates"
+	pub, priv := mustKeypair(t, 0x32)
+	issuer := issuerKey(pub)
+	badEffective := mustAttestation(t, subject, "Doc", map[string]string{
+		"Effective-Date": "next tuesday",
+		"Role":           "buyer",
+		"Type":           "approval",
+	}, issuer, priv)
+	badExpires := mustAttestation(t, subject, "Doc", map[string]string{
+		"Effective-Date": "2026-01-01",
+		"Expires":        "2026-13-01",
+		"Role":           "buyer",
+		"Type":           "approval",
+	}, issuer, priv)
+	policy := []byte(trustPolicy([]trustEntry{{issuer, "buyer"}}, []requireRule{{"approval", "buyer", 1}}))
+	atts := [][]byte{badEffective, badExpires}
+
+	res, err := ResolveWithOptions(atts, policy, subject, Options{})
+	if err != nil || len(res.Exclusions) != 0 {
+		t.Fatalf("without AsOf dates are not interpreted: %+v %v", res, err)
+	}
+
+	res, err = ResolveWithOptions(atts, policy, subject, Options{AsOf: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("ResolveWithOptions: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, badEffective), ReasonInvalidEffectiveDate)
+	assertExclusion(t, res, mustCID(t, badExpires), ReasonInvalidExpires)
+}
+
+func TestParseAsOf(t *testing.T) {
+	for in, want := range map[string]time.Time{
+		"2026-04-15":                time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC),
+		"2026-04-15T09:30:00-05:00": time.Date(2026, 4, 15, 14, 30, 0, 0, time.UTC),
+		"2026-04-15T14:30:00.9Z":    time.Date(2026, 4, 15, 14, 30, 0, 0, time.UTC),
+	} {
+		got, err := ParseAsOf(in)
+		if err != nil || !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("ParseAsOf(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseAsOf("04/15/2026"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func assertExclusion(t *testing.T, res *Resolution, cid, reason string) {
+	t.Helper()
+	for _, ex := range res.Exclusions {
+		if ex.CID == cid {
+			if ex.Reason != reason {
+				t.Fatalf("exclusion %s: got reason %q want %q", cid, ex.Reason, reason)
+			}
+			return
+		}
+	}
+	t.Fatalf("no exclusion for %s in %+v", cid, res.Exclusions)
+}
+
+func mustCID(t *testing.T, attestation []byte) string {
+	t.Helper()
+	a, err := catf.Parse(attestation)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	id, err := a.CID()
+	if err != nil {
+		t.Fatalf("CID: %v", err)
+	}
+	return id
+}
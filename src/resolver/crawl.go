@@ -0,0 +1,395 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/storage"
+)
+
+// Default crawl limits, used when CrawlOptions leaves a limit at zero.
+const (
+	DefaultCrawlMaxDepth        = 8
+	DefaultCrawlMaxAttestations = 1024
+)
+
+// Crawl gap fields that are not CLAIMS keys.
+const (
+	CrawlFieldSeed  = "Seed"
+	CrawlFieldIndex = "Index"
+)
+
+// AttestationIndex finds attestations that cannot be reached by following
+// references forward: attestations about a subject, attestations whose
+// CLAIMS reference another attestation (revocations, supersessions), and
+// attestations whose CLAIMS name an issuer key (key-rotation, key-revocation,
+// delegation).
+//
+// ByReference is queried with attestation CIDs and with Issuer-Key values.
+// Result order does not matter; the crawler sorts results.
+type AttestationIndex interface {
+	BySubject(ctx context.Context, subjectCID string) ([]cid.Cid, error)
+	ByReference(ctx context.Context, target string) ([]cid.Cid, error)
+}
+
+// CrawlOptions bounds an attestation graph crawl.
+type CrawlOptions struct {
+	// Index, when set, is consulted for attestations about the subject (and
+	// about Points-To targets) and for attestations referencing each
+	// collected attestation or one of its issuer keys. Without it only
+	// forward references are followed.
+	Index AttestationIndex
+
+	// MaxDepth is the number of reference hops followed from the seeds.
+	MaxDepth int
+	// MaxAttestations caps the number of collected attestations.
+	MaxAttestations int
+}
+
+// CrawlRequest starts a crawl from a subject and/or seed attestations.
+//
+// CAS and CASAdapters follow the same rules as ResolveRequestCAS.
+type CrawlRequest struct {
+	SubjectCID string
+	Seeds      []BlobRef
+
+	CAS         storage.CAS
+	CASAdapters []storage.CAS
+
+	Options CrawlOptions
+}
+
+// CrawledAttestation is one attestation collected by a crawl.
+type CrawledAttestation struct {
+	CID   string
+	Bytes []byte
+	// Depth is the number of reference hops from the nearest seed or
+	// subject lookup.
+	Depth int
+}
+
+// CrawlGap records a reference the crawl could not follow.
+type CrawlGap struct {
+	// CID is the referenced identifier as written.
+	CID string
+	// ReferencedBy is the attestation holding the reference; it is empty for
+	// seeds and for index results.
+	ReferencedBy string
+	// Field is the CLAIMS key holding the reference, CrawlFieldSeed or
+	// CrawlFieldIndex.
+	Field  string
+	Reason string
+}
+
+// CrawlResult is the closed set of attestations reached by a crawl.
+type CrawlResult struct {
+	// Attestations are sorted by CID.
+	Attestations []CrawledAttestation
+	// Gaps are sorted by CID, then ReferencedBy, then Field.
+	Gaps []CrawlGap
+	// Truncated reports that a depth or count limit stopped the crawl while
+	// references remained unfollowed.
+	Truncated bool
+}
+
+// Refs returns the collected attestations as byte-backed BlobRefs, ready for
+// ResolveRequestCAS.Attestations or ResolveNameRequestCAS.Attestations.
+func (r *CrawlResult) Refs() []BlobRef {
+	out := make([]BlobRef, 0, len(r.Attestations))
+	for _, a := range r.Attestations {
+		out = append(out, BlobRef{Bytes: a.Bytes})
+	}
+	return out
+}
+
+// Crawl deterministically collects the attestations transitively referenced
+// from req.SubjectCID and req.Seeds.
+//
+// The crawl is breadth-first and sorted at every level. From each collected
+// attestation it follows the CLAIMS references Supersedes and
+// Target-Attestation (fetched from CAS), Points-To (as a subject lookup in the
+// index), and the index's reverse references to the attestation and to each
+// of its Issuer-Keys, which find the key events and delegations affecting its
+// signers. Signatures are not checked here; trust is evaluated by resolution.
+//
+// Missing, mismatched or non-CATF objects are reported as gaps rather than
+// errors. Seeds are always collected, even when they do not parse, so that
+// resolution reports them as exclusions. Cancellation and index failures
+// abort the crawl.
+func Crawl(ctx context.Context, req CrawlRequest) (*CrawlResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cas, err := casFromRequest(req.CAS, req.CASAdapters)
+	if err != nil {
+		return nil, err
+	}
+	opts := req.Options
+	if opts.MaxDepth < 0 || opts.MaxAttestations < 0 {
+		return nil, errors.New("resolver: crawl limits must not be negative")
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultCrawlMaxDepth
+	}
+	if opts.MaxAttestations == 0 {
+		opts.MaxAttestations = DefaultCrawlMaxAttestations
+	}
+
+	c := &crawler{
+		ctx:     ctx,
+		cas:     cas,
+		index:   opts.Index,
+		seen:    make(map[string]bool),
+		subject: make(map[string]bool),
+		issuers: make(map[string]bool),
+		res:     &CrawlResult{},
+	}
+
+	var frontier []crawlRef
+	for _, s := range req.Seeds {
+		ref := crawlRef{field: CrawlFieldSeed, seed: true}
+		switch {
+		case len(s.Bytes) > 0 && s.CID.Defined():
+			return nil, errors.New("ambiguous blob ref: both bytes and CID set")
+		case len(s.Bytes) > 0:
+			ref.cid = cidutil.CIDv1RawSHA256(s.Bytes)
+			ref.bytes = s.Bytes
+		case s.CID.Defined():
+			ref.cid = s.CID.String()
+		default:
+			return nil, errors.New("invalid blob ref: neither bytes nor CID set")
+		}
+		frontier = append(frontier, ref)
+	}
+	if req.SubjectCID != "" {
+		refs, err := c.subjectRefs(req.SubjectCID)
+		if err != nil {
+			return nil, err
+		}
+		frontier = append(frontier, refs...)
+	}
+
+	for depth := 0; len(frontier) > 0; depth++ {
+		sortCrawlRefs(frontier)
+		var level []*crawlNode
+		for _, ref := range frontier {
+			if c.seen[ref.cid] {
+				continue
+			}
+			if len(c.res.Attestations) >= opts.MaxAttestations {
+				c.res.Truncated = true
+				break
+			}
+			c.seen[ref.cid] = true
+			n, err := c.fetch(ref, depth)
+			if err != nil {
+				return nil, err
+			}
+			if n != nil {
+				level = append(level, n)
+			}
+		}
+		if c.res.Truncated {
+			break
+		}
+
+		var next []crawlRef
+		for _, n := range level {
+			refs, err := c.references(n)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, refs...)
+		}
+		if depth == opts.MaxDepth {
+			for _, ref := range next {
+				if !c.seen[ref.cid] {
+					c.res.Truncated = true
+					break
+				}
+			}
+			break
+		}
+		frontier = next
+	}
+
+	sort.Slice(c.res.Attestations, func(i, j int) bool {
+		return c.res.Attestations[i].CID < c.res.Attestations[j].CID
+	})
+	sort.Slice(c.res.Gaps, func(i, j int) bool {
+		a, b := c.res.Gaps[i], c.res.Gaps[j]
+		if a.CID != b.CID {
+			return a.CID < b.CID
+		}
+		if a.ReferencedBy != b.ReferencedBy {
+			return a.ReferencedBy < b.ReferencedBy
+		}
+		return a.Field < b.Field
+	})
+	return c.res, nil
+}
+
+// ResolveWithCrawlContext crawls from req.SubjectCID, seeded with
+// req.Attestations, and resolves the subject over the collected set.
+//
+// The returned CrawlResult carries the gaps that resolution could not see.
+func ResolveWithCrawlContext(ctx context.Context, req ResolveRequestCAS, opts CrawlOptions) (*ResolveOutputCAS, *CrawlResult, error) {
+	crawl, err := Crawl(ctx, CrawlRequest{
+		SubjectCID:  req.SubjectCID,
+		Seeds:       req.Attestations,
+		CAS:         req.CAS,
+		CASAdapters: req.CASAdapters,
+		Options:     opts,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Attestations = crawl.Refs()
+	out, err := ResolveWithCASContext(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, crawl, nil
+}
+
+type crawlRef struct {
+	cid          string
+	referencedBy string
+	field        string
+	seed         bool
+	bytes        []byte
+}
+
+type crawlNode struct {
+	cid  string
+	catf *catf.CATF
+}
+
+type crawler struct {
+	ctx     context.Context
+	cas     storage.ContextCAS
+	index   AttestationIndex
+	seen    map[string]bool
+	subject map[string]bool
+	issuers map[string]bool
+	res     *CrawlResult
+}
+
+func sortCrawlRefs(refs []crawlRef) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.cid != b.cid {
+			return a.cid < b.cid
+		}
+		if a.seed != b.seed {
+			return a.seed
+		}
+		if a.referencedBy != b.referencedBy {
+			return a.referencedBy < b.referencedBy
+		}
+		return a.field < b.field
+	})
+}
+
+func (c *crawler) gap(ref crawlRef, reason string) {
+	c.res.Gaps = append(c.res.Gaps, CrawlGap{
+		CID:          ref.cid,
+		ReferencedBy: ref.referencedBy,
+		Field:        ref.field,
+		Reason:       reason,
+	})
+}
+
+// fetch collects ref and returns its parsed node, or nil when it is a gap or
+// an unparseable seed.
+func (c *crawler) fetch(ref crawlRef, depth int) (*crawlNode, error) {
+	b := ref.bytes
+	if b == nil {
+		id, err := cid.Decode(ref.cid)
+		if err != nil {
+			c.gap(ref, "invalid CID")
+			return nil, nil
+		}
+		b, _, err = hydrateOne(c.ctx, BlobRef{CID: id}, c.cas)
+		if err != nil {
+			if cerr := c.ctx.Err(); cerr != nil {
+				return nil, cerr
+			}
+			c.gap(ref, err.Error())
+			return nil, nil
+		}
+	}
+
+	doc, err := catf.Parse(b)
+	if err != nil && !ref.seed {
+		c.gap(ref, "not a CATF document: "+err.Error())
+		return nil, nil
+	}
+	c.res.Attestations = append(c.res.Attestations, CrawledAttestation{CID: ref.cid, Bytes: b, Depth: depth})
+	if err != nil {
+		return nil, nil
+	}
+	return &crawlNode{cid: ref.cid, catf: doc}, nil
+}
+
+// references lists the outgoing and index-reported incoming references of n.
+func (c *crawler) references(n *crawlNode) ([]crawlRef, error) {
+	var out []crawlRef
+	claims := n.catf.Sections["CLAIMS"].Pairs
+	for _, field := range []string{"Supersedes", "Target-Attestation"} {
+		if v := claims[field]; v != "" {
+			out = append(out, crawlRef{cid: v, referencedBy: n.cid, field: field})
+		}
+	}
+	if v := claims["Points-To"]; v != "" {
+		refs, err := c.subjectRefs(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, refs...)
+	}
+	if c.index == nil {
+		return out, nil
+	}
+	targets := []string{n.cid}
+	if signers, err := n.catf.SignatureEntries(); err == nil {
+		for _, s := range signers {
+			if s.IssuerKey != "" && !c.issuers[s.IssuerKey] {
+				c.issuers[s.IssuerKey] = true
+				targets = append(targets, s.IssuerKey)
+			}
+		}
+	}
+	for _, target := range targets {
+		ids, err := c.index.ByReference(c.ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			out = append(out, crawlRef{cid: id.String(), field: CrawlFieldIndex})
+		}
+	}
+	return out, nil
+}
+
+// subjectRefs looks up attestations about subjectCID once per crawl.
+func (c *crawler) subjectRefs(subjectCID string) ([]crawlRef, error) {
+	if c.index == nil || c.subject[subjectCID] {
+		return nil, nil
+	}
+	c.subject[subjectCID] = true
+	ids, err := c.index.BySubject(c.ctx, subjectCID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]crawlRef, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, crawlRef{cid: id.String(), field: CrawlFieldIndex})
+	}
+	return out, nil
+}
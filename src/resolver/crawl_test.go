@@ -0,0 +1,181 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/storage"
+)
+
+type mapIndex struct {
+	subjects map[string][]cid.Cid
+	refs     map[string][]cid.Cid
+}
+
+func (x mapIndex) BySubject(_ context.Context, subjectCID string) ([]cid.Cid, error) {
+	return x.subjects[subjectCID], nil
+}
+
+func (x mapIndex) ByReference(_ context.Context, targetCID string) ([]cid.Cid, error) {
+	return x.refs[targetCID], nil
+}
+
+func mustPut(t *testing.T, cas storage.CAS, b []byte) cid.Cid {
+	t.Helper()
+	id, err := cas.Put(b)
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	return id
+}
+
+func TestResolveWithCrawl_FindsRevocationThroughIndex(t *testing.T) {
+	subject := "bafy-contract-crawl"
+	pub, priv := mustKeypair(t, 0xC1)
+	issuer := issuerKey(pub)
+
+	cas := newMemCAS()
+	approval := mustAttestation(t, subject, "Contract", map[string]string{
+		"Effective-Date": "2026-01-10",
+		"Role":           "buyer",
+		"Type":           "approval",
+	}, issuer, priv)
+	approvalCID := mustPut(t, cas, approval)
+	revocation := mustAttestation(t, subject, "Contract", map[string]string{
+		"Target-Attestation": approvalCID.String(),
+		"Type":               "revocation",
+	}, issuer, priv)
+	revocationCID := mustPut(t, cas, revocation)
+	policy := mustPut(t, cas, []byte(trustPolicy(
+		[]trustEntry{{issuer, "buyer"}},
+		[]requireRule{{"approval", "buyer", 1}},
+	)))
+
+	// The index only knows the revocation as a reference to the approval.
+	idx := mapIndex{
+		subjects: map[string][]cid.Cid{subject: {approvalCID}},
+		refs:     map[string][]cid.Cid{approvalCID.String(): {revocationCID}},
+	}
+
+	out, crawl, err := ResolveWithCrawlContext(context.Background(), ResolveRequestCAS{
+		Policy:     BlobRef{CID: policy},
+		SubjectCID: subject,
+		CAS:        cas,
+	}, CrawlOptions{Index: idx})
+	if err != nil {
+		t.Fatalf("ResolveWithCrawlContext: %v", err)
+	}
+	if out.Resolution.State != StateRevoked {
+		t.Fatalf("expected Revoked, got %s", out.Resolution.State)
+	}
+	if len(crawl.Attestations) != 2 || len(crawl.Gaps) != 0 || crawl.Truncated {
+		t.Fatalf("unexpected crawl: %+v", crawl)
+	}
+	for _, a := range crawl.Attestations {
+		want := 0
+		if a.CID == revocationCID.String() {
+			want = 1
+		}
+		if a.Depth != want {
+			t.Fatalf("%s: depth %d, want %d", a.CID, a.Depth, want)
+		}
+	}
+	if len(out.AttestationIDs) != 2 {
+		t.Fatalf("expected 2 resolver inputs, got %v", out.AttestationIDs)
+	}
+}
+
+func TestResolveWithCrawl_FindsKeyRevocationByIssuer(t *testing.T) {
+	subject := "bafy-contract-key-crawl"
+	buyerPub, buyerPriv := mustKeypair(t, 0xC4)
+	custodianPub, custodianPriv := mustKeypair(t, 0xC5)
+	buyer, custodian := issuerKey(buyerPub), issuerKey(custodianPub)
+
+	cas := newMemCAS()
+	approvalCID := mustPut(t, cas, approvalOn(t, subject, "2026-01-10", buyer, buyerPriv))
+	revokeCID := mustPut(t, cas, mustAttestation(t, "bafy-key-revocation", "Compromise", map[string]string{
+		"Revoked-Key": buyer,
+		"Type":        "key-revocation",
+	}, custodian, custodianPriv))
+	base := trustPolicy([]trustEntry{{buyer, "buyer"}, {custodian, "custodian"}}, []requireRule{{"approval", "buyer", 1}})
+	policy := mustPut(t, cas, withKeyRecovery(base, "custodian"))
+
+	// The index only knows the key-revocation through the key it revokes.
+	idx := mapIndex{
+		subjects: map[string][]cid.Cid{subject: {approvalCID}},
+		refs:     map[string][]cid.Cid{buyer: {revokeCID}},
+	}
+
+	out, crawl, err := ResolveWithCrawlContext(context.Background(), ResolveRequestCAS{
+		Policy:     BlobRef{CID: policy},
+		SubjectCID: subject,
+		CAS:        cas,
+	}, CrawlOptions{Index: idx})
+	if err != nil {
+		t.Fatalf("ResolveWithCrawlContext: %v", err)
+	}
+	if len(crawl.Attestations) != 2 {
+		t.Fatalf("key-revocation not collected: %+v", crawl)
+	}
+	if out.Resolution.State != StateUnresolved {
+		t.Fatalf("expected Unresolved, got %s", out.Resolution.State)
+	}
+	assertExclusion(t, out.Resolution, approvalCID.String(), ReasonKeyRevoked)
+}
+
+func TestCrawl_ReportsGapsAndTruncation(t *testing.T) {
+	subject := "bafy-chain-crawl"
+	pub, priv := mustKeypair(t, 0xC2)
+	issuer := issuerKey(pub)
+
+	cas := newMemCAS()
+	first := mustAttestation(t, subject, "v1", map[string]string{"Role": "author", "Type": "authorship"}, issuer, priv)
+	missing := mustPut(t, newMemCAS(), first) // never stored in cas
+	second := mustAttestation(t, subject, "v2", map[string]string{"Supersedes": missing.String(), "Type": "supersedes"}, issuer, priv)
+	secondCID := mustPut(t, cas, second)
+	third := mustAttestation(t, subject, "v3", map[string]string{"Supersedes": secondCID.String(), "Type": "supersedes"}, issuer, priv)
+
+	res, err := Crawl(context.Background(), CrawlRequest{Seeds: []BlobRef{{Bytes: third}}, CAS: cas})
+	if err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	if len(res.Attestations) != 2 || res.Truncated {
+		t.Fatalf("unexpected crawl: %+v", res)
+	}
+	if len(res.Gaps) != 1 {
+		t.Fatalf("expected 1 gap, got %+v", res.Gaps)
+	}
+	g := res.Gaps[0]
+	if g.CID != missing.String() || g.ReferencedBy != secondCID.String() || g.Field != "Supersedes" || !strings.Contains(g.Reason, storage.ErrNotFound.Error()) {
+		t.Fatalf("unexpected gap: %+v", g)
+	}
+
+	res, err = Crawl(context.Background(), CrawlRequest{Seeds: []BlobRef{{Bytes: third}}, CAS: cas, Options: CrawlOptions{MaxDepth: 1}})
+	if err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	if len(res.Attestations) != 2 || len(res.Gaps) != 0 || !res.Truncated {
+		t.Fatalf("expected truncation without gaps, got %+v", res)
+	}
+
+	res, err = Crawl(context.Background(), CrawlRequest{Seeds: []BlobRef{{Bytes: third}}, CAS: cas, Options: CrawlOptions{MaxAttestations: 1}})
+	if err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	if len(res.Attestations) != 1 || !res.Truncated {
+		t.Fatalf("expected count truncation, got %+v", res)
+	}
+}
+
+func TestCrawl_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Crawl(ctx, CrawlRequest{SubjectCID: "bafy-x"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package resolver
+
+import (
+	"sort"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/tpdl"
+)
+
+// DelegationLink is one delegation attestation in a chain: Delegator granted
+// the chain's role to Delegate.
+type DelegationLink struct {
+	CID       string
+	Delegator string
+	Delegate  string
+}
+
+// DelegationChain explains a role a signer holds through delegation rather
+// than directly through policy TRUST.
+//
+// Links run from a key holding the role in TRUST (Links[0].Delegator) to SignerKey
+// (the last Delegate). Only the shortest chain is reported per signer and role.
+type DelegationChain struct {
+	SignerKey string
+	Role      string
+	Links     []DelegationLink
+}
+
+type delegationGrant struct {
+	role  string
+	scope trustScope
+	links []DelegationLink
+}
+
+// delegationGrants maps delegate keys to the roles they hold through
+// delegation, shortest chains first. A nil value grants nothing.
+type delegationGrants map[string][]delegationGrant
+
+type delegation struct {
+	cid       string
+	delegator string
+	delegate  string
+	roles     []string
+	scope     trustScope
+}
+
+func parseDelegation(a *catf.CATF, cid string) delegation {
+	claims := a.Sections["CLAIMS"].Pairs
+	d := delegation{
+		cid:       cid,
+		delegator: claims["Delegator-Key"],
+		delegate:  claims["Delegate-Key"],
+		scope:     trustScope{typ: claims["Scope-Type"], subject: claims["Scope-Subject"]},
+	}
+	d.roles, _ = catf.SplitRoles(claims["Roles"])
+	return d
+}
+
+// collectDelegationGrants computes the roles granted by the delegation events
+// among the inputs. It returns nil unless the policy enables delegation.
+//
+// Only delegations whose delegator is an active signer (see
+// keyEvents.activeSigners) and that have not been withdrawn (see
+// withdrawsDelegation) are honored. Delegations apply regardless of the
+// subject being resolved.
+func collectDelegationGrants(events []trustEvent, policy *tpdl.Policy, trust *policyTrust, ke *keyEvents) delegationGrants {
+	if policy == nil || policy.DelegationMaxDepth < 1 {
+		return nil
+	}
+	var ds []delegation
+	for _, e := range events {
+		if e.catf.ClaimType() != "delegation" {
+			continue
+		}
+		d := parseDelegation(e.catf, e.cid)
+		// The delegator's own signature must verify, its key must be active and
+		// policy must not deny it.
+		if signers, _ := ke.activeSigners(e.catf, e.cid, e.signers); containsString(signers, d.delegator) && !trust.denies(d.delegator, "delegation") {
+			ds = append(ds, d)
+		}
+	}
+	byCID := make(map[string]delegation, len(ds))
+	for _, d := range ds {
+		byCID[d.cid] = d
+	}
+	withdrawn := make(map[string]bool)
+	for _, e := range events {
+		if e.catf.ClaimType() != "revocation" {
+			continue
+		}
+		signers, _ := ke.activeSigners(e.catf, e.cid, e.signers)
+		d, ok := byCID[e.catf.Sections["CLAIMS"].Pairs["Target-Attestation"]]
+		if ok && withdrawsDelegation(e.catf, signers, d, policy, trust) {
+			withdrawn[d.cid] = true
+		}
+	}
+	var live []delegation
+	for _, d := range ds {
+		if !withdrawn[d.cid] {
+			live = append(live, d)
+		}
+	}
+	return buildDelegationGrants(live, trust, policy.DelegationMaxDepth)
+}
+
+// buildDelegationGrants walks delegations breadth-first from the roles held
+// through TRUST, including scoped entries.
+// Round n honors delegations whose delegator gained the role in round n-1, so
+// every grant carries a shortest chain and no chain exceeds maxDepth links.
+// A grant is skipped when the delegate already holds the role in a covering scope.
+func buildDelegationGrants(ds []delegation, trust *policyTrust, maxDepth int) delegationGrants {
+	type holding struct {
+		key   string
+		grant delegationGrant
+	}
+	var frontier []holding
+	keys := make([]string, 0, len(trust.roles)+len(trust.scoped))
+	for k := range trust.roles {
+		keys = append(keys, k)
+	}
+	for k := range trust.scoped {
+		if trust.roles[k] == nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, r := range sortedRoles(trust.roles[k]) {
+			frontier = append(frontier, holding{key: k, grant: delegationGrant{role: r}})
+		}
+		for _, s := range trust.scoped[k] {
+			frontier = append(frontier, holding{key: k, grant: delegationGrant{role: s.role, scope: s.scope}})
+		}
+	}
+
+	grants := make(delegationGrants)
+	held := func(key, role string, scope trustScope) bool {
+		if trust.holds(key, role, scope) {
+			return true
+		}
+		for _, g := range grants[key] {
+			if g.role == role && g.scope.covers(scope) {
+				return true
+			}
+		}
+		return false
+	}
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		var next []holding
+		for _, d := range ds {
+			for _, role := range d.roles {
+				for _, h := range frontier {
+					if h.key != d.delegator || h.grant.role != role {
+						continue
+					}
+					scope, ok := h.grant.scope.narrow(d.scope)
+					if !ok || held(d.delegate, role, scope) {
+						continue
+					}
+					links := append(append([]DelegationLink(nil), h.grant.links...), DelegationLink{CID: d.cid, Delegator: d.delegator, Delegate: d.delegate})
+					g := delegationGrant{role: role, scope: scope, links: links}
+					grants[d.delegate] = append(grants[d.delegate], g)
+					next = append(next, holding{key: d.delegate, grant: g})
+				}
+			}
+		}
+		frontier = next
+	}
+	if len(grants) == 0 {
+		return nil
+	}
+	return grants
+}
+
+// signerRoles is trustedSignerRoles extended with roles held through
+// delegation whose scope admits a. chains explains each delegated role that
+// the signer does not also hold through TRUST, ordered by signer key and role.
+// Denied signers receive no delegated roles.
+func (g delegationGrants) signerRoles(trust *policyTrust, signers []string, a *catf.CATF) (map[string]map[string]bool, []DelegationChain) {
+	out := trustedSignerRoles(trust, signers, a)
+	if len(g) == 0 {
+		return out, nil
+	}
+	var chains []DelegationChain
+	for _, k := range signers {
+		if trust.denies(k, a.ClaimType()) {
+			continue
+		}
+		for _, gr := range g[k] {
+			if !gr.scope.admits(a) || out[k][gr.role] {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]map[string]bool)
+			}
+			roles := make(map[string]bool, len(out[k])+1)
+			for r := range out[k] {
+				roles[r] = true
+			}
+			roles[gr.role] = true
+			out[k] = roles
+			chains = append(chains, DelegationChain{SignerKey: k, Role: gr.role, Links: gr.links})
+		}
+	}
+	sort.SliceStable(chains, func(i, j int) bool {
+		if chains[i].SignerKey != chains[j].SignerKey {
+			return chains[i].SignerKey < chains[j].SignerKey
+		}
+		return chains[i].Role < chains[j].Role
+	})
+	return out, chains
+}
+
+// withdrawsDelegation reports whether revocation rev, signed by signers,
+// withdraws delegation d. The delegator may always withdraw its own grant.
+// Other signers not denied revocations may withdraw it when they hold every
+// delegated role through TRUST in d's scope, or hold a Key-Recovery role.
+// Keys trusted only through delegation cannot withdraw delegations, which
+// keeps chain evaluation independent of its own outcome.
+func withdrawsDelegation(rev *catf.CATF, signers []string, d delegation, policy *tpdl.Policy, trust *policyTrust) bool {
+	for _, k := range signers {
+		if k == d.delegator {
+			return true
+		}
+		if trust.denies(k, "revocation") {
+			continue
+		}
+		holdsAll := len(d.roles) > 0
+		for _, r := range d.roles {
+			if !trust.holds(k, r, d.scope) {
+				holdsAll = false
+				break
+			}
+		}
+		if holdsAll {
+			return true
+		}
+		if policy != nil {
+			roles := trust.rolesFor(k, rev)
+			for _, r := range policy.KeyRecoveryAllowedBy {
+				if roles[r] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// trustReasons explains why an attestation with the given signers is trusted,
+// or why it is not: ReasonIssuerDenied when a signer matches a Deny entry.
+func trustReasons(trust *policyTrust, signers []string, a *catf.CATF, chains []DelegationChain) []string {
+	var reasons []string
+	if len(trustedSignerRoles(trust, signers, a)) > 0 {
+		reasons = append(reasons, "Issuer trusted by policy")
+	}
+	if len(chains) > 0 {
+		reasons = append(reasons, "Issuer trusted by delegation")
+	}
+	for _, k := range signers {
+		if trust.denies(k, a.ClaimType()) {
+			reasons = append(reasons, ReasonIssuerDenied)
+			break
+		}
+	}
+	return reasons
+}
+
+func sortedRoles(roles map[string]bool) []string {
+	out := make([]string, 0, len(roles))
+	for r := range roles {
+		out = append(out, r)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func containsString(items []string, s string) bool {
+	for _, it := range items {
+		if it == s {
+			return true
+		}
+	}
+	return false
+}
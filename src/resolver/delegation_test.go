@@ -0,0 +1,237 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+)
+
+func withDelegation(policy string, maxDepth int) []byte {
+	policy = strings.Replace(policy, "Spec: xdao-tpdl-1", "Spec: xdao-tpdl-2", 1)
+	return []byte(strings.Replace(policy, "-----END XDAO TRUST POLICY-----",
+		"Delegation:\n  Max-Depth: "+itoa(maxDepth)+"\n-----END XDAO TRUST POLICY-----", 1))
+}
+
+func findVerdict(t *testing.T, verdicts []Verdict, cid string) Verdict {
+	t.Helper()
+	for _, v := range verdicts {
+		if v.CID == cid {
+			return v
+		}
+	}
+	t.Fatalf("no verdict for %s", cid)
+	return Verdict{}
+}
+
+func TestResolveDelegation_ChainDepth(t *testing.T) {
+	subject := "bafy-recorded-deed"
+	rootPub, rootPriv := mustKeypair(t, 0x41)
+	clerkPub, clerkPriv := mustKeypair(t, 0x42)
+	deputyPub, deputyPriv := mustKeypair(t, 0x43)
+	root, clerk, deputy := issuerKey(rootPub), issuerKey(clerkPub), issuerKey(deputyPub)
+
+	toClerk := mustAttestation(t, "bafy-clerk-appointment", "Clerk appointment", map[string]string{
+		"Delegate-Key":  clerk,
+		"Delegator-Key": root,
+		"Roles":         "recorder",
+		"Type":          "delegation",
+	}, root, rootPriv)
+	toDeputy := mustAttestation(t, "bafy-deputy-appointment", "Deputy appointment", map[string]string{
+		"Delegate-Key":  deputy,
+		"Delegator-Key": clerk,
+		"Roles":         "recorder",
+		"Type":          "delegation",
+	}, clerk, clerkPriv)
+	byClerk := mustAttestation(t, subject, "Deed", map[string]string{"Role": "recorder", "Type": "approval", "Effective-Date": "2026-01-01"}, clerk, clerkPriv)
+	byDeputy := mustAttestation(t, subject, "Deed", map[string]string{"Role": "recorder", "Type": "approval", "Effective-Date": "2026-01-01"}, deputy, deputyPriv)
+
+	base := trustPolicy([]trustEntry{{root, "recorder"}}, []requireRule{{"approval", "recorder", 2}})
+	atts := [][]byte{toClerk, toDeputy, byClerk, byDeputy}
+
+	// Without a Delegation block, delegations grant nothing.
+	res, err := Resolve(atts, []byte(base), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("delegation disabled: expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, byClerk), "Issuer not trusted")
+
+	// Depth 1 admits the clerk but not the deputy.
+	res, err = Resolve(atts, withDelegation(base, 1), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("depth 1: expected Unresolved, got %s", res.State)
+	}
+	v := findVerdict(t, res.Verdicts, mustCID(t, byClerk))
+	if !v.Trusted || len(v.Delegations) != 1 || len(v.Delegations[0].Links) != 1 {
+		t.Fatalf("depth 1: clerk verdict %+v", v)
+	}
+	assertExclusion(t, res, mustCID(t, byDeputy), "Issuer not trusted")
+
+	// Depth 2 admits both and reports the full chain for the deputy.
+	res, err = Resolve(atts, withDelegation(base, 2), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("depth 2: expected Resolved, got %s", res.State)
+	}
+	v = findVerdict(t, res.Verdicts, mustCID(t, byDeputy))
+	want := DelegationChain{SignerKey: deputy, Role: "recorder", Links: []DelegationLink{
+		{CID: mustCID(t, toClerk), Delegator: root, Delegate: clerk},
+		{CID: mustCID(t, toDeputy), Delegator: clerk, Delegate: deputy},
+	}}
+	if len(v.Delegations) != 1 || v.Delegations[0].SignerKey != want.SignerKey || v.Delegations[0].Role != want.Role ||
+		len(v.Delegations[0].Links) != 2 || v.Delegations[0].Links[0] != want.Links[0] || v.Delegations[0].Links[1] != want.Links[1] {
+		t.Fatalf("depth 2: deputy chain %+v", v.Delegations)
+	}
+	if len(v.Reasons) != 1 || v.Reasons[0] != "Issuer trusted by delegation" {
+		t.Fatalf("depth 2: deputy reasons %v", v.Reasons)
+	}
+	if dv := findVerdict(t, res.Verdicts, mustCID(t, toClerk)); !dv.Trusted || len(dv.Delegations) != 0 {
+		t.Fatalf("root delegation verdict %+v", dv)
+	}
+}
+
+func TestResolveDelegation_ScopeAndWithdrawal(t *testing.T) {
+	subject := "bafy-permit"
+	rootPub, rootPriv := mustKeypair(t, 0x44)
+	clerkPub, clerkPriv := mustKeypair(t, 0x45)
+	root, clerk := issuerKey(rootPub), issuerKey(clerkPub)
+
+	scoped := func(scopeSubject string) []byte {
+		return mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+			"Delegate-Key":  clerk,
+			"Delegator-Key": root,
+			"Roles":         "inspector",
+			"Scope-Subject": scopeSubject,
+			"Scope-Type":    "approval",
+			"Type":          "delegation",
+		}, root, rootPriv)
+	}
+	approval := mustAttestation(t, subject, "Permit", map[string]string{"Role": "inspector", "Type": "approval", "Effective-Date": "2026-01-01"}, clerk, clerkPriv)
+	authorship := mustAttestation(t, subject, "Permit", map[string]string{"Role": "inspector", "Type": "authorship"}, clerk, clerkPriv)
+	policy := withDelegation(trustPolicy([]trustEntry{{root, "inspector"}}, []requireRule{{"approval", "inspector", 1}}), 1)
+
+	// Scope admits approvals of this subject only.
+	grant := scoped(subject)
+	res, err := Resolve([][]byte{grant, approval, authorship}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("in scope: expected Resolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, authorship), "Issuer not trusted")
+
+	res, err = Resolve([][]byte{scoped("bafy-other-permit"), approval}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("out of scope: expected Unresolved, got %s", res.State)
+	}
+
+	// The delegator withdraws the grant.
+	withdrawal := mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+		"Target-Attestation": mustCID(t, grant),
+		"Type":               "revocation",
+	}, root, rootPriv)
+	res, err = Resolve([][]byte{grant, withdrawal, approval}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("withdrawn: expected Unresolved, got %s", res.State)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, grant)); !v.Revoked {
+		t.Fatalf("withdrawn delegation not revoked: %+v", v)
+	}
+
+	// A delegate cannot withdraw the grant it depends on.
+	selfRevoke := mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+		"Target-Attestation": mustCID(t, grant),
+		"Type":               "revocation",
+	}, clerk, clerkPriv)
+	res, err = Resolve([][]byte{grant, selfRevoke, approval}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("delegate revocation: expected Resolved, got %s", res.State)
+	}
+}
+
+func TestResolveDelegation_WithdrawalAuthority(t *testing.T) {
+	subject := "bafy-deed-withdrawal"
+	notaryPub, notaryPriv := mustKeypair(t, 0x9a)
+	peerPub, peerPriv := mustKeypair(t, 0x9b)
+	witnessPub, witnessPriv := mustKeypair(t, 0x9c)
+	custodianPub, custodianPriv := mustKeypair(t, 0x9d)
+	clerkPub, clerkPriv := mustKeypair(t, 0x9e)
+	notary, peer, witness, custodian, clerk := issuerKey(notaryPub), issuerKey(peerPub), issuerKey(witnessPub), issuerKey(custodianPub), issuerKey(clerkPub)
+
+	grant := mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+		"Delegate-Key":  clerk,
+		"Delegator-Key": notary,
+		"Roles":         "notary",
+		"Type":          "delegation",
+	}, notary, notaryPriv)
+	approval := mustAttestation(t, subject, "Deed", map[string]string{"Effective-Date": "2026-01-01", "Role": "notary", "Type": "approval"}, clerk, clerkPriv)
+	policy := withDelegation(string(withKeyRecovery(trustPolicy([]trustEntry{
+		{notary, "notary"}, {peer, "notary"}, {witness, "witness"}, {custodian, "custodian"},
+	}, []requireRule{{"approval", "notary", 1}}), "custodian")), 1)
+	withdrawBy := func(issuer string, priv []byte) []byte {
+		return mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+			"Target-Attestation": mustCID(t, grant),
+			"Type":               "revocation",
+		}, issuer, priv)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		by        []byte
+		withdrawn bool
+	}{
+		{"lower-role key", withdrawBy(witness, witnessPriv), false},
+		{"holder of the delegated role", withdrawBy(peer, peerPriv), true},
+		{"Key-Recovery role", withdrawBy(custodian, custodianPriv), true},
+	} {
+		res, err := Resolve([][]byte{grant, tc.by, approval}, policy, subject)
+		if err != nil {
+			t.Fatalf("%s: Resolve: %v", tc.name, err)
+		}
+		want := StateResolved
+		if tc.withdrawn {
+			want = StateUnresolved
+		}
+		if res.State != want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, want, res.State)
+		}
+		if v := findVerdict(t, res.Verdicts, mustCID(t, grant)); v.Revoked != tc.withdrawn {
+			t.Fatalf("%s: delegation revoked=%v, want %v", tc.name, v.Revoked, tc.withdrawn)
+		}
+	}
+}
+
+func TestResolveDelegation_DelegatorMustSign(t *testing.T) {
+	rootPub, _ := mustKeypair(t, 0x46)
+	clerkPub, clerkPriv := mustKeypair(t, 0x47)
+	root, clerk := issuerKey(rootPub), issuerKey(clerkPub)
+
+	forged := mustAttestation(t, "bafy-appointment", "Appointment", map[string]string{
+		"Delegate-Key":  clerk,
+		"Delegator-Key": root,
+		"Roles":         "recorder",
+		"Type":          "delegation",
+	}, clerk, clerkPriv)
+	policy := withDelegation(trustPolicy([]trustEntry{{root, "recorder"}}, nil), 1)
+	res, err := Resolve([][]byte{forged}, policy, "bafy-appointment")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	assertExclusion(t, res, mustCID(t, forged), "CATF-VAL-255")
+}
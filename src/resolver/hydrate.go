@@ -1,8 +1,10 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ipfs/go-cid"
 
@@ -28,6 +30,9 @@ type BlobRef struct {
 // - If CASAdapters is provided, adapters are consulted in the provided slice order.
 // - No randomization or map iteration is used.
 // - If both CAS and CASAdapters are set, the request is rejected.
+//
+// CAS and adapters that also implement storage.ContextCAS receive the context
+// passed to ResolveWithCASContext.
 type ResolveRequestCAS struct {
 	Attestations []BlobRef
 	Policy       BlobRef
@@ -35,6 +40,13 @@ type ResolveRequestCAS struct {
 
 	Compliance compliance.ComplianceMode
 
+	// AsOf optionally resolves as of an instant; see Options.AsOf. It is
+	// recorded as the Resolution's AsOf.
+	AsOf time.Time
+
+	// Trace requests Resolution.Trace; see Options.Trace.
+	Trace bool
+
 	CAS         storage.CAS
 	CASAdapters []storage.CAS
 }
@@ -49,30 +61,132 @@ type ResolveOutputCAS struct {
 }
 
 func ResolveWithCAS(req ResolveRequestCAS) (*ResolveOutputCAS, error) {
-	cas, err := casFromRequest(req.CAS, req.CASAdapters)
+	return ResolveWithCASContext(context.Background(), req)
+}
+
+// ResolveWithCASContext is ResolveWithCAS with cancellation: CAS hydration
+// stops and returns ctx.Err() once ctx is done.
+func ResolveWithCASContext(ctx context.Context, req ResolveRequestCAS) (*ResolveOutputCAS, error) {
+	in, err := hydrateInputs(ctx, req.Attestations, req.Policy, req.Compliance, req.CAS, req.CASAdapters)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resolveWithPolicy(in.attBytes, in.policy, req.SubjectCID, req.AsOf, newTracer(req.Trace))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResolveOutputCAS{
+		Resolution:      res,
+		TrustPolicyCID:  in.policyCID.String(),
+		AttestationIDs:  in.attIDs,
+		AttestationCIDV: in.attCIDs,
+	}, nil
+}
+
+// ResolveNameRequestCAS is the name-resolution counterpart of ResolveRequestCAS.
+//
+// Hydration order and CAS/CASAdapters rules are identical to ResolveRequestCAS.
+// Version (exact) and Selector (range or latest) are mutually exclusive.
+type ResolveNameRequestCAS struct {
+	Attestations []BlobRef
+	Policy       BlobRef
+	Name         string
+	Version      string
+	Selector     VersionSelector
+
+	Compliance compliance.ComplianceMode
+
+	// AsOf optionally resolves as of an instant; see Options.AsOf.
+	AsOf time.Time
+
+	CAS         storage.CAS
+	CASAdapters []storage.CAS
+}
+
+// ResolveNameOutputCAS bundles a name resolution with the deterministic input identifiers
+// used to bind a name-resolution CROF to its inputs.
+type ResolveNameOutputCAS struct {
+	Resolution      *NameResolution
+	TrustPolicyCID  string
+	AttestationIDs  []string
+	AttestationCIDV []cid.Cid
+}
+
+// ResolveNameWithCAS resolves a symbolic name, hydrating CID inputs through an injected CAS.
+func ResolveNameWithCAS(req ResolveNameRequestCAS) (*ResolveNameOutputCAS, error) {
+	return ResolveNameWithCASContext(context.Background(), req)
+}
+
+// ResolveNameWithCASContext is ResolveNameWithCAS with cancellation.
+func ResolveNameWithCASContext(ctx context.Context, req ResolveNameRequestCAS) (*ResolveNameOutputCAS, error) {
+	sel := ExactVersion(req.Version)
+	if req.Selector.Kind != VersionAny {
+		if req.Version != "" {
+			return nil, errors.New("resolver: specify either Version or Selector, not both")
+		}
+		sel = req.Selector
+	}
+
+	in, err := hydrateInputs(ctx, req.Attestations, req.Policy, req.Compliance, req.CAS, req.CASAdapters)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resolveNameWithPolicy(in.attBytes, in.policy, req.Name, sel, req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResolveNameOutputCAS{
+		Resolution:      res,
+		TrustPolicyCID:  in.policyCID.String(),
+		AttestationIDs:  in.attIDs,
+		AttestationCIDV: in.attCIDs,
+	}, nil
+}
+
+type hydratedInputs struct {
+	policy    *tpdl.Policy
+	policyCID cid.Cid
+	attBytes  [][]byte
+	attIDs    []string
+	attCIDs   []cid.Cid
+}
+
+func hydrateInputs(ctx context.Context, attestations []BlobRef, policyRef BlobRef, mode compliance.ComplianceMode, single storage.CAS, adapters []storage.CAS) (*hydratedInputs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cas, err := casFromRequest(single, adapters)
 	if err != nil {
 		return nil, err
 	}
 
-	policyBytes, policyCID, err := hydrateOne(req.Policy, cas)
+	policyBytes, policyCID, err := hydrateOne(ctx, policyRef, cas)
 	if err != nil {
 		return nil, fmt.Errorf("resolver: hydrate policy: %w", err)
 	}
-	policy, err := tpdl.ParseWithCompliance(policyBytes, req.Compliance)
+	policy, err := tpdl.ParseWithCompliance(policyBytes, mode)
 	if err != nil {
 		return nil, err
 	}
 
-	attBytes := make([][]byte, 0, len(req.Attestations))
-	attIDs := make([]string, 0, len(req.Attestations))
-	attCIDs := make([]cid.Cid, 0, len(req.Attestations))
-	for i, a := range req.Attestations {
-		b, id, err := hydrateOne(a, cas)
+	in := &hydratedInputs{
+		policy:    policy,
+		policyCID: policyCID,
+		attBytes:  make([][]byte, 0, len(attestations)),
+		attIDs:    make([]string, 0, len(attestations)),
+		attCIDs:   make([]cid.Cid, 0, len(attestations)),
+	}
+	for i, a := range attestations {
+		b, id, err := hydrateOne(ctx, a, cas)
 		if err != nil {
 			return nil, fmt.Errorf("resolver: hydrate attestation[%d]: %w", i, err)
 		}
-		attBytes = append(attBytes, b)
-		attCIDs = append(attCIDs, id)
+		in.attBytes = append(in.attBytes, b)
+		in.attCIDs = append(in.attCIDs, id)
 
 		// Bind to either CATF CID (when parse/canonicalization succeeds) or a stable input hash.
 		if len(a.Bytes) > 0 {
@@ -80,35 +194,24 @@ func ResolveWithCAS(req ResolveRequestCAS) (*ResolveOutputCAS, error) {
 			if perr == nil {
 				cidStr, cerr := parsed.CID()
 				if cerr == nil {
-					attIDs = append(attIDs, cidStr)
+					in.attIDs = append(in.attIDs, cidStr)
 					continue
 				}
 			}
-			attIDs = append(attIDs, inputHash(a.Bytes))
+			in.attIDs = append(in.attIDs, inputHash(a.Bytes))
 			continue
 		}
-		attIDs = append(attIDs, id.String())
+		in.attIDs = append(in.attIDs, id.String())
 	}
-
-	res, err := resolveWithPolicy(attBytes, policy, req.SubjectCID)
-	if err != nil {
-		return nil, err
-	}
-
-	return &ResolveOutputCAS{
-		Resolution:      res,
-		TrustPolicyCID:  policyCID.String(),
-		AttestationIDs:  attIDs,
-		AttestationCIDV: attCIDs,
-	}, nil
+	return in, nil
 }
 
-func casFromRequest(single storage.CAS, adapters []storage.CAS) (storage.CAS, error) {
+func casFromRequest(single storage.CAS, adapters []storage.CAS) (storage.ContextCAS, error) {
 	if single != nil && len(adapters) > 0 {
 		return nil, errors.New("resolver: specify either CAS or CASAdapters, not both")
 	}
 	if single != nil {
-		return single, nil
+		return storage.AsContextCAS(single), nil
 	}
 	if len(adapters) > 0 {
 		return storage.MultiCAS{Adapters: adapters}, nil
@@ -116,7 +219,7 @@ func casFromRequest(single storage.CAS, adapters []storage.CAS) (storage.CAS, er
 	return nil, nil
 }
 
-func hydrateOne(ref BlobRef, cas storage.CAS) ([]byte, cid.Cid, error) {
+func hydrateOne(ctx context.Context, ref BlobRef, cas storage.ContextCAS) ([]byte, cid.Cid, error) {
 	if len(ref.Bytes) > 0 && ref.CID.Defined() {
 		return nil, cid.Undef, errors.New("ambiguous blob ref: both bytes and CID set")
 	}
@@ -131,7 +234,7 @@ func hydrateOne(ref BlobRef, cas storage.CAS) ([]byte, cid.Cid, error) {
 		if cas == nil {
 			return nil, cid.Undef, ErrMissingCAS
 		}
-		b, err := cas.Get(ref.CID)
+		b, err := cas.GetContext(ctx, ref.CID)
 		if err != nil {
 			return nil, cid.Undef, err
 		}
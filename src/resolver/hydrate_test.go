@@ -2,6 +2,8 @@ package resolver
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/ipfs/go-cid"
@@ -177,3 +179,74 @@ func TestResolveWithCAS_MultiAdapterOrderIsDeterministic(t *testing.T) {
 		t.Fatalf("resolution state mismatch")
 	}
 }
+
+func TestResolveNameWithCAS_HydratesAndMatchesResolveName(t *testing.T) {
+	pub, priv := mustKeypair(t, 0x43)
+	issuer := issuerKey(pub)
+
+	policyBytes := []byte(trustPolicy([]trustEntry{{key: issuer, role: "registrar"}}, nil))
+	binding := mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+		"Name":      "contracts.purchase",
+		"Points-To": "bafy-doc-1",
+		"Type":      "name-binding",
+		"Version":   "1.0.0",
+	}, issuer, priv)
+
+	cas := newMemCAS()
+	policyCID, err := cas.Put(policyBytes)
+	if err != nil {
+		t.Fatalf("Put policy failed: %v", err)
+	}
+	bindingCID, err := cas.Put(binding)
+	if err != nil {
+		t.Fatalf("Put binding failed: %v", err)
+	}
+
+	got, err := ResolveNameWithCAS(ResolveNameRequestCAS{
+		Attestations: []BlobRef{{CID: bindingCID}, {Bytes: []byte("not a catf")}},
+		Policy:       BlobRef{CID: policyCID},
+		Name:         "contracts.purchase",
+		Version:      "1.0.0",
+		Compliance:   compliance.Permissive,
+		CAS:          cas,
+	})
+	if err != nil {
+		t.Fatalf("ResolveNameWithCAS failed: %v", err)
+	}
+	if got.TrustPolicyCID != policyCID.String() {
+		t.Fatalf("unexpected TrustPolicyCID: %s", got.TrustPolicyCID)
+	}
+	if len(got.AttestationIDs) != 2 || got.AttestationIDs[0] != bindingCID.String() || got.AttestationIDs[1] != inputHash([]byte("not a catf")) {
+		t.Fatalf("unexpected AttestationIDs: %v", got.AttestationIDs)
+	}
+	if got.Resolution.State != StateResolved || got.Resolution.PointsTo != "bafy-doc-1" {
+		t.Fatalf("unexpected name resolution: %+v", got.Resolution)
+	}
+
+	if _, err := ResolveNameWithCAS(ResolveNameRequestCAS{
+		Attestations: []BlobRef{{CID: bindingCID}},
+		Policy:       BlobRef{CID: policyCID},
+		Name:         "contracts.purchase",
+	}); !errors.Is(err, ErrMissingCAS) {
+		t.Fatalf("expected ErrMissingCAS, got %v", err)
+	}
+}
+
+func TestResolveWithCASContext_Canceled(t *testing.T) {
+	cas := newMemCAS()
+	policyCID, err := cas.Put([]byte(trustPolicy(nil, nil)))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ResolveWithCASContext(ctx, ResolveRequestCAS{
+		Policy:     BlobRef{CID: policyCID},
+		SubjectCID: "bafy-subject",
+		CAS:        cas,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
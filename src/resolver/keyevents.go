@@ -0,0 +1,273 @@
+package resolver
+
+import (
+	"sort"
+	"time"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/tpdl"
+)
+
+// Stable exclusion reasons for key lifecycle events (key-rotation and
+// key-revocation attestations).
+const (
+	// ReasonKeyRevoked excludes an attestation signed by a revoked key: every
+	// attestation when the key-revocation has no Compromised-At, otherwise those
+	// with an Effective-Date at or after it, or with no usable Effective-Date.
+	ReasonKeyRevoked = "Signer key revoked"
+
+	// ReasonKeyRotated excludes an attestation signed by a rotated key whose
+	// Effective-Date is at or after the rotation's Effective-Date, or that has no
+	// usable Effective-Date.
+	ReasonKeyRotated = "Signer key rotated"
+
+	// ReasonKeyEventNotAuthorized excludes a key-rotation or key-revocation
+	// signed neither by the affected key nor by a policy Key-Recovery role.
+	ReasonKeyEventNotAuthorized = "Key event not authorized by policy"
+
+	// ReasonKeyRotationConflict excludes an authorized key-rotation of an
+	// Old-Key that an earlier-dated rotation (or, on the same date, one with a
+	// lower CID) already rotated.
+	ReasonKeyRotationConflict = "Key rotation conflicts with an earlier rotation"
+)
+
+// trustEvent is a verified attestation, valid at As-Of, that changes trust
+// itself (delegation, key-rotation, key-revocation) or may withdraw such a
+// change (revocation). Events are evaluated before per-attestation trust.
+type trustEvent struct {
+	catf    *catf.CATF
+	cid     string
+	signers []string
+}
+
+// scanTrustEvents collects the trust events among the inputs, sorted by CID.
+// Inputs that fail parsing, core validation, verification or the As-Of window
+// are skipped; the main resolver loop reports them.
+func scanTrustEvents(attestationBytes [][]byte, asOf time.Time) []trustEvent {
+	var events []trustEvent
+	for _, b := range attestationBytes {
+		a, err := catf.Parse(b)
+		if err != nil {
+			continue
+		}
+		switch a.ClaimType() {
+		case "delegation", "key-rotation", "key-revocation", "revocation":
+		default:
+			continue
+		}
+		cid, err := a.CID()
+		if err != nil || catf.ValidateCoreClaims(a) != nil || validityReason(a, asOf) != "" {
+			continue
+		}
+		signers, err := verifiedSigners(a)
+		if err != nil {
+			continue
+		}
+		events = append(events, trustEvent{catf: a, cid: cid, signers: signers})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].cid < events[j].cid })
+	return events
+}
+
+// keyCutoff excludes a revoked key's attestations from an instant onward.
+type keyCutoff struct {
+	all bool // every attestation, dated or not
+	at  time.Time
+}
+
+// keyEvents is the outcome of the authorized key-rotation and key-revocation
+// attestations among the inputs. A nil *keyEvents has no effect.
+type keyEvents struct {
+	revoked      map[string]keyCutoff
+	rotated      map[string]time.Time // old key -> rotation Effective-Date
+	predecessors map[string][]string  // new key -> keys it replaced
+	honored      map[string]bool      // CIDs of authorized key events
+	conflicting  map[string]bool      // CIDs of authorized rotations that lost to an earlier one
+}
+
+// collectKeyEvents applies key-revocations, then key-rotations, in CID order.
+//
+// A key event is authorized when signed by the affected key (Revoked-Key or
+// Old-Key) or by a key holding a Key-Recovery role in TRUST. Self-revocations
+// are applied first; a Key-Recovery revocation then counts only signers whose
+// keys those revocations leave active, and all Key-Recovery revocations are
+// judged against that same state so the outcome does not depend on CID order.
+//
+// Revocations are applied before rotations so that a rotation signed by a
+// compromised key is ignored: Old-Key can rotate itself only with an
+// Effective-Date before its Compromised-At, and not at all when revoked
+// without one. Only the earliest authorized rotation of each Old-Key (lowest
+// CID on a tie) is applied; the others are ReasonKeyRotationConflict.
+func collectKeyEvents(events []trustEvent, policy *tpdl.Policy, trust *policyTrust) *keyEvents {
+	ke := &keyEvents{
+		revoked:      make(map[string]keyCutoff),
+		rotated:      make(map[string]time.Time),
+		predecessors: make(map[string][]string),
+		honored:      make(map[string]bool),
+		conflicting:  make(map[string]bool),
+	}
+	recovery := func(a *catf.CATF, signers []string) bool {
+		if policy == nil {
+			return false
+		}
+		for _, k := range signers {
+			roles := trust.rolesFor(k, a)
+			for _, r := range policy.KeyRecoveryAllowedBy {
+				if roles[r] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	revoke := func(e trustEvent) {
+		claims := e.catf.Sections["CLAIMS"].Pairs
+		key := claims["Revoked-Key"]
+		ke.honored[e.cid] = true
+		cut := keyCutoff{all: true}
+		if s, ok := claims["Compromised-At"]; ok {
+			if t, err := parseClaimTime(s); err == nil {
+				cut = keyCutoff{at: t.UTC()}
+			}
+		}
+		if prev, ok := ke.revoked[key]; ok && (prev.all || (!cut.all && prev.at.Before(cut.at))) {
+			return
+		}
+		ke.revoked[key] = cut
+	}
+
+	var byRecovery []trustEvent
+	for _, e := range events {
+		if e.catf.ClaimType() != "key-revocation" {
+			continue
+		}
+		if containsString(e.signers, e.catf.Sections["CLAIMS"].Pairs["Revoked-Key"]) {
+			revoke(e)
+		} else {
+			byRecovery = append(byRecovery, e)
+		}
+	}
+	var recovered []trustEvent
+	for _, e := range byRecovery {
+		if signers, _ := ke.activeSigners(e.catf, e.cid, e.signers); recovery(e.catf, signers) {
+			recovered = append(recovered, e)
+		}
+	}
+	for _, e := range recovered {
+		revoke(e)
+	}
+
+	type rotation struct {
+		cid            string
+		oldKey, newKey string
+		at             time.Time
+	}
+	var rotations []rotation
+	for _, e := range events {
+		if e.catf.ClaimType() != "key-rotation" {
+			continue
+		}
+		claims := e.catf.Sections["CLAIMS"].Pairs
+		oldKey, newKey := claims["Old-Key"], claims["New-Key"]
+		// Core validation requires a key-rotation's Effective-Date.
+		at, dated := issuedAt(e.catf)
+		if !dated {
+			continue
+		}
+		signers, _ := ke.activeSigners(e.catf, e.cid, e.signers)
+		cut, compromised := ke.revoked[oldKey]
+		self := containsString(signers, oldKey) && (!compromised || (!cut.all && cut.at.After(at)))
+		if !self && !recovery(e.catf, signers) {
+			continue
+		}
+		ke.honored[e.cid] = true
+		rotations = append(rotations, rotation{cid: e.cid, oldKey: oldKey, newKey: newKey, at: at})
+	}
+	// events are in CID order, so a stable sort by date breaks ties by CID.
+	sort.SliceStable(rotations, func(i, j int) bool { return rotations[i].at.Before(rotations[j].at) })
+	for _, r := range rotations {
+		if _, ok := ke.rotated[r.oldKey]; ok {
+			ke.conflicting[r.cid] = true
+			continue
+		}
+		ke.rotated[r.oldKey] = r.at
+		ke.predecessors[r.newKey] = appendUniqueSorted(ke.predecessors[r.newKey], r.oldKey)
+	}
+	return ke
+}
+
+// issuedAt returns an attestation's Effective-Date; dated is false when the
+// claim is absent or unparseable.
+func issuedAt(a *catf.CATF) (at time.Time, dated bool) {
+	s, ok := a.Sections["CLAIMS"].Pairs["Effective-Date"]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := parseClaimTime(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
+
+// activeSigners drops the signers whose keys were revoked or rotated before a
+// was issued and reports why (ReasonKeyRevoked takes precedence). An undated
+// attestation cannot show that it predates either event, so a revoked or
+// rotated key never signs one. Authorized
+// key events are exempt, so a key can always revoke or rotate itself.
+func (ke *keyEvents) activeSigners(a *catf.CATF, cid string, signers []string) ([]string, string) {
+	if ke == nil || ke.honored[cid] || (len(ke.revoked) == 0 && len(ke.rotated) == 0) {
+		return signers, ""
+	}
+	at, dated := issuedAt(a)
+	var out []string
+	reason := ""
+	for _, k := range signers {
+		if c, ok := ke.revoked[k]; ok && (c.all || !dated || !at.Before(c.at)) {
+			reason = ReasonKeyRevoked
+			continue
+		}
+		if t, ok := ke.rotated[k]; ok && (!dated || !at.Before(t)) {
+			if reason == "" {
+				reason = ReasonKeyRotated
+			}
+			continue
+		}
+		out = append(out, k)
+	}
+	return out, reason
+}
+
+// eventReason reports ReasonKeyEventNotAuthorized or ReasonKeyRotationConflict
+// for key events that were not applied, and "" otherwise.
+func (ke *keyEvents) eventReason(a *catf.CATF, cid string) string {
+	switch a.ClaimType() {
+	case "key-rotation", "key-revocation":
+		if ke == nil || !ke.honored[cid] {
+			return ReasonKeyEventNotAuthorized
+		}
+		if ke.conflicting[cid] {
+			return ReasonKeyRotationConflict
+		}
+	}
+	return ""
+}
+
+// trust extends policy trust so that each rotated-to key holds the TRUST
+// entries of the keys it replaced (see policyTrust.withSuccessors).
+func (ke *keyEvents) trust(base *policyTrust) *policyTrust {
+	if ke == nil {
+		return base
+	}
+	return base.withSuccessors(ke.predecessors)
+}
+
+// isTrustEventType reports whether claims of this type change trust rather
+// than making a semantic claim about the subject.
+func isTrustEventType(typ string) bool {
+	switch typ {
+	case "revocation", "delegation", "key-rotation", "key-revocation", "policy-supersedes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+)
+
+func withKeyRecovery(policy string, roles string) []byte {
+	policy = strings.Replace(policy, "Spec: xdao-tpdl-1", "Spec: xdao-tpdl-2", 1)
+	return []byte(strings.Replace(policy, "-----END XDAO TRUST POLICY-----",
+		"Key-Recovery:\n  Allowed-By: "+roles+"\n-----END XDAO TRUST POLICY-----", 1))
+}
+
+func approvalOn(t *testing.T, subject, date, issuer string, priv []byte) []byte {
+	t.Helper()
+	return mustAttestation(t, subject, "Deed", map[string]string{"Effective-Date": date, "Role": "buyer", "Type": "approval"}, issuer, priv)
+}
+
+func TestResolveKeyRotation_NewKeyInheritsRoles(t *testing.T) {
+	subject := "bafy-rotated-deed"
+	oldPub, oldPriv := mustKeypair(t, 0x51)
+	newPub, newPriv := mustKeypair(t, 0x52)
+	oldKey, newKey := issuerKey(oldPub), issuerKey(newPub)
+
+	rotation := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"Effective-Date": "2026-06-01",
+		"New-Key":        newKey,
+		"Old-Key":        oldKey,
+		"Type":           "key-rotation",
+	}, oldKey, oldPriv)
+	before := approvalOn(t, subject, "2026-05-01", oldKey, oldPriv)
+	after := approvalOn(t, subject, "2026-07-01", oldKey, oldPriv)
+	byNew := approvalOn(t, subject, "2026-07-02", newKey, newPriv)
+	policy := []byte(trustPolicy([]trustEntry{{oldKey, "buyer"}}, []requireRule{{"approval", "buyer", 2}}))
+
+	undated := mustAttestation(t, subject, "Deed", map[string]string{"Role": "buyer", "Type": "authorship"}, oldKey, oldPriv)
+	undatedRotation := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"New-Key": newKey,
+		"Old-Key": oldKey,
+		"Type":    "key-rotation",
+	}, oldKey, oldPriv)
+
+	res, err := Resolve([][]byte{rotation, before, after, byNew, undated, undatedRotation}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved, got %s", res.State)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, byNew)); !v.Trusted || len(v.TrustRoles) != 1 || v.TrustRoles[0] != "buyer" {
+		t.Fatalf("new key verdict %+v", v)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, before)); !v.Trusted {
+		t.Fatalf("pre-rotation attestation not trusted: %+v", v)
+	}
+	assertExclusion(t, res, mustCID(t, after), ReasonKeyRotated)
+	// An undated attestation cannot show it predates the rotation.
+	assertExclusion(t, res, mustCID(t, undated), ReasonKeyRotated)
+	assertExclusion(t, res, mustCID(t, undatedRotation), "CATF-VAL-264")
+}
+
+func TestResolveKeyRevocation_CompromisedAt(t *testing.T) {
+	subject := "bafy-compromised-deed"
+	buyerPub, buyerPriv := mustKeypair(t, 0x53)
+	custodianPub, custodianPriv := mustKeypair(t, 0x54)
+	attackerPub, attackerPriv := mustKeypair(t, 0x9f)
+	buyer, custodian, attacker := issuerKey(buyerPub), issuerKey(custodianPub), issuerKey(attackerPub)
+
+	revokeKey := mustAttestation(t, "bafy-key-revocation", "Compromise", map[string]string{
+		"Compromised-At": "2026-03-01T00:00:00Z",
+		"Revoked-Key":    buyer,
+		"Type":           "key-revocation",
+	}, custodian, custodianPriv)
+	early := approvalOn(t, subject, "2026-02-01", buyer, buyerPriv)
+	late := approvalOn(t, subject, "2026-04-01", buyer, buyerPriv)
+	undated := mustAttestation(t, subject, "Deed", map[string]string{"Role": "buyer", "Type": "authorship"}, buyer, buyerPriv)
+	// The compromised key rotates itself to the attacker's key after
+	// Compromised-At.
+	hijack := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"Effective-Date": "2026-03-15",
+		"New-Key":        attacker,
+		"Old-Key":        buyer,
+		"Type":           "key-rotation",
+	}, buyer, buyerPriv)
+	byAttacker := approvalOn(t, subject, "2026-04-02", attacker, attackerPriv)
+	base := trustPolicy([]trustEntry{{buyer, "buyer"}, {custodian, "custodian"}}, []requireRule{{"approval", "buyer", 1}})
+	atts := [][]byte{revokeKey, early, late, undated, hijack, byAttacker}
+
+	// Without a Key-Recovery role the custodian cannot revoke the buyer's key.
+	res, err := Resolve([][]byte{revokeKey, early, late, undated}, []byte(base), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	assertExclusion(t, res, mustCID(t, revokeKey), ReasonKeyEventNotAuthorized)
+	if v := findVerdict(t, res.Verdicts, mustCID(t, late)); !v.Trusted {
+		t.Fatalf("unauthorized revocation applied: %+v", v)
+	}
+
+	res, err = Resolve(atts, withKeyRecovery(base, "custodian"), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved from the pre-compromise approval, got %s", res.State)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, early)); !v.Trusted {
+		t.Fatalf("pre-compromise approval not trusted: %+v", v)
+	}
+	assertExclusion(t, res, mustCID(t, late), ReasonKeyRevoked)
+	assertExclusion(t, res, mustCID(t, undated), ReasonKeyRevoked)
+	// A rotation signed by the compromised key after Compromised-At is not
+	// honored, so the attacker's key inherits nothing.
+	assertExclusion(t, res, mustCID(t, hijack), ReasonKeyRevoked)
+	assertExclusion(t, res, mustCID(t, byAttacker), "Issuer not trusted")
+	if v := findVerdict(t, res.Verdicts, mustCID(t, revokeKey)); !v.Trusted {
+		t.Fatalf("key revocation verdict %+v", v)
+	}
+}
+
+func TestResolveKeyRevocation_SelfRevocationAndValidation(t *testing.T) {
+	subject := "bafy-self-revoked"
+	pub, priv := mustKeypair(t, 0x55)
+	key := issuerKey(pub)
+
+	self := mustAttestation(t, "bafy-key-revocation", "Lost key", map[string]string{
+		"Revoked-Key": key,
+		"Type":        "key-revocation",
+	}, key, priv)
+	badDate := mustAttestation(t, "bafy-key-revocation", "Lost key", map[string]string{
+		"Compromised-At": "last week",
+		"Revoked-Key":    key,
+		"Type":           "key-revocation",
+	}, key, priv)
+	sameKey := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"New-Key": key,
+		"Old-Key": key,
+		"Type":    "key-rotation",
+	}, key, priv)
+	approval := approvalOn(t, subject, "2026-01-01", key, priv)
+	policy := []byte(trustPolicy([]trustEntry{{key, "buyer"}}, []requireRule{{"approval", "buyer", 1}}))
+
+	res, err := Resolve([][]byte{self, badDate, sameKey, approval}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, approval), ReasonKeyRevoked)
+	assertExclusion(t, res, mustCID(t, badDate), "CATF-VAL-272")
+	assertExclusion(t, res, mustCID(t, sameKey), "CATF-VAL-263")
+}
+
+func TestResolveKeyRotation_SurvivesLaterCompromise(t *testing.T) {
+	subject := "bafy-rotated-then-compromised"
+	oldPub, oldPriv := mustKeypair(t, 0x56)
+	newPub, newPriv := mustKeypair(t, 0x57)
+	custodianPub, custodianPriv := mustKeypair(t, 0x58)
+	oldKey, newKey, custodian := issuerKey(oldPub), issuerKey(newPub), issuerKey(custodianPub)
+
+	rotation := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"Effective-Date": "2026-01-01",
+		"New-Key":        newKey,
+		"Old-Key":        oldKey,
+		"Type":           "key-rotation",
+	}, oldKey, oldPriv)
+	compromise := mustAttestation(t, "bafy-key-revocation", "Compromise", map[string]string{
+		"Compromised-At": "2026-03-01T00:00:00Z",
+		"Revoked-Key":    oldKey,
+		"Type":           "key-revocation",
+	}, custodian, custodianPriv)
+	byNew := approvalOn(t, subject, "2026-04-01", newKey, newPriv)
+	base := trustPolicy([]trustEntry{{oldKey, "buyer"}, {custodian, "custodian"}}, []requireRule{{"approval", "buyer", 1}})
+
+	res, err := Resolve([][]byte{rotation, compromise, byNew}, withKeyRecovery(base, "custodian"), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved, got %s", res.State)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, rotation)); !v.Trusted {
+		t.Fatalf("rotation before the compromise not honored: %+v", v)
+	}
+	if v := findVerdict(t, res.Verdicts, mustCID(t, byNew)); !v.Trusted || len(v.TrustRoles) != 1 || v.TrustRoles[0] != "buyer" {
+		t.Fatalf("new key lost its inherited trust: %+v", v)
+	}
+}
+
+func TestResolveKeyRotation_EarliestRotationWins(t *testing.T) {
+	subject := "bafy-competing-rotations"
+	oldPub, oldPriv := mustKeypair(t, 0x59)
+	firstPub, firstPriv := mustKeypair(t, 0x5a)
+	secondPub, secondPriv := mustKeypair(t, 0x5b)
+	oldKey, first, second := issuerKey(oldPub), issuerKey(firstPub), issuerKey(secondPub)
+
+	toFirst := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"Effective-Date": "2026-01-01",
+		"New-Key":        first,
+		"Old-Key":        oldKey,
+		"Type":           "key-rotation",
+	}, oldKey, oldPriv)
+	// Dated earlier, so it wins over toFirst whatever the CIDs.
+	toSecond := mustAttestation(t, "bafy-key-rotation", "Rotation", map[string]string{
+		"Effective-Date": "2025-12-31",
+		"New-Key":        second,
+		"Old-Key":        oldKey,
+		"Type":           "key-rotation",
+	}, oldKey, oldPriv)
+	byFirst := approvalOn(t, subject, "2026-02-01", first, firstPriv)
+	bySecond := approvalOn(t, subject, "2026-02-01", second, secondPriv)
+	policy := []byte(trustPolicy([]trustEntry{{oldKey, "buyer"}}, []requireRule{{"approval", "buyer", 1}}))
+
+	res, err := Resolve([][]byte{toFirst, toSecond, byFirst, bySecond}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	assertExclusion(t, res, mustCID(t, toFirst), ReasonKeyRotationConflict)
+	assertExclusion(t, res, mustCID(t, byFirst), "Issuer not trusted")
+	if v := findVerdict(t, res.Verdicts, mustCID(t, bySecond)); !v.Trusted {
+		t.Fatalf("earliest rotation not applied: %+v", v)
+	}
+}
+
+func TestResolveKeyRevocation_RevokedRecoveryKeyCannotRevoke(t *testing.T) {
+	subject := "bafy-revoked-custodian"
+	buyerPub, buyerPriv := mustKeypair(t, 0x5c)
+	custodianPub, custodianPriv := mustKeypair(t, 0x5d)
+	buyer, custodian := issuerKey(buyerPub), issuerKey(custodianPub)
+
+	custodianLost := mustAttestation(t, "bafy-key-revocation", "Lost key", map[string]string{
+		"Revoked-Key": custodian,
+		"Type":        "key-revocation",
+	}, custodian, custodianPriv)
+	revokeBuyer := mustAttestation(t, "bafy-key-revocation", "Compromise", map[string]string{
+		"Revoked-Key": buyer,
+		"Type":        "key-revocation",
+	}, custodian, custodianPriv)
+	approval := approvalOn(t, subject, "2026-01-01", buyer, buyerPriv)
+	base := trustPolicy([]trustEntry{{buyer, "buyer"}, {custodian, "custodian"}}, []requireRule{{"approval", "buyer", 1}})
+
+	res, err := Resolve([][]byte{custodianLost, revokeBuyer, approval}, withKeyRecovery(base, "custodian"), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	assertExclusion(t, res, mustCID(t, revokeBuyer), ReasonKeyRevoked)
+	if v := findVerdict(t, res.Verdicts, mustCID(t, approval)); !v.Trusted {
+		t.Fatalf("revoked recovery key revoked the buyer: %+v", v)
+	}
+}
@@ -2,15 +2,26 @@ package resolver
 
 import (
 	"sort"
+	"time"
 
-	"xdao.co/catf/catf"
 	"xdao.co/catf/tpdl"
 )
 
 type NameResolution struct {
-	Name    string
+	Name string
+
+	// Version is the requested version for exact lookups, or the selected
+	// version when a semver selector (range or latest) chose one.
 	Version string
 
+	// Selector is the semver selector expression ("latest" or a range);
+	// empty for exact or any-version lookups.
+	Selector string
+
+	// ConsideredVersions lists, in ascending semver order, every version of
+	// trusted non-revoked bindings that matched Selector.
+	ConsideredVersions []string
+
 	State      State
 	Confidence Confidence
 
@@ -24,6 +35,10 @@ type NameResolution struct {
 	Verdicts   []Verdict
 
 	PolicyVerdicts []PolicyVerdict
+
+	// AsOf is the instant validity windows were evaluated at; see
+	// Resolution.AsOf.
+	AsOf time.Time
 }
 
 type NameFork struct {
@@ -38,104 +53,41 @@ func ResolveName(attestationBytes [][]byte, policyBytes []byte, name, version st
 	if err != nil {
 		return nil, err
 	}
-	return resolveNameWithPolicy(attestationBytes, policy, name, version)
+	return resolveNameWithPolicy(attestationBytes, policy, name, ExactVersion(version), time.Time{})
 }
 
-func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name, version string) (*NameResolution, error) {
-	trustIndex := indexTrust(policy)
-
-	var atts []*attestation
-	var exclusions []Exclusion
-	var verdicts []Verdict
-	verdictIndex := make(map[string]int)
-
-	for _, b := range attestationBytes {
-		v := Verdict{}
-		a, perr := catf.Parse(b)
-		if perr != nil {
-			v.CID = ""
-			v.InputHash = inputHash(b)
-			v.Status = VerdictInvalid
-			v.ExcludedReason = "CATF parse/canonicalization failed"
-			v.Reasons = []string{stableCATFReason(perr)}
-			verdicts = append(verdicts, v)
-			exclusions = append(exclusions, Exclusion{CID: v.CID, InputHash: v.InputHash, Reason: v.ExcludedReason})
-			continue
-		}
-		cid, err := a.CID()
-		if err != nil {
-			v.CID = ""
-			v.InputHash = inputHash(b)
-			v.Status = VerdictInvalid
-			v.ExcludedReason = "CATF parse/canonicalization failed"
-			v.Reasons = []string{stableCATFReason(err)}
-			verdicts = append(verdicts, v)
-			exclusions = append(exclusions, Exclusion{CID: v.CID, InputHash: v.InputHash, Reason: v.ExcludedReason})
-			continue
-		}
-		v.CID = cid
-		v.IssuerKey = a.IssuerKey()
-		v.ClaimType = a.ClaimType()
-		v.AttestedSubjectCID = a.SubjectCID()
-		if err := catf.ValidateCoreClaims(a); err != nil {
-			v.Status = VerdictInvalid
-			v.ExcludedReason = stableCATFReason(err)
-			v.Reasons = []string{v.ExcludedReason}
-			verdicts = append(verdicts, v)
-			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
-			continue
-		}
-		if err := a.Verify(); err != nil {
-			v.Status = VerdictInvalid
-			v.ExcludedReason = "Signature invalid"
-			v.Reasons = []string{v.ExcludedReason}
-			verdicts = append(verdicts, v)
-			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
-			continue
-		}
-		att := &attestation{catf: a, cid: cid}
-		if roles, ok := trustIndex[a.IssuerKey()]; ok {
-			att.trusted = true
-			att.trustRoles = roles
-			v.Trusted = true
-			v.Status = VerdictTrusted
-			v.Reasons = []string{"Issuer trusted by policy"}
-			for r := range roles {
-				v.TrustRoles = append(v.TrustRoles, r)
-			}
-			sort.Strings(v.TrustRoles)
-		} else {
-			v.Status = VerdictExcluded
-			v.ExcludedReason = "Issuer not trusted"
-			v.Reasons = []string{v.ExcludedReason}
-			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
-		}
-		verdictIndex[cid] = len(verdicts)
-		verdicts = append(verdicts, v)
-		atts = append(atts, att)
+// ResolveNameSelect resolves a symbolic name, choosing among versions with sel.
+//
+// For range and latest selectors, candidate bindings are grouped by parsed
+// semantic version (non-semver Version claims never match) and versions are
+// tried from highest to lowest; the first whose bindings satisfy the policy
+// rules is selected. If none does, the highest version is reported as
+// Unresolved. Exact and any-version selectors behave like ResolveName.
+func ResolveNameSelect(attestationBytes [][]byte, policyBytes []byte, name string, sel VersionSelector) (*NameResolution, error) {
+	policy, err := tpdl.Parse(policyBytes)
+	if err != nil {
+		return nil, err
 	}
+	return resolveNameWithPolicy(attestationBytes, policy, name, sel, time.Time{})
+}
 
-	sort.Slice(atts, func(i, j int) bool { return atts[i].cid < atts[j].cid })
-	applyRevocations(atts)
-	for _, a := range atts {
-		if !a.revoked {
-			continue
-		}
-		if idx, ok := verdictIndex[a.cid]; ok {
-			verdicts[idx].Revoked = true
-			if len(a.revokedBy) > 0 {
-				verdicts[idx].RevokedBy = appendUniqueSorted(append([]string(nil), a.revokedBy...))
-			}
-			verdicts[idx].Status = VerdictRevoked
-			verdicts[idx].Reasons = appendUniqueSorted(verdicts[idx].Reasons, "Revoked")
-		}
+func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name string, sel VersionSelector, asOf time.Time) (*NameResolution, error) {
+	asOf = normalizeAsOf(asOf)
+	atts, exclusions, verdicts := verifyInputs(attestationBytes, policy, asOf, nil, nil)
+	res := resolveNameVerified(atts, exclusions, verdicts, policy, name, sel)
+	res.AsOf = asOf
+	return res, nil
+}
+
+// resolveNameVerified applies §5.1 to inputs already processed by verifyInputs.
+func resolveNameVerified(atts []*attestation, exclusions []Exclusion, verdicts []Verdict, policy *tpdl.Policy, name string, sel VersionSelector) *NameResolution {
+	res := &NameResolution{Name: name, Confidence: ConfidenceUndefined, Exclusions: exclusions, Verdicts: verdicts}
+	if sel.Kind == VersionExact {
+		res.Version = sel.Expr
 	}
-	for i := range verdicts {
-		verdicts[i].Reasons = appendUniqueSorted(verdicts[i].Reasons)
+	if sel.semverSelect() {
+		res.Selector = sel.Expr
 	}
-	sort.SliceStable(verdicts, func(i, j int) bool { return verdictLessV2(verdicts[i], verdicts[j]) })
-
-	res := &NameResolution{Name: name, Version: version, Confidence: ConfidenceUndefined, Exclusions: exclusions, Verdicts: verdicts}
 
 	// Collect all name-binding attestations for the requested name (+ optional version).
 	var candidates []*attestation
@@ -151,7 +103,7 @@ func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name,
 		if c["Name"] != name {
 			continue
 		}
-		if version != "" && c["Version"] != version {
+		if !sel.matches(c["Version"]) {
 			continue
 		}
 		if a.revoked {
@@ -167,7 +119,11 @@ func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name,
 		} else {
 			res.State = StateUnresolved
 		}
-		return res, nil
+		return res
+	}
+
+	if sel.semverSelect() {
+		candidates = selectSemverCandidates(res, policy, candidates)
 	}
 
 	// Apply trust policy quorum/role requirements to name-binding evidence.
@@ -178,7 +134,7 @@ func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name,
 	if !ok {
 		res.State = StateUnresolved
 		res.Confidence = ConfidenceUndefined
-		return res, nil
+		return res
 	}
 
 	// Construct supersession DAG among name-bindings.
@@ -221,11 +177,41 @@ func resolveNameWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, name,
 		}
 		res.State = StateResolved
 		res.Confidence = ConfidenceHigh
-		return res, nil
+		return res
 	}
 
 	res.State = StateForked
 	res.Confidence = ConfidenceMedium
 	res.Forks = []NameFork{{ID: "name-fork-1", ConflictingBinding: heads}}
-	return res, nil
+	return res
+}
+
+// selectSemverCandidates narrows candidates to a single version chosen by
+// semver precedence and records the considered versions on res.
+func selectSemverCandidates(res *NameResolution, policy *tpdl.Policy, candidates []*attestation) []*attestation {
+	byVersion := make(map[string][]*attestation)
+	var versions []semver
+	for _, a := range candidates {
+		raw := a.catf.Sections["CLAIMS"].Pairs["Version"]
+		if _, ok := byVersion[raw]; !ok {
+			v, _ := parseSemver(raw)
+			versions = append(versions, v)
+		}
+		byVersion[raw] = append(byVersion[raw], a)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareSemver(versions[i], versions[j]) < 0 })
+	for _, v := range versions {
+		res.ConsideredVersions = append(res.ConsideredVersions, v.raw)
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		raw := versions[i].raw
+		if _, ok := evaluatePolicyRules(policy, byVersion[raw], "name-binding"); ok {
+			res.Version = raw
+			return byVersion[raw]
+		}
+	}
+	highest := versions[len(versions)-1].raw
+	res.Version = highest
+	return byVersion[highest]
 }
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"strings"
 	"testing"
 
 	"xdao.co/catf/catf"
@@ -173,3 +174,67 @@ func TestResolveName_UntrustedBindingIsExcludedAndNotSelected(t *testing.T) {
 		t.Fatalf("expected attacker exclusion, got %+v", res.Exclusions)
 	}
 }
+
+func TestResolveNameSelect_LatestPicksHighestPolicySatisfyingVersion(t *testing.T) {
+	pubA, privA := mustKeypair(t, 0xF7)
+	pubB, privB := mustKeypair(t, 0xF8)
+	binding := func(version, pointsTo string, pub []byte, priv []byte) []byte {
+		return mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+			"Name":      "contracts.purchase",
+			"Points-To": pointsTo,
+			"Type":      "name-binding",
+			"Version":   version,
+		}, issuerKey(pub), priv)
+	}
+	atts := [][]byte{
+		binding("1.2.0", "bafy-doc-120", pubA, privA),
+		binding("1.10.0", "bafy-doc-1100", pubA, privA),
+		binding("2.0.0", "bafy-doc-200", pubB, privB),
+		binding("2.1.0-rc.1", "bafy-doc-210rc", pubA, privA),
+		binding("final", "bafy-doc-final", pubA, privA),
+	}
+	policy := trustPolicy(
+		[]trustEntry{{issuerKey(pubA), "registrar"}, {issuerKey(pubB), "observer"}},
+		[]requireRule{{"name-binding", "registrar", 1}},
+	)
+
+	res, err := ResolveNameSelect(atts, []byte(policy), "contracts.purchase", LatestVersion())
+	if err != nil {
+		t.Fatalf("ResolveNameSelect error: %v", err)
+	}
+	// 2.0.0 is only bound by an observer, so the highest satisfying version is 1.10.0 (not 1.2.0 by string order).
+	if res.State != StateResolved || res.Version != "1.10.0" || res.PointsTo != "bafy-doc-1100" {
+		t.Fatalf("expected Resolved 1.10.0 -> bafy-doc-1100, got %s %q -> %q", res.State, res.Version, res.PointsTo)
+	}
+	if res.Selector != "latest" {
+		t.Fatalf("expected Selector latest, got %q", res.Selector)
+	}
+	want := []string{"1.2.0", "1.10.0", "2.0.0"}
+	if strings.Join(res.ConsideredVersions, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected considered versions: %v", res.ConsideredVersions)
+	}
+
+	sel, err := SemverRange("~1.2.0")
+	if err != nil {
+		t.Fatalf("SemverRange: %v", err)
+	}
+	res, err = ResolveNameSelect(atts, []byte(policy), "contracts.purchase", sel)
+	if err != nil {
+		t.Fatalf("ResolveNameSelect error: %v", err)
+	}
+	if res.Version != "1.2.0" || res.PointsTo != "bafy-doc-120" || len(res.ConsideredVersions) != 1 {
+		t.Fatalf("expected range to select 1.2.0, got %q -> %q (%v)", res.Version, res.PointsTo, res.ConsideredVersions)
+	}
+
+	sel, err = SemverRange(">=2.0.0")
+	if err != nil {
+		t.Fatalf("SemverRange: %v", err)
+	}
+	res, err = ResolveNameSelect(atts, []byte(policy), "contracts.purchase", sel)
+	if err != nil {
+		t.Fatalf("ResolveNameSelect error: %v", err)
+	}
+	if res.State == StateResolved || res.Version != "2.0.0" {
+		t.Fatalf("expected unsatisfied 2.0.0 to be reported unresolved, got %s %q", res.State, res.Version)
+	}
+}
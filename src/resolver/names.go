@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"sort"
+	"time"
+
+	"xdao.co/catf/tpdl"
+)
+
+// NameListing enumerates every name/version bound by trusted name-binding
+// attestations in an input set.
+type NameListing struct {
+	// Entries are sorted by Name, then by Version (semantic versions first in
+	// precedence order, then other version strings lexicographically).
+	Entries []NameListEntry
+
+	Exclusions []Exclusion
+	Verdicts   []Verdict
+}
+
+// NameListEntry is the resolution state of one name/version, as ResolveName
+// would report it for an exact lookup.
+type NameListEntry struct {
+	Name    string
+	Version string
+
+	State      State
+	Confidence Confidence
+
+	// PointsTo is set only when State is Resolved.
+	PointsTo string
+
+	// Head binding CIDs (one when Resolved, multiple when Forked)
+	Bindings []string
+
+	PolicyVerdicts []PolicyVerdict
+}
+
+// ListNames scans trusted name-binding attestations under policy and resolves
+// every distinct Name/Version pair they bind, including pairs whose bindings
+// are all revoked (reported as Revoked).
+//
+// Inputs are verified once; each entry is identical to the corresponding
+// ResolveName(attestationBytes, policyBytes, name, version) result.
+func ListNames(attestationBytes [][]byte, policyBytes []byte) (*NameListing, error) {
+	policy, err := tpdl.Parse(policyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return listNamesWithPolicy(attestationBytes, policy), nil
+}
+
+func listNamesWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy) *NameListing {
+	atts, exclusions, verdicts := verifyInputs(attestationBytes, policy, time.Time{}, nil, nil)
+
+	type nameVersion struct{ name, version string }
+	seen := make(map[nameVersion]bool)
+	var keys []nameVersion
+	for _, a := range atts {
+		if !a.trusted || a.catf.ClaimType() != "name-binding" {
+			continue
+		}
+		c := a.catf.Sections["CLAIMS"].Pairs
+		k := nameVersion{name: c["Name"], version: c["Version"]}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return versionLess(keys[i].version, keys[j].version)
+	})
+
+	out := &NameListing{Entries: make([]NameListEntry, 0, len(keys)), Exclusions: exclusions, Verdicts: verdicts}
+	for _, k := range keys {
+		res := resolveNameVerified(atts, exclusions, verdicts, policy, k.name, ExactVersion(k.version))
+		out.Entries = append(out.Entries, NameListEntry{
+			Name:           res.Name,
+			Version:        res.Version,
+			State:          res.State,
+			Confidence:     res.Confidence,
+			PointsTo:       res.PointsTo,
+			Bindings:       res.Bindings,
+			PolicyVerdicts: res.PolicyVerdicts,
+		})
+	}
+	return out
+}
+
+// versionLess orders semantic versions by precedence ahead of non-semver
+// version strings, which sort lexicographically.
+func versionLess(a, b string) bool {
+	va, aok := parseSemver(a)
+	vb, bok := parseSemver(b)
+	switch {
+	case aok && bok:
+		return compareSemver(va, vb) < 0
+	case aok != bok:
+		return aok
+	default:
+		return a < b
+	}
+}
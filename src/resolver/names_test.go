@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"testing"
+
+	"xdao.co/catf/catf"
+)
+
+func TestListNames_EnumeratesNamesAndVersions(t *testing.T) {
+	pub, priv := mustKeypair(t, 0xE1)
+	untrustedPub, untrustedPriv := mustKeypair(t, 0xE2)
+	binding := func(name, version, pointsTo string) []byte {
+		return mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+			"Name":      name,
+			"Points-To": pointsTo,
+			"Type":      "name-binding",
+			"Version":   version,
+		}, issuerKey(pub), priv)
+	}
+
+	revoked := binding("contracts.lease", "1.0.0", "bafy-lease-1")
+	p, err := catf.Parse(revoked)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	revokedCID, err := p.CID()
+	if err != nil {
+		t.Fatalf("CID: %v", err)
+	}
+	revocation := mustAttestation(t, "bafy-name-record", "Revocation", map[string]string{
+		"Target-Attestation": revokedCID,
+		"Type":               "revocation",
+	}, issuerKey(pub), priv)
+	untrusted := mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+		"Name":      "contracts.rogue",
+		"Points-To": "bafy-rogue",
+		"Type":      "name-binding",
+		"Version":   "1.0.0",
+	}, issuerKey(untrustedPub), untrustedPriv)
+
+	atts := [][]byte{
+		binding("contracts.purchase", "1.10.0", "bafy-purchase-110"),
+		binding("contracts.purchase", "final", "bafy-purchase-final"),
+		binding("contracts.purchase", "1.2.0", "bafy-purchase-120a"),
+		binding("contracts.purchase", "1.2.0", "bafy-purchase-120b"),
+		revoked,
+		revocation,
+		untrusted,
+	}
+	policy := trustPolicy([]trustEntry{{issuerKey(pub), "registrar"}}, nil)
+
+	listing, err := ListNames(atts, []byte(policy))
+	if err != nil {
+		t.Fatalf("ListNames error: %v", err)
+	}
+
+	want := []struct {
+		name, version string
+		state         State
+		pointsTo      string
+	}{
+		{"contracts.lease", "1.0.0", StateRevoked, ""},
+		{"contracts.purchase", "1.2.0", StateForked, ""},
+		{"contracts.purchase", "1.10.0", StateResolved, "bafy-purchase-110"},
+		{"contracts.purchase", "final", StateResolved, "bafy-purchase-final"},
+	}
+	if len(listing.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), listing.Entries)
+	}
+	for i, w := range want {
+		e := listing.Entries[i]
+		if e.Name != w.name || e.Version != w.version || e.State != w.state || e.PointsTo != w.pointsTo {
+			t.Fatalf("entry %d: got %s@%s %s -> %q, want %s@%s %s -> %q", i, e.Name, e.Version, e.State, e.PointsTo, w.name, w.version, w.state, w.pointsTo)
+		}
+	}
+	if len(listing.Entries[1].Bindings) != 2 {
+		t.Fatalf("expected forked entry to list 2 head bindings, got %v", listing.Entries[1].Bindings)
+	}
+
+	// Each entry matches an exact ResolveName lookup.
+	for _, e := range listing.Entries {
+		res, err := ResolveName(atts, []byte(policy), e.Name, e.Version)
+		if err != nil {
+			t.Fatalf("ResolveName error: %v", err)
+		}
+		if res.State != e.State || res.PointsTo != e.PointsTo || len(res.Bindings) != len(e.Bindings) {
+			t.Fatalf("ListNames entry %s@%s disagrees with ResolveName", e.Name, e.Version)
+		}
+	}
+}
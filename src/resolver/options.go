@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"fmt"
+	"time"
 
 	"xdao.co/catf/compliance"
 )
@@ -11,6 +12,15 @@ import (
 // Default behavior is Permissive when Options{} is used.
 type Options struct {
 	Mode compliance.ComplianceMode
+
+	// AsOf, when non-zero, resolves as of that instant: attestations whose
+	// Effective-Date is after AsOf, or whose Expires is at or before AsOf, are
+	// excluded with a stable reason. Zero keeps resolution time-independent.
+	AsOf time.Time
+
+	// Trace records the resolver's decisions in Resolution.Trace. It does
+	// not change the resolution itself.
+	Trace bool
 }
 
 func (o Options) withDefaults() Options {
@@ -0,0 +1,357 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/storage"
+	"xdao.co/catf/tpdl"
+)
+
+// Stable reasons reported while resolving the effective policy (see
+// PolicyResolution).
+const (
+	// ReasonPolicySupersessionNotAuthorized excludes a policy-supersedes
+	// attestation with no signer holding a Policy-Supersedes role of the
+	// prior policy (including every supersession of a policy without a
+	// Policy-Supersedes block).
+	ReasonPolicySupersessionNotAuthorized = "Policy supersession not authorized by policy"
+
+	// ReasonPolicyQuorumNotMet excludes authorized policy-supersedes
+	// attestations whose successor was not named by the prior policy's
+	// Policy-Supersedes Quorum of distinct keys.
+	ReasonPolicyQuorumNotMet = "Policy supersession quorum not met"
+
+	// ReasonPolicyNotInChain excludes a policy-supersedes attestation whose
+	// Prior-Policy was never the effective policy.
+	ReasonPolicyNotInChain = "Prior policy not in supersession chain"
+
+	// ReasonPolicySupersessionForked reports that more than one successor of
+	// the same policy met quorum.
+	ReasonPolicySupersessionForked = "Competing policy supersessions"
+
+	// ReasonSuccessorPolicyUnavailable reports an authorized successor whose
+	// bytes were not supplied (or not found in CAS).
+	ReasonSuccessorPolicyUnavailable = "Successor policy unavailable"
+
+	// ReasonSuccessorPolicyInvalid reports an authorized successor that is not
+	// a valid, canonical TPDL policy.
+	ReasonSuccessorPolicyInvalid = "Successor policy invalid"
+
+	// ReasonPolicySupersessionCycle reports an authorized successor that is
+	// already part of the chain.
+	ReasonPolicySupersessionCycle = "Policy supersession cycle"
+)
+
+// PolicySupersession is one authorized step of a policy chain.
+type PolicySupersession struct {
+	PriorCID     string
+	SuccessorCID string
+
+	// AttestationCIDs lists the policy-supersedes attestations naming this
+	// successor that were signed by at least one authorized key, and
+	// SignerKeys the distinct authorized keys among their signers.
+	AttestationCIDs []string
+	SignerKeys      []string
+}
+
+// PolicyResolution is the outcome of resolving the effective policy from a
+// root policy and a set of policy-supersedes attestations.
+//
+// Policies are identified by the CIDv1 (raw, sha2-256) of their bytes, as in
+// CROF Trust-Policy-CID.
+type PolicyResolution struct {
+	RootCID string
+
+	// EffectiveCID is the last policy of the chain, with its parsed form and
+	// bytes. When State is not Resolved it is the last policy every authorized
+	// supersession agrees on.
+	EffectiveCID string
+	Policy       *tpdl.Policy
+	PolicyBytes  []byte
+
+	// State is Resolved when EffectiveCID has no authorized successor, Forked
+	// when several successors of it met quorum, and Unresolved when its one
+	// authorized successor could not be adopted (see Reason).
+	State  State
+	Reason string
+
+	// Chain lists the adopted supersessions from RootCID to EffectiveCID.
+	Chain []PolicySupersession
+
+	// Candidates lists the authorized successors of EffectiveCID that were not
+	// adopted, sorted; it is empty when State is Resolved.
+	Candidates []string
+
+	// Exclusions lists the policy-supersedes attestations that did not
+	// contribute to Chain, with a stable reason.
+	Exclusions []Exclusion
+}
+
+// ResolveEffectivePolicy resolves the current effective policy starting from
+// the policy whose CID is rootCID.
+//
+// policies supplies the bytes of the root and any successor policies;
+// attestationBytes may hold any attestations, of which only policy-supersedes
+// attestations (and the key events that affect their signers) are considered.
+// A policy can only be superseded when it has a Policy-Supersedes block: the
+// successor must be named by policy-supersedes attestations whose Prior-Policy
+// is that policy, signed by at least Quorum distinct keys that hold an
+// Allowed-By role in its TRUST. Keys are evaluated against the prior policy,
+// after its Deny entries and any authorized key rotations and revocations.
+// Delegated roles do not authorize supersession, and supersession cannot be
+// withdrawn by a revocation.
+//
+// Policies are identified by policy CID (tpdl.CID), so every policy in the
+// chain must be canonical: successors are matched only by the CID of
+// canonical bytes, and a non-canonical entry in policies is ignored unless
+// its raw-bytes CID is rootCID, in which case its TPDL-CANON-001 error is
+// returned.
+//
+// An error is returned only when the root policy is missing or invalid.
+func ResolveEffectivePolicy(rootCID string, policies [][]byte, attestationBytes [][]byte) (*PolicyResolution, error) {
+	byCID := make(map[string][]byte, len(policies))
+	var rootErr error
+	for _, b := range policies {
+		id, err := tpdl.CID(b)
+		if err != nil {
+			if rootErr == nil && cidutil.CIDv1RawSHA256(b) == rootCID {
+				rootErr = err
+			}
+			continue
+		}
+		byCID[id] = b
+	}
+	root, ok := byCID[rootCID]
+	if !ok {
+		if rootErr != nil {
+			return nil, rootErr
+		}
+		return nil, fmt.Errorf("resolver: root policy %s not supplied", rootCID)
+	}
+	return resolvePolicyChain(rootCID, root, attestationBytes, func(c string) ([]byte, error) {
+		return byCID[c], nil
+	})
+}
+
+// EffectivePolicyRequestCAS is the CAS counterpart of ResolveEffectivePolicy.
+//
+// RootPolicy and Attestations are hydrated as in ResolveRequestCAS. Successor
+// policies are fetched by CID from CAS as the chain is walked; a successor
+// missing from CAS makes the resolution Unresolved rather than failing.
+type EffectivePolicyRequestCAS struct {
+	RootPolicy   BlobRef
+	Attestations []BlobRef
+
+	CAS         storage.CAS
+	CASAdapters []storage.CAS
+}
+
+// ResolveEffectivePolicyWithCAS resolves the effective policy, hydrating CID
+// inputs and successor policies through an injected CAS.
+func ResolveEffectivePolicyWithCAS(req EffectivePolicyRequestCAS) (*PolicyResolution, error) {
+	return ResolveEffectivePolicyWithCASContext(context.Background(), req)
+}
+
+// ResolveEffectivePolicyWithCASContext is ResolveEffectivePolicyWithCAS with
+// cancellation.
+func ResolveEffectivePolicyWithCASContext(ctx context.Context, req EffectivePolicyRequestCAS) (*PolicyResolution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cas, err := casFromRequest(req.CAS, req.CASAdapters)
+	if err != nil {
+		return nil, err
+	}
+	root, rootCID, err := hydrateOne(ctx, req.RootPolicy, cas)
+	if err != nil {
+		return nil, fmt.Errorf("resolver: hydrate policy: %w", err)
+	}
+	attBytes := make([][]byte, 0, len(req.Attestations))
+	for i, a := range req.Attestations {
+		b, _, err := hydrateOne(ctx, a, cas)
+		if err != nil {
+			return nil, fmt.Errorf("resolver: hydrate attestation[%d]: %w", i, err)
+		}
+		attBytes = append(attBytes, b)
+	}
+	return resolvePolicyChain(rootCID.String(), root, attBytes, func(c string) ([]byte, error) {
+		id, err := cid.Decode(c)
+		if err != nil || cas == nil {
+			return nil, nil
+		}
+		b, _, err := hydrateOne(ctx, BlobRef{CID: id}, cas)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolver: hydrate policy %s: %w", c, err)
+		}
+		return b, nil
+	})
+}
+
+// policySupersession is a verified policy-supersedes attestation.
+type policySupersession struct {
+	catf      *catf.CATF
+	cid       string
+	signers   []string
+	prior     string
+	successor string
+}
+
+// resolvePolicyChain walks supersessions from the root policy. fetch returns
+// the bytes of a policy by CID, or nil when they are unavailable. The root and
+// each successor must be canonical, so that rootCID and the CIDs named by
+// policy-supersedes attestations are policy CIDs.
+func resolvePolicyChain(rootCID string, rootBytes []byte, attestationBytes [][]byte, fetch func(string) ([]byte, error)) (*PolicyResolution, error) {
+	policy, err := tpdl.Parse(rootBytes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tpdl.CanonicalizeTPDL(rootBytes); err != nil {
+		return nil, err
+	}
+	res := &PolicyResolution{RootCID: rootCID, EffectiveCID: rootCID, Policy: policy, PolicyBytes: rootBytes, State: StateResolved}
+
+	var supersessions []policySupersession
+	for _, b := range attestationBytes {
+		a, err := catf.Parse(b)
+		if err != nil || a.ClaimType() != "policy-supersedes" {
+			continue
+		}
+		cid, err := a.CID()
+		if err != nil {
+			continue
+		}
+		if err := catf.ValidateCoreClaims(a); err != nil {
+			res.Exclusions = append(res.Exclusions, Exclusion{CID: cid, Reason: stableCATFReason(err)})
+			continue
+		}
+		signers, err := verifiedSigners(a)
+		if err != nil {
+			res.Exclusions = append(res.Exclusions, Exclusion{CID: cid, Reason: "Signature invalid"})
+			continue
+		}
+		claims := a.Sections["CLAIMS"].Pairs
+		supersessions = append(supersessions, policySupersession{catf: a, cid: cid, signers: signers, prior: claims["Prior-Policy"], successor: claims["Successor-Policy"]})
+	}
+	sort.Slice(supersessions, func(i, j int) bool { return supersessions[i].cid < supersessions[j].cid })
+	events := scanTrustEvents(attestationBytes, time.Time{})
+
+	used := make(map[string]bool)
+	exclude := func(cid, reason string) {
+		used[cid] = true
+		res.Exclusions = append(res.Exclusions, Exclusion{CID: cid, Reason: reason})
+	}
+	visited := map[string]bool{rootCID: true}
+	for {
+		trust := indexTrust(policy)
+		keyState := collectKeyEvents(events, policy, trust)
+		trust = keyState.trust(trust)
+
+		type candidate struct {
+			atts []string
+			keys []string
+		}
+		candidates := make(map[string]*candidate)
+		for _, s := range supersessions {
+			if s.prior != res.EffectiveCID {
+				continue
+			}
+			signers, _ := keyState.activeSigners(s.catf, s.cid, s.signers)
+			var keys []string
+			for _, k := range signers {
+				if holdsAny(trust.rolesFor(k, s.catf), policy.PolicySupersedesAllowedBy) {
+					keys = append(keys, k)
+				}
+			}
+			if len(keys) == 0 {
+				exclude(s.cid, ReasonPolicySupersessionNotAuthorized)
+				continue
+			}
+			c := candidates[s.successor]
+			if c == nil {
+				c = &candidate{}
+				candidates[s.successor] = c
+			}
+			c.atts = append(c.atts, s.cid)
+			c.keys = appendUniqueSorted(c.keys, keys...)
+		}
+
+		var authorized []string
+		for successor, c := range candidates {
+			if len(c.keys) >= policy.PolicySupersedesQuorum {
+				authorized = append(authorized, successor)
+				continue
+			}
+			for _, cid := range c.atts {
+				exclude(cid, ReasonPolicyQuorumNotMet)
+			}
+		}
+		sort.Strings(authorized)
+
+		stop := func(state State, reason string) {
+			res.State = state
+			res.Reason = reason
+			res.Candidates = authorized
+			for _, successor := range authorized {
+				for _, cid := range candidates[successor].atts {
+					exclude(cid, reason)
+				}
+			}
+		}
+		if len(authorized) == 0 {
+			break
+		}
+		if len(authorized) > 1 {
+			stop(StateForked, ReasonPolicySupersessionForked)
+			break
+		}
+		successor := authorized[0]
+		if visited[successor] {
+			stop(StateUnresolved, ReasonPolicySupersessionCycle)
+			break
+		}
+		b, err := fetch(successor)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			stop(StateUnresolved, ReasonSuccessorPolicyUnavailable)
+			break
+		}
+		next, err := tpdl.Parse(b)
+		if err == nil {
+			_, err = tpdl.CanonicalizeTPDL(b)
+		}
+		if err != nil {
+			stop(StateUnresolved, ReasonSuccessorPolicyInvalid)
+			break
+		}
+
+		c := candidates[successor]
+		for _, cid := range c.atts {
+			used[cid] = true
+		}
+		res.Chain = append(res.Chain, PolicySupersession{PriorCID: res.EffectiveCID, SuccessorCID: successor, AttestationCIDs: c.atts, SignerKeys: c.keys})
+		visited[successor] = true
+		res.EffectiveCID, res.Policy, res.PolicyBytes = successor, next, b
+		policy = next
+	}
+
+	for _, s := range supersessions {
+		if !used[s.cid] {
+			exclude(s.cid, ReasonPolicyNotInChain)
+		}
+	}
+	sort.SliceStable(res.Exclusions, func(i, j int) bool { return res.Exclusions[i].CID < res.Exclusions[j].CID })
+	return res, nil
+}
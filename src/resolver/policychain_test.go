@@ -0,0 +1,241 @@
+package resolver
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sort"
+	"strings"
+	"testing"
+
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/tpdl"
+)
+
+// governedPolicy renders a canonical xdao-tpdl-2 policy whose governors may
+// supersede it with the given quorum; name makes otherwise identical policies
+// distinct.
+func governedPolicy(name string, governors []string, quorum int) []byte {
+	var sb strings.Builder
+	sb.WriteString("-----BEGIN XDAO TRUST POLICY-----\nMETA\nName: " + name + "\nSpec: xdao-tpdl-2\nVersion: 1\n\nTRUST\n")
+	governors = append([]string(nil), governors...)
+	sort.Strings(governors)
+	for _, k := range governors {
+		sb.WriteString("Key: " + k + "\nRole: governor\n\n")
+	}
+	sb.WriteString("RULES\nRequire:\n  Type: authorship\n  Role: governor\n  Quorum: 1\n")
+	if quorum > 0 {
+		sb.WriteString("\nPolicy-Supersedes:\n  Allowed-By: governor\n  Quorum: " + itoa(quorum) + "\n")
+	}
+	sb.WriteString("-----END XDAO TRUST POLICY-----\n")
+	return []byte(sb.String())
+}
+
+func supersedePolicy(t *testing.T, prior, successor []byte, issuer string, priv ed25519.PrivateKey) []byte {
+	t.Helper()
+	priorCID := cidutil.CIDv1RawSHA256(prior)
+	return mustAttestation(t, priorCID, "Policy update", map[string]string{
+		"Prior-Policy":     priorCID,
+		"Successor-Policy": cidutil.CIDv1RawSHA256(successor),
+		"Type":             "policy-supersedes",
+	}, issuer, priv)
+}
+
+func TestResolveEffectivePolicy_Chain(t *testing.T) {
+	aPub, aPriv := mustKeypair(t, 0x71)
+	bPub, bPriv := mustKeypair(t, 0x72)
+	cPub, cPriv := mustKeypair(t, 0x73)
+	a, b, c := issuerKey(aPub), issuerKey(bPub), issuerKey(cPub)
+
+	root := governedPolicy("root", []string{a, b}, 2)
+	second := governedPolicy("second", []string{c}, 1)
+	third := governedPolicy("third", []string{c}, 1)
+
+	byA := supersedePolicy(t, root, second, a, aPriv)
+	byB := supersedePolicy(t, root, second, b, bPriv)
+	byC := supersedePolicy(t, second, third, c, cPriv)
+	// a governs root only, so it cannot supersede second.
+	stale := supersedePolicy(t, second, root, a, aPriv)
+
+	res, err := ResolveEffectivePolicy(cidutil.CIDv1RawSHA256(root), [][]byte{root, second, third}, [][]byte{byC, stale, byB, byA})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if res.State != StateResolved || res.EffectiveCID != cidutil.CIDv1RawSHA256(third) || string(res.PolicyBytes) != string(third) {
+		t.Fatalf("unexpected resolution %+v", res)
+	}
+	if len(res.Chain) != 2 {
+		t.Fatalf("expected 2 supersessions, got %+v", res.Chain)
+	}
+	first := res.Chain[0]
+	if first.PriorCID != res.RootCID || len(first.AttestationCIDs) != 2 || len(first.SignerKeys) != 2 {
+		t.Fatalf("unexpected first supersession %+v", first)
+	}
+	if res.Chain[1].SuccessorCID != res.EffectiveCID || res.Chain[1].SignerKeys[0] != c {
+		t.Fatalf("unexpected second supersession %+v", res.Chain[1])
+	}
+	if len(res.Exclusions) != 1 {
+		t.Fatalf("expected one exclusion, got %+v", res.Exclusions)
+	}
+	assertPolicyExclusion(t, res, mustCID(t, stale), ReasonPolicySupersessionNotAuthorized)
+
+	// Supersession is deterministic regardless of input order.
+	again, err := ResolveEffectivePolicy(res.RootCID, [][]byte{third, second, root}, [][]byte{byA, byB, stale, byC})
+	if err != nil || again.EffectiveCID != res.EffectiveCID {
+		t.Fatalf("order-dependent resolution: %+v, %v", again, err)
+	}
+}
+
+func TestResolveEffectivePolicy_QuorumDenyAndImmutable(t *testing.T) {
+	aPub, aPriv := mustKeypair(t, 0x74)
+	bPub, bPriv := mustKeypair(t, 0x75)
+	a, b := issuerKey(aPub), issuerKey(bPub)
+
+	root := governedPolicy("root", []string{a, b}, 2)
+	next := governedPolicy("next", []string{a}, 1)
+	byA := supersedePolicy(t, root, next, a, aPriv)
+	policies := [][]byte{root, next}
+
+	res, err := ResolveEffectivePolicy(cidutil.CIDv1RawSHA256(root), policies, [][]byte{byA})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if res.State != StateResolved || res.EffectiveCID != res.RootCID || len(res.Chain) != 0 {
+		t.Fatalf("expected root to remain effective, got %+v", res)
+	}
+	assertPolicyExclusion(t, res, mustCID(t, byA), ReasonPolicyQuorumNotMet)
+
+	// A denied governor does not count towards quorum.
+	denied := []byte(strings.Replace(string(root), "\nRULES\n", "\nDeny: "+b+"\n\nRULES\n", 1))
+	byB := supersedePolicy(t, denied, next, b, bPriv)
+	byA = supersedePolicy(t, denied, next, a, aPriv)
+	res, err = ResolveEffectivePolicy(cidutil.CIDv1RawSHA256(denied), [][]byte{denied, next}, [][]byte{byA, byB})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if len(res.Chain) != 0 {
+		t.Fatalf("denied key authorized supersession: %+v", res.Chain)
+	}
+	assertPolicyExclusion(t, res, mustCID(t, byB), ReasonPolicySupersessionNotAuthorized)
+
+	// Without a Policy-Supersedes block a policy cannot be superseded.
+	immutable := governedPolicy("immutable", []string{a}, 0)
+	byA = supersedePolicy(t, immutable, next, a, aPriv)
+	res, err = ResolveEffectivePolicy(cidutil.CIDv1RawSHA256(immutable), [][]byte{immutable, next}, [][]byte{byA})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if res.State != StateResolved || len(res.Chain) != 0 {
+		t.Fatalf("immutable policy superseded: %+v", res)
+	}
+	assertPolicyExclusion(t, res, mustCID(t, byA), ReasonPolicySupersessionNotAuthorized)
+}
+
+func TestResolveEffectivePolicy_ForkedAndUnavailable(t *testing.T) {
+	aPub, aPriv := mustKeypair(t, 0x76)
+	bPub, bPriv := mustKeypair(t, 0x77)
+	a, b := issuerKey(aPub), issuerKey(bPub)
+
+	root := governedPolicy("root", []string{a, b}, 1)
+	left := governedPolicy("left", []string{a}, 1)
+	right := governedPolicy("right", []string{b}, 1)
+	toLeft := supersedePolicy(t, root, left, a, aPriv)
+	toRight := supersedePolicy(t, root, right, b, bPriv)
+	rootCID := cidutil.CIDv1RawSHA256(root)
+
+	res, err := ResolveEffectivePolicy(rootCID, [][]byte{root, left, right}, [][]byte{toLeft, toRight})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if res.State != StateForked || res.EffectiveCID != rootCID || len(res.Candidates) != 2 {
+		t.Fatalf("expected fork at root, got %+v", res)
+	}
+	assertPolicyExclusion(t, res, mustCID(t, toLeft), ReasonPolicySupersessionForked)
+
+	res, err = ResolveEffectivePolicy(rootCID, [][]byte{root}, [][]byte{toLeft})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicy: %v", err)
+	}
+	if res.State != StateUnresolved || res.Reason != ReasonSuccessorPolicyUnavailable || res.Candidates[0] != cidutil.CIDv1RawSHA256(left) {
+		t.Fatalf("expected unavailable successor, got %+v", res)
+	}
+
+	if _, err := ResolveEffectivePolicy(rootCID, [][]byte{left}, nil); err == nil {
+		t.Fatalf("expected error for missing root policy")
+	}
+
+	// A non-canonical root reports why it cannot be used, not that it is missing.
+	loose := []byte(strings.TrimSuffix(string(root), "\n"))
+	if _, err := ResolveEffectivePolicy(cidutil.CIDv1RawSHA256(loose), [][]byte{loose, left}, nil); tpdl.RuleID(err) != "TPDL-CANON-001" {
+		t.Fatalf("expected TPDL-CANON-001 for non-canonical root, got %v", err)
+	}
+}
+
+func TestResolveEffectivePolicyWithCAS_FetchesSuccessors(t *testing.T) {
+	aPub, aPriv := mustKeypair(t, 0x78)
+	a := issuerKey(aPub)
+
+	root := governedPolicy("root", []string{a}, 1)
+	next := governedPolicy("next", []string{a}, 1)
+	att := supersedePolicy(t, root, next, a, aPriv)
+
+	cas := newMemCAS()
+	rootID, err := cas.Put(root)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	req := EffectivePolicyRequestCAS{RootPolicy: BlobRef{CID: rootID}, Attestations: []BlobRef{{Bytes: att}}, CAS: cas}
+
+	res, err := ResolveEffectivePolicyWithCASContext(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicyWithCAS: %v", err)
+	}
+	if res.State != StateUnresolved || res.Reason != ReasonSuccessorPolicyUnavailable {
+		t.Fatalf("expected unavailable successor, got %+v", res)
+	}
+
+	if _, err := cas.Put(next); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	res, err = ResolveEffectivePolicyWithCAS(req)
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicyWithCAS: %v", err)
+	}
+	if res.State != StateResolved || res.EffectiveCID != cidutil.CIDv1RawSHA256(next) || res.RootCID != rootID.String() {
+		t.Fatalf("unexpected resolution %+v", res)
+	}
+
+	// Only canonical policies have a policy CID, so a non-canonical successor
+	// is invalid and a non-canonical root is rejected.
+	unsorted := func(p []byte) []byte {
+		return []byte(strings.Replace(string(p), "Spec: xdao-tpdl-2\nVersion: 1\n", "Version: 1\nSpec: xdao-tpdl-2\n", 1))
+	}
+	messy := unsorted(governedPolicy("messy", []string{a}, 1))
+	if _, err := cas.Put(messy); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	toMessy := supersedePolicy(t, root, messy, a, aPriv)
+	res, err = ResolveEffectivePolicyWithCAS(EffectivePolicyRequestCAS{RootPolicy: BlobRef{CID: rootID}, Attestations: []BlobRef{{Bytes: toMessy}}, CAS: cas})
+	if err != nil {
+		t.Fatalf("ResolveEffectivePolicyWithCAS: %v", err)
+	}
+	if res.State != StateUnresolved || res.Reason != ReasonSuccessorPolicyInvalid {
+		t.Fatalf("expected invalid successor, got %+v", res)
+	}
+	_, err = ResolveEffectivePolicyWithCAS(EffectivePolicyRequestCAS{RootPolicy: BlobRef{Bytes: unsorted(root)}, CAS: cas})
+	if tpdl.RuleID(err) != "TPDL-CANON-001" {
+		t.Fatalf("expected TPDL-CANON-001 for non-canonical root, got %v", err)
+	}
+}
+
+func assertPolicyExclusion(t *testing.T, res *PolicyResolution, cid, reason string) {
+	t.Helper()
+	for _, e := range res.Exclusions {
+		if e.CID == cid {
+			if e.Reason != reason {
+				t.Fatalf("exclusion %s: reason %q, want %q", cid, e.Reason, reason)
+			}
+			return
+		}
+	}
+	t.Fatalf("no exclusion for %s in %+v", cid, res.Exclusions)
+}
@@ -0,0 +1,214 @@
+package resolver
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/tpdl"
+)
+
+// PolicyDiff compares how the same attestations resolve under two policies.
+type PolicyDiff struct {
+	// Subjects has one entry per subject CID of a semantic claim (any claim
+	// type except name-binding and trust events), sorted by SubjectCID.
+	Subjects []SubjectDiff
+
+	// Names has one entry per Name/Version of a name-binding attestation,
+	// sorted by Name, then Version.
+	Names []NameDiff
+}
+
+// SubjectDiff is the resolution of one subject under the old and new policy.
+type SubjectDiff struct {
+	SubjectCID string
+
+	OldState State
+	NewState State
+
+	// VerdictChanges lists the policy verdicts that differ.
+	VerdictChanges []PolicyVerdictChange
+}
+
+// Changed reports whether the subject resolves differently.
+func (d SubjectDiff) Changed() bool {
+	return d.OldState != d.NewState || len(d.VerdictChanges) > 0
+}
+
+// NameDiff is the exact-version resolution of one name under the old and new
+// policy.
+type NameDiff struct {
+	Name    string
+	Version string
+
+	OldState    State
+	NewState    State
+	OldPointsTo string
+	NewPointsTo string
+
+	VerdictChanges []PolicyVerdictChange
+}
+
+// Changed reports whether the name resolves differently.
+func (d NameDiff) Changed() bool {
+	return d.OldState != d.NewState || d.OldPointsTo != d.NewPointsTo || len(d.VerdictChanges) > 0
+}
+
+// PolicyVerdictChange pairs the verdicts for one Require rule, matched by
+// Type and Role (see PolicyVerdict.Role). Old is nil for a rule only the new
+// policy has, and New is nil for a rule it dropped.
+type PolicyVerdictChange struct {
+	Type string
+	Role string
+
+	Old *PolicyVerdict
+	New *PolicyVerdict
+}
+
+// Changed reports whether any subject or name resolves differently.
+func (d *PolicyDiff) Changed() bool {
+	for _, s := range d.Subjects {
+		if s.Changed() {
+			return true
+		}
+	}
+	for _, n := range d.Names {
+		if n.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffPolicies resolves every subject and name in attestationBytes under
+// oldPolicy and newPolicy and reports the differences. The inputs are
+// verified once per policy, however many subjects and names they cover.
+//
+// opts.Mode applies to policy parsing only; resolutions are always
+// permissive, so that a subject that does not resolve is reported rather
+// than failing the diff. opts.AsOf applies to both resolutions.
+func DiffPolicies(attestationBytes [][]byte, oldPolicy, newPolicy []byte, opts Options) (*PolicyDiff, error) {
+	opts = opts.withDefaults()
+	oldP, err := tpdl.ParseWithCompliance(oldPolicy, opts.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("resolver: old policy: %w", err)
+	}
+	newP, err := tpdl.ParseWithCompliance(newPolicy, opts.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("resolver: new policy: %w", err)
+	}
+
+	subjects := make(map[string]bool)
+	type nameVersion struct{ name, version string }
+	names := make(map[nameVersion]bool)
+	for _, b := range attestationBytes {
+		a, err := catf.Parse(b)
+		if err != nil {
+			continue
+		}
+		switch typ := a.ClaimType(); {
+		case typ == "name-binding":
+			claims := a.Sections["CLAIMS"].Pairs
+			if claims["Name"] != "" {
+				names[nameVersion{claims["Name"], claims["Version"]}] = true
+			}
+		case !isTrustEventType(typ) && a.SubjectCID() != "":
+			subjects[a.SubjectCID()] = true
+		}
+	}
+
+	diff := &PolicyDiff{}
+	subjectList := make([]string, 0, len(subjects))
+	for s := range subjects {
+		subjectList = append(subjectList, s)
+	}
+	sort.Strings(subjectList)
+	asOf := normalizeAsOf(opts.AsOf)
+	if len(subjectList) > 0 {
+		oldAtts, oldExcl, oldVerdicts := verifyInputs(attestationBytes, oldP, asOf, subjectClaimGates(oldP), nil)
+		newAtts, newExcl, newVerdicts := verifyInputs(attestationBytes, newP, asOf, subjectClaimGates(newP), nil)
+		for _, s := range subjectList {
+			before := resolveSubjectVerified(oldAtts, oldExcl, oldVerdicts, oldP, s, asOf, nil)
+			after := resolveSubjectVerified(newAtts, newExcl, newVerdicts, newP, s, asOf, nil)
+			diff.Subjects = append(diff.Subjects, SubjectDiff{
+				SubjectCID:     s,
+				OldState:       before.State,
+				NewState:       after.State,
+				VerdictChanges: diffPolicyVerdicts(before.PolicyVerdicts, after.PolicyVerdicts),
+			})
+		}
+	}
+
+	if len(names) == 0 {
+		return diff, nil
+	}
+	nameList := make([]nameVersion, 0, len(names))
+	for nv := range names {
+		nameList = append(nameList, nv)
+	}
+	sort.Slice(nameList, func(i, j int) bool {
+		if nameList[i].name != nameList[j].name {
+			return nameList[i].name < nameList[j].name
+		}
+		return nameList[i].version < nameList[j].version
+	})
+	oldAtts, oldExcl, oldVerdicts := verifyInputs(attestationBytes, oldP, asOf, nil, nil)
+	newAtts, newExcl, newVerdicts := verifyInputs(attestationBytes, newP, asOf, nil, nil)
+	for _, nv := range nameList {
+		before := resolveNameVerified(oldAtts, oldExcl, oldVerdicts, oldP, nv.name, ExactVersion(nv.version))
+		after := resolveNameVerified(newAtts, newExcl, newVerdicts, newP, nv.name, ExactVersion(nv.version))
+		diff.Names = append(diff.Names, NameDiff{
+			Name:           nv.name,
+			Version:        nv.version,
+			OldState:       before.State,
+			NewState:       after.State,
+			OldPointsTo:    before.PointsTo,
+			NewPointsTo:    after.PointsTo,
+			VerdictChanges: diffPolicyVerdicts(before.PolicyVerdicts, after.PolicyVerdicts),
+		})
+	}
+	return diff, nil
+}
+
+// diffPolicyVerdicts pairs verdicts by Type and Role, in order of occurrence,
+// and returns the pairs that differ, sorted by Type and Role.
+func diffPolicyVerdicts(before, after []PolicyVerdict) []PolicyVerdictChange {
+	type ruleKey struct{ typ, role string }
+	pending := make(map[ruleKey][]PolicyVerdict)
+	for _, v := range after {
+		k := ruleKey{v.Type, v.Role}
+		pending[k] = append(pending[k], v)
+	}
+
+	var out []PolicyVerdictChange
+	for i := range before {
+		old := before[i]
+		k := ruleKey{old.Type, old.Role}
+		c := PolicyVerdictChange{Type: old.Type, Role: old.Role, Old: &old}
+		if list := pending[k]; len(list) > 0 {
+			nv := list[0]
+			pending[k] = list[1:]
+			if reflect.DeepEqual(old, nv) {
+				continue
+			}
+			c.New = &nv
+		}
+		out = append(out, c)
+	}
+	for _, v := range after {
+		k := ruleKey{v.Type, v.Role}
+		if list := pending[k]; len(list) > 0 {
+			nv := list[0]
+			pending[k] = list[1:]
+			out = append(out, PolicyVerdictChange{Type: nv.Type, Role: nv.Role, New: &nv})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Type != out[j].Type {
+			return out[i].Type < out[j].Type
+		}
+		return out[i].Role < out[j].Role
+	})
+	return out
+}
@@ -0,0 +1,86 @@
+package resolver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPolicies_ReportsStateAndVerdictChanges(t *testing.T) {
+	buyerPub, buyerPriv := mustKeypair(t, 0x81)
+	sellerPub, sellerPriv := mustKeypair(t, 0x82)
+	registrarPub, registrarPriv := mustKeypair(t, 0x83)
+	buyer, seller, registrar := issuerKey(buyerPub), issuerKey(sellerPub), issuerKey(registrarPub)
+
+	deed := approvalOn(t, "bafy-diff-deed", "2026-01-01", buyer, buyerPriv)
+	note := mustAttestation(t, "bafy-diff-note", "Note", map[string]string{"Role": "seller", "Type": "authorship"}, seller, sellerPriv)
+	binding := mustAttestation(t, "bafy-name-record", "Name record", map[string]string{
+		"Name":      "deeds.example",
+		"Points-To": "bafy-diff-deed",
+		"Type":      "name-binding",
+		"Version":   "1",
+	}, registrar, registrarPriv)
+	atts := [][]byte{deed, note, binding}
+
+	oldPolicy := trustPolicy([]trustEntry{{buyer, "buyer"}, {seller, "seller"}, {registrar, "registrar"}}, []requireRule{{"approval", "buyer", 1}})
+	newPolicy := trustPolicy([]trustEntry{{buyer, "buyer"}, {seller, "seller"}}, []requireRule{{"approval", "buyer", 2}})
+
+	diff, err := DiffPolicies(atts, []byte(oldPolicy), []byte(newPolicy), Options{})
+	if err != nil {
+		t.Fatalf("DiffPolicies: %v", err)
+	}
+	if !diff.Changed() {
+		t.Fatalf("expected changes")
+	}
+	if len(diff.Subjects) != 2 {
+		t.Fatalf("expected 2 subjects, got %+v", diff.Subjects)
+	}
+
+	d := diff.Subjects[0]
+	if d.SubjectCID != "bafy-diff-deed" || d.OldState != StateResolved || d.NewState != StateUnresolved {
+		t.Fatalf("unexpected deed diff %+v", d)
+	}
+	if len(d.VerdictChanges) != 1 {
+		t.Fatalf("expected one verdict change, got %+v", d.VerdictChanges)
+	}
+	c := d.VerdictChanges[0]
+	if c.Type != "approval" || c.Role != "buyer" || !c.Old.Satisfied || c.New.Satisfied || c.New.Quorum != 2 {
+		t.Fatalf("unexpected verdict change old=%+v new=%+v", c.Old, c.New)
+	}
+	// The note never satisfies the approval rule, but its verdict records
+	// the new quorum.
+	if n := diff.Subjects[1]; n.SubjectCID != "bafy-diff-note" || n.OldState != n.NewState || len(n.VerdictChanges) != 1 {
+		t.Fatalf("unexpected note diff %+v", n)
+	}
+
+	// Sharing verification across subjects must not change any resolution.
+	for _, d := range diff.Subjects {
+		before, err := ResolveWithOptions(atts, []byte(oldPolicy), d.SubjectCID, Options{})
+		if err != nil {
+			t.Fatalf("ResolveWithOptions(old, %s): %v", d.SubjectCID, err)
+		}
+		after, err := ResolveWithOptions(atts, []byte(newPolicy), d.SubjectCID, Options{})
+		if err != nil {
+			t.Fatalf("ResolveWithOptions(new, %s): %v", d.SubjectCID, err)
+		}
+		if before.State != d.OldState || after.State != d.NewState || !reflect.DeepEqual(d.VerdictChanges, diffPolicyVerdicts(before.PolicyVerdicts, after.PolicyVerdicts)) {
+			t.Fatalf("diff of %s disagrees with ResolveWithOptions: %+v", d.SubjectCID, d)
+		}
+	}
+
+	if len(diff.Names) != 1 {
+		t.Fatalf("expected 1 name, got %+v", diff.Names)
+	}
+	nd := diff.Names[0]
+	if nd.Name != "deeds.example" || nd.Version != "1" || nd.OldState != StateResolved || nd.NewState != StateUnresolved || nd.OldPointsTo != "bafy-diff-deed" || nd.NewPointsTo != "" {
+		t.Fatalf("unexpected name diff %+v", nd)
+	}
+
+	same, err := DiffPolicies(atts, []byte(oldPolicy), []byte(oldPolicy), Options{})
+	if err != nil || same.Changed() {
+		t.Fatalf("expected no changes for identical policies, got %+v, %v", same, err)
+	}
+
+	if _, err := DiffPolicies(atts, []byte("not a policy"), []byte(newPolicy), Options{}); err == nil {
+		t.Fatalf("expected error for invalid old policy")
+	}
+}
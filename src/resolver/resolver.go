@@ -8,6 +8,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"sort"
+	"strings"
+	"time"
 
 	"xdao.co/catf/catf"
 	"xdao.co/catf/compliance"
@@ -46,6 +48,15 @@ type Resolution struct {
 	// This allows consumers to distinguish missing/insufficient evidence from other failures
 	// without re-running the resolver.
 	PolicyVerdicts []PolicyVerdict
+
+	// AsOf is the instant attestation validity windows were evaluated at
+	// (UTC, whole seconds). Zero means time was not considered.
+	AsOf time.Time
+
+	// Trace records, in order, the decisions that led to State. It is only
+	// populated when requested (Options.Trace, ResolveRequestCAS.Trace) and
+	// is never rendered into CROF.
+	Trace []TraceStep
 }
 
 type Path struct {
@@ -87,6 +98,10 @@ type Verdict struct {
 	IssuerKey          string
 	ClaimType          string
 
+	// SignerKeys lists the co-signers, all of whose signatures verified.
+	// It is only populated for co-signed attestations (IssuerKey is then empty).
+	SignerKeys []string
+
 	Trusted    bool
 	TrustRoles []string
 	Revoked    bool
@@ -98,6 +113,10 @@ type Verdict struct {
 	Reasons []string
 
 	ExcludedReason string // retained for compatibility; also included in Reasons when set
+
+	// Delegations explains trust roles held through delegation attestations
+	// rather than directly through policy TRUST.
+	Delegations []DelegationChain
 }
 
 type attestation struct {
@@ -107,6 +126,13 @@ type attestation struct {
 	trustRoles map[string]bool
 	revoked    bool
 	revokedBy  []string
+
+	// signers lists the verified signer keys, trusted or not.
+	signers []string
+
+	// signerRoles maps each trusted, verified signer key to its policy roles.
+	// Quorum evaluation credits every entry, so co-signers count individually.
+	signerRoles map[string]map[string]bool
 }
 
 // inputHash computes a stable, non-CID handle for raw input bytes.
@@ -125,11 +151,49 @@ func Resolve(attestationBytes [][]byte, policyBytes []byte, subjectCID string) (
 	if err != nil {
 		return nil, err
 	}
-	return resolveWithPolicy(attestationBytes, policy, subjectCID)
+	return resolveWithPolicy(attestationBytes, policy, subjectCID, time.Time{}, nil)
+}
+
+// resolveWithPolicy resolves subjectCID. When asOf is non-zero, attestations
+// outside their Effective-Date/Expires window at asOf are excluded before trust
+// evaluation, so they neither satisfy rules nor revoke. Decisions are recorded
+// in tr, which may be nil.
+func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCID string, asOf time.Time, tr *tracer) (*Resolution, error) {
+	asOf = normalizeAsOf(asOf)
+	atts, exclusions, verdicts := verifyInputs(attestationBytes, policy, asOf, subjectClaimGates(policy), tr)
+	return resolveSubjectVerified(atts, exclusions, verdicts, policy, subjectCID, asOf, tr), nil
+}
+
+// claimGate withdraws trust from attestations of one claim type unless a
+// signer holds one of allowedBy, excluding them with reason.
+type claimGate struct {
+	allowedBy []string
+	reason    string
+}
+
+// subjectClaimGates returns the claim gates of subject resolution: the
+// policy's Supersedes Allowed-By roles, when set.
+func subjectClaimGates(policy *tpdl.Policy) map[string]claimGate {
+	if len(policy.SupersedesAllowedBy) == 0 {
+		return nil
+	}
+	return map[string]claimGate{
+		"supersedes": {allowedBy: policy.SupersedesAllowedBy, reason: "Supersedes not allowed by policy"},
+	}
 }
 
-func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCID string) (*Resolution, error) {
+// verifyInputs parses and verifies attestations, assigns trust under policy,
+// applies gates (keyed by claim type; nil for none) and applies revocations.
+// A non-zero asOf excludes attestations outside their validity window (see
+// validityReason). atts is sorted by CID and verdicts are in report order.
+// The result depends on the policy but not on the subject or name, so it can
+// be shared across lookups. Decisions are recorded in tr, which may be nil.
+func verifyInputs(attestationBytes [][]byte, policy *tpdl.Policy, asOf time.Time, gates map[string]claimGate, tr *tracer) ([]*attestation, []Exclusion, []Verdict) {
 	trustIndex := indexTrust(policy)
+	events := scanTrustEvents(attestationBytes, asOf)
+	keyState := collectKeyEvents(events, policy, trustIndex)
+	trustIndex = keyState.trust(trustIndex)
+	grants := collectDelegationGrants(events, policy, trustIndex, keyState)
 
 	var atts []*attestation
 	var exclusions []Exclusion
@@ -144,7 +208,7 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 			v.InputHash = inputHash(b)
 			v.Status = VerdictInvalid
 			v.ExcludedReason = "CATF parse/canonicalization failed"
-			v.Reasons = []string{v.ExcludedReason}
+			v.Reasons = []string{stableCATFReason(perr)}
 			verdicts = append(verdicts, v)
 			exclusions = append(exclusions, Exclusion{CID: v.CID, InputHash: v.InputHash, Reason: v.ExcludedReason})
 			continue
@@ -155,7 +219,7 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 			v.InputHash = inputHash(b)
 			v.Status = VerdictInvalid
 			v.ExcludedReason = "CATF parse/canonicalization failed"
-			v.Reasons = []string{v.ExcludedReason}
+			v.Reasons = []string{stableCATFReason(err)}
 			verdicts = append(verdicts, v)
 			exclusions = append(exclusions, Exclusion{CID: v.CID, InputHash: v.InputHash, Reason: v.ExcludedReason})
 			continue
@@ -172,7 +236,8 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
 			continue
 		}
-		if err := a.Verify(); err != nil {
+		signers, err := verifiedSigners(a)
+		if err != nil {
 			v.Status = VerdictInvalid
 			v.ExcludedReason = "Signature invalid"
 			v.Reasons = []string{v.ExcludedReason}
@@ -180,26 +245,52 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
 			continue
 		}
-		att := &attestation{catf: a, cid: cid}
-		if roles, ok := trustIndex[a.IssuerKey()]; ok {
+		if a.IsCoSigned() {
+			v.SignerKeys = signers
+		}
+		if reason := validityReason(a, asOf); reason != "" {
+			v.Status = VerdictExcluded
+			v.ExcludedReason = reason
+			v.Reasons = []string{v.ExcludedReason}
+			verdicts = append(verdicts, v)
+			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
+			continue
+		}
+		signers, keyReason := keyState.activeSigners(a, cid, signers)
+		reason := keyReason
+		if len(signers) > 0 {
+			reason = keyState.eventReason(a, cid)
+		}
+		if reason != "" {
+			v.Status = VerdictExcluded
+			v.ExcludedReason = reason
+			v.Reasons = []string{v.ExcludedReason}
+			verdicts = append(verdicts, v)
+			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
+			continue
+		}
+		signerRoles, chains := grants.signerRoles(trustIndex, signers, a)
+		att := &attestation{catf: a, cid: cid, signers: signers, signerRoles: signerRoles}
+		if len(att.signerRoles) > 0 {
 			att.trusted = true
-			att.trustRoles = roles
+			att.trustRoles = unionRoles(att.signerRoles)
 			v.Trusted = true
-			for r := range roles {
+			for r := range att.trustRoles {
 				v.TrustRoles = append(v.TrustRoles, r)
 			}
 			sort.Strings(v.TrustRoles)
 			v.Status = VerdictTrusted
-			v.Reasons = []string{"Issuer trusted by policy"}
+			v.Reasons = trustReasons(trustIndex, signers, a, chains)
+			v.Delegations = chains
 		} else {
 			v.Status = VerdictExcluded
-			v.ExcludedReason = "Issuer not trusted"
+			v.ExcludedReason = untrustedReason(trustIndex, signers, a)
 			v.Reasons = []string{v.ExcludedReason}
 			exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
 		}
-		if att.trusted && a.ClaimType() == "supersedes" && len(policy.SupersedesAllowedBy) > 0 {
+		if gate, ok := gates[a.ClaimType()]; ok && att.trusted {
 			allowed := false
-			for _, role := range policy.SupersedesAllowedBy {
+			for _, role := range gate.allowedBy {
 				if att.trustRoles[role] {
 					allowed = true
 					break
@@ -210,18 +301,24 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 				v.Trusted = false
 				v.TrustRoles = nil
 				v.Status = VerdictExcluded
-				v.ExcludedReason = "Supersedes not allowed by policy"
+				v.ExcludedReason = gate.reason
 				v.Reasons = []string{v.ExcludedReason}
 				exclusions = append(exclusions, Exclusion{CID: cid, Reason: v.ExcludedReason})
 			}
 		}
+		if keyReason != "" {
+			v.Reasons = append(v.Reasons, keyReason)
+		}
 		verdictIndex[cid] = len(verdicts)
 		verdicts = append(verdicts, v)
 		atts = append(atts, att)
 	}
 
 	sort.Slice(atts, func(i, j int) bool { return atts[i].cid < atts[j].cid })
-	applyRevocations(atts)
+	if tr != nil {
+		traceInputs(tr, verdicts, asOf)
+	}
+	applyRevocations(atts, policy, trustIndex, tr)
 	for _, a := range atts {
 		if !a.revoked {
 			continue
@@ -240,7 +337,12 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 		verdicts[i].Reasons = appendUniqueSorted(verdicts[i].Reasons)
 	}
 	sort.SliceStable(verdicts, func(i, j int) bool { return verdictLessV2(verdicts[i], verdicts[j]) })
+	return atts, exclusions, verdicts
+}
 
+// resolveSubjectVerified resolves subjectCID from the output of
+// verifyInputs. It does not modify atts, exclusions or verdicts.
+func resolveSubjectVerified(atts []*attestation, exclusions []Exclusion, verdicts []Verdict, policy *tpdl.Policy, subjectCID string, asOf time.Time, tr *tracer) *Resolution {
 	// Only consider attestations about this subject.
 	var subjectAtts []*attestation
 	for _, a := range atts {
@@ -249,10 +351,17 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 		}
 	}
 
-	res := &Resolution{SubjectCID: subjectCID, Confidence: ConfidenceUndefined, Exclusions: exclusions, Verdicts: verdicts}
+	res := &Resolution{SubjectCID: subjectCID, Confidence: ConfidenceUndefined, Exclusions: exclusions, Verdicts: verdicts, AsOf: asOf}
+	if tr != nil {
+		defer func() {
+			tr.add(TracePhaseState, string(res.State)+" with confidence "+string(res.Confidence))
+			res.Trace = tr.result()
+		}()
+	}
 	if len(subjectAtts) == 0 {
+		tr.add(TracePhaseSubject, "no valid attestations about the subject")
 		res.State = StateUnresolved
-		return res, nil
+		return res
 	}
 
 	var activeTrusted []*attestation
@@ -262,14 +371,21 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 		}
 	}
 
-	// Revocations affect trust but should not count as active semantic claims.
+	// Revocations, delegations and key events affect trust but should not count as active semantic claims.
 	var activeTrustedClaims []*attestation
 	for _, a := range activeTrusted {
-		if a.catf.ClaimType() == "revocation" {
+		if isTrustEventType(a.catf.ClaimType()) {
 			continue
 		}
 		activeTrustedClaims = append(activeTrustedClaims, a)
 	}
+	if tr != nil {
+		var claimCIDs []string
+		for _, a := range activeTrustedClaims {
+			claimCIDs = append(claimCIDs, a.cid)
+		}
+		tr.add(TracePhaseSubject, itoa(len(subjectAtts))+" attestation(s) about the subject, "+itoa(len(activeTrustedClaims))+" active trusted claim(s)", claimCIDs...)
+	}
 
 	if len(activeTrustedClaims) == 0 {
 		anyRevoked := false
@@ -284,29 +400,32 @@ func resolveWithPolicy(attestationBytes [][]byte, policy *tpdl.Policy, subjectCI
 		} else {
 			res.State = StateUnresolved
 		}
-		return res, nil
+		return res
 	}
 
 	policyVerdicts, ok := evaluatePolicyRules(policy, activeTrustedClaims, "")
 	res.PolicyVerdicts = policyVerdicts
+	if tr != nil {
+		tracePolicyVerdicts(tr, policyVerdicts)
+	}
 	if !ok {
 		res.State = StateUnresolved
-		return res, nil
+		return res
 	}
 
-	paths, forks := buildPaths(policy, activeTrustedClaims)
+	paths, forks := buildPaths(policy, activeTrustedClaims, tr)
 	res.Paths = paths
 	res.Forks = forks
 
 	if len(forks) > 0 {
 		res.State = StateForked
 		res.Confidence = ConfidenceMedium
-		return res, nil
+		return res
 	}
 
 	res.State = StateResolved
 	res.Confidence = ConfidenceHigh
-	return res, nil
+	return res
 }
 
 func stableCATFReason(err error) string {
@@ -322,28 +441,17 @@ func stableCATFReason(err error) string {
 	return err.Error()
 }
 
-func indexTrust(policy *tpdl.Policy) map[string]map[string]bool {
-	idx := make(map[string]map[string]bool)
-	for _, t := range policy.Trust {
-		m := idx[t.Key]
-		if m == nil {
-			m = make(map[string]bool)
-			idx[t.Key] = m
-		}
-		m[t.Role] = true
-	}
-	return idx
-}
-
-func applyRevocations(atts []*attestation) {
+// applyRevocations marks the targets of revocations. Trusted revocations
+// revoke any target except key events and policy supersessions, which cannot
+// be revoked. Delegation targets are instead withdrawn as described by
+// withdrawsDelegation, matching the grants computed before trust evaluation.
+// Each revocation's outcome is recorded in tr, which may be nil.
+func applyRevocations(atts []*attestation, policy *tpdl.Policy, trustIndex *policyTrust, tr *tracer) {
 	byCID := make(map[string]*attestation)
 	for _, a := range atts {
 		byCID[a.cid] = a
 	}
 	for _, a := range atts {
-		if !a.trusted {
-			continue
-		}
 		if a.catf.ClaimType() != "revocation" {
 			continue
 		}
@@ -351,10 +459,31 @@ func applyRevocations(atts []*attestation) {
 		if target == "" {
 			continue
 		}
-		if t, ok := byCID[target]; ok {
-			t.revoked = true
-			t.revokedBy = appendUniqueSorted(t.revokedBy, a.cid)
+		t, ok := byCID[target]
+		if !ok {
+			tr.add(TracePhaseRevocation, "ignored: target is not a valid input", a.cid, target)
+			continue
 		}
+		switch typ := t.catf.ClaimType(); typ {
+		case "key-rotation", "key-revocation", "policy-supersedes":
+			// Key events and policy supersessions are permanent; rotate or
+			// revoke the key, or supersede the policy, again instead.
+			tr.add(TracePhaseRevocation, "ignored: "+typ+" attestations cannot be revoked", a.cid, target)
+			continue
+		case "delegation":
+			if !withdrawsDelegation(a.catf, a.signers, parseDelegation(t.catf, t.cid), policy, trustIndex) {
+				tr.add(TracePhaseRevocation, "ignored: signers may not withdraw the delegation", a.cid, target)
+				continue
+			}
+		default:
+			if !a.trusted {
+				tr.add(TracePhaseRevocation, "ignored: revocation is not trusted", a.cid, target)
+				continue
+			}
+		}
+		tr.add(TracePhaseRevocation, "revoked "+target, a.cid, target)
+		t.revoked = true
+		t.revokedBy = appendUniqueSorted(t.revokedBy, a.cid)
 	}
 }
 
@@ -364,15 +493,16 @@ func rulesSatisfied(policy *tpdl.Policy, activeTrusted []*attestation) bool {
 	}
 	typeRoleToKeys := make(map[string]map[string]bool)
 	for _, a := range activeTrusted {
-		issuer := a.catf.IssuerKey()
-		for role := range a.trustRoles {
-			key := a.catf.ClaimType() + "|" + role
-			m := typeRoleToKeys[key]
-			if m == nil {
-				m = make(map[string]bool)
-				typeRoleToKeys[key] = m
+		for issuer, roles := range a.signerRoles {
+			for role := range roles {
+				key := a.catf.ClaimType() + "|" + role
+				m := typeRoleToKeys[key]
+				if m == nil {
+					m = make(map[string]bool)
+					typeRoleToKeys[key] = m
+				}
+				m[issuer] = true
 			}
-			m[issuer] = true
 		}
 	}
 	for _, r := range policy.Rules {
@@ -380,8 +510,7 @@ func rulesSatisfied(policy *tpdl.Policy, activeTrusted []*attestation) bool {
 		if q < 1 {
 			q = 1
 		}
-		key := r.Type + "|" + r.Role
-		m := typeRoleToKeys[key]
+		m := ruleKeys(typeRoleToKeys, r)
 		count := 0
 		for range m {
 			count++
@@ -413,15 +542,16 @@ func rulesSatisfiedForType(policy *tpdl.Policy, activeTrusted []*attestation, ty
 		if a.catf.ClaimType() != typ {
 			continue
 		}
-		issuer := a.catf.IssuerKey()
-		for role := range a.trustRoles {
-			key := typ + "|" + role
-			m := typeRoleToKeys[key]
-			if m == nil {
-				m = make(map[string]bool)
-				typeRoleToKeys[key] = m
+		for issuer, roles := range a.signerRoles {
+			for role := range roles {
+				key := typ + "|" + role
+				m := typeRoleToKeys[key]
+				if m == nil {
+					m = make(map[string]bool)
+					typeRoleToKeys[key] = m
+				}
+				m[issuer] = true
 			}
-			m[issuer] = true
 		}
 	}
 
@@ -433,8 +563,7 @@ func rulesSatisfiedForType(policy *tpdl.Policy, activeTrusted []*attestation, ty
 		if q < 1 {
 			q = 1
 		}
-		key := r.Type + "|" + r.Role
-		m := typeRoleToKeys[key]
+		m := ruleKeys(typeRoleToKeys, r)
 		count := 0
 		for range m {
 			count++
@@ -446,7 +575,9 @@ func rulesSatisfiedForType(policy *tpdl.Policy, activeTrusted []*attestation, ty
 	return true
 }
 
-func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation) ([]Path, []Fork) {
+// buildPaths groups the active trusted claims into paths, and into a fork
+// when they compete. The mode it chose is recorded in tr, which may be nil.
+func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation, tr *tracer) ([]Path, []Fork) {
 	// Model supersession using CLAIMS: Supersedes: <CID>
 	supersedes := make(map[string]string)
 	for _, a := range activeTrusted {
@@ -497,6 +628,7 @@ func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation) ([]Path, []Fo
 			}
 			forks = append(forks, fork)
 		}
+		tr.add(TracePhasePaths, "supersession: "+itoa(len(supersedes))+" supersedes claim(s) leave "+itoa(len(heads))+" path head(s)", heads...)
 		return paths, forks
 	}
 
@@ -515,12 +647,12 @@ func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation) ([]Path, []Fo
 			if q != 1 {
 				continue
 			}
-			k := roleKey{typ: r.Type, role: r.Role}
+			k := roleKey{typ: r.Type, role: r.RoleLabel()}
 			for _, a := range activeTrusted {
 				if a.catf.ClaimType() != r.Type {
 					continue
 				}
-				if !a.trustRoles[r.Role] {
+				if !holdsAny(a.trustRoles, r.Roles()) {
 					continue
 				}
 				ambiguous[k] = append(ambiguous[k], a.cid)
@@ -567,6 +699,13 @@ func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation) ([]Path, []Fo
 			for _, p := range paths {
 				fork.ConflictingPath = append(fork.ConflictingPath, p.ID)
 			}
+			if tr != nil {
+				labels := make([]string, len(keys))
+				for i, key := range keys {
+					labels[i] = key.typ + "/" + key.role
+				}
+				tr.add(TracePhasePaths, "quorum ambiguity: "+labels[0]+" is the first of "+itoa(len(keys))+" ambiguous Quorum 1 rule(s) ("+strings.Join(labels, ", ")+"); forking across its "+itoa(len(cands))+" candidates", cands...)
+			}
 			return paths, []Fork{fork}
 		}
 	}
@@ -595,10 +734,12 @@ func buildPaths(policy *tpdl.Policy, activeTrusted []*attestation) ([]Path, []Fo
 			}
 			forks = append(forks, fork)
 		}
+		tr.add(TracePhasePaths, "authorship: "+itoa(len(paths))+" authorship claim(s), one path each", allCIDs...)
 		return paths, forks
 	}
 
 	// Otherwise, consider the set compatible and produce one combined path.
+	tr.add(TracePhasePaths, "compatible: one combined path", allCIDs...)
 	return []Path{{ID: "path-1", CIDs: allCIDs}}, nil
 }
 
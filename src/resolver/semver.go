@@ -0,0 +1,444 @@
+package resolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionSelectorKind selects how ResolveNameSelect matches name-binding Version claims.
+type VersionSelectorKind string
+
+const (
+	// VersionAny matches every version (ResolveName with an empty version).
+	VersionAny VersionSelectorKind = ""
+	// VersionExact matches the Version claim by exact string comparison.
+	VersionExact VersionSelectorKind = "exact"
+	// VersionRange matches semantic versions satisfying a range expression.
+	VersionRange VersionSelectorKind = "range"
+	// VersionLatest matches the highest semantic version.
+	VersionLatest VersionSelectorKind = "latest"
+)
+
+// VersionSelector is a deterministic name-binding version selector.
+//
+// Construct selectors with ExactVersion, LatestVersion, SemverRange or
+// ParseVersionSelector; the zero value matches any version.
+type VersionSelector struct {
+	Kind VersionSelectorKind
+	Expr string
+
+	set [][]semverComparator
+}
+
+// ExactVersion selects bindings whose Version claim equals v exactly.
+// An empty v selects any version.
+func ExactVersion(v string) VersionSelector {
+	if v == "" {
+		return VersionSelector{}
+	}
+	return VersionSelector{Kind: VersionExact, Expr: v}
+}
+
+// LatestVersion selects the highest semantic version. Pre-releases are not
+// considered.
+func LatestVersion() VersionSelector {
+	return VersionSelector{Kind: VersionLatest, Expr: "latest"}
+}
+
+// SemverRange selects semantic versions satisfying expr.
+//
+// Supported syntax (npm-style subset):
+//   - comparators: =1.2.3, >1.2.3, >=1.2.3, <1.2.3, <=1.2.3
+//   - wildcards: *, 1.x, 1.2.x (x, X and * are equivalent)
+//   - caret and tilde: ^1.2.3, ~1.2.3
+//   - intersection by whitespace, union by "||"
+//
+// Pre-release versions only match when a comparator in the same intersection
+// names a pre-release of the same MAJOR.MINOR.PATCH.
+func SemverRange(expr string) (VersionSelector, error) {
+	set, err := parseSemverRange(expr)
+	if err != nil {
+		return VersionSelector{}, err
+	}
+	return VersionSelector{Kind: VersionRange, Expr: strings.TrimSpace(expr), set: set}, nil
+}
+
+// ParseVersionSelector parses "latest" or a semver range expression.
+func ParseVersionSelector(expr string) (VersionSelector, error) {
+	if strings.TrimSpace(expr) == "latest" {
+		return LatestVersion(), nil
+	}
+	return SemverRange(expr)
+}
+
+// semverSelect reports whether the selector orders candidates by semantic version.
+func (s VersionSelector) semverSelect() bool {
+	return s.Kind == VersionRange || s.Kind == VersionLatest
+}
+
+// matches reports whether a Version claim is eligible under the selector.
+func (s VersionSelector) matches(version string) bool {
+	switch s.Kind {
+	case VersionAny:
+		return true
+	case VersionExact:
+		return version == s.Expr
+	case VersionLatest:
+		v, ok := parseSemver(version)
+		return ok && len(v.pre) == 0
+	case VersionRange:
+		v, ok := parseSemver(version)
+		if !ok {
+			return false
+		}
+		for _, and := range s.set {
+			if semverSatisfiesAll(v, and) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+type semver struct {
+	major, minor, patch uint64
+	pre                 []string
+	raw                 string
+}
+
+// parseSemver parses a SemVer 2.0.0 version. A leading "v" is accepted.
+func parseSemver(s string) (semver, bool) {
+	v := semver{raw: s}
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		if !validIdentifiers(s[i+1:], false) {
+			return semver{}, false
+		}
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if !validIdentifiers(s[i+1:], true) {
+			return semver{}, false
+		}
+		v.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	nums := make([]uint64, 3)
+	for i, p := range parts {
+		n, ok := parseNumericIdentifier(p)
+		if !ok {
+			return semver{}, false
+		}
+		nums[i] = n
+	}
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+	return v, true
+}
+
+func parseNumericIdentifier(p string) (uint64, bool) {
+	if p == "" || (len(p) > 1 && p[0] == '0') {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(p, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func validIdentifiers(s string, pre bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		numeric := true
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+		if pre && numeric && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareSemver orders versions by SemVer precedence, breaking precedence ties
+// (build metadata) by the raw string so the order is total.
+func compareSemver(a, b semver) int {
+	if c := comparePrecedence(a, b); c != 0 {
+		return c
+	}
+	return strings.Compare(a.raw, b.raw)
+}
+
+func comparePrecedence(a, b semver) int {
+	for _, d := range [][2]uint64{{a.major, b.major}, {a.minor, b.minor}, {a.patch, b.patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		if c := compareIdentifier(a.pre[i], b.pre[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a.pre) < len(b.pre):
+		return -1
+	case len(a.pre) > len(b.pre):
+		return 1
+	}
+	return 0
+}
+
+func compareIdentifier(a, b string) int {
+	an, aNum := parseNumericIdentifier(a)
+	bn, bNum := parseNumericIdentifier(b)
+	switch {
+	case aNum && bNum:
+		if an == bn {
+			return 0
+		}
+		if an < bn {
+			return -1
+		}
+		return 1
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+type semverComparator struct {
+	op string // one of "=", ">", ">=", "<", "<="
+	v  semver
+}
+
+func (c semverComparator) satisfiedBy(v semver) bool {
+	cmp := comparePrecedence(v, c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func semverSatisfiesAll(v semver, and []semverComparator) bool {
+	for _, c := range and {
+		if !c.satisfiedBy(v) {
+			return false
+		}
+	}
+	if len(v.pre) == 0 {
+		return true
+	}
+	for _, c := range and {
+		if len(c.v.pre) > 0 && c.v.major == v.major && c.v.minor == v.minor && c.v.patch == v.patch {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSemverRange(expr string) ([][]semverComparator, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty version range")
+	}
+	var out [][]semverComparator
+	for _, alt := range strings.Split(expr, "||") {
+		fields := strings.Fields(alt)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", expr)
+		}
+		var and []semverComparator
+		for _, f := range fields {
+			cs, err := parseComparator(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %w", expr, err)
+			}
+			and = append(and, cs...)
+		}
+		out = append(out, and)
+	}
+	return out, nil
+}
+
+// partialVersion is a version with optional (wildcard) trailing components.
+type partialVersion struct {
+	nums [3]uint64
+	n    int // number of concrete numeric components
+	pre  []string
+}
+
+func parsePartial(s string) (partialVersion, error) {
+	var p partialVersion
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if !validIdentifiers(s[i+1:], true) {
+			return p, fmt.Errorf("invalid pre-release in %q", s)
+		}
+		p.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("invalid version %q", s)
+	}
+	wild := false
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wild = true
+			continue
+		}
+		if wild {
+			return p, fmt.Errorf("invalid version %q: number after wildcard", s)
+		}
+		n, ok := parseNumericIdentifier(part)
+		if !ok {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+		p.nums[i] = n
+		p.n = i + 1
+	}
+	if p.pre != nil && p.n != 3 {
+		return p, fmt.Errorf("invalid version %q: pre-release requires MAJOR.MINOR.PATCH", s)
+	}
+	return p, nil
+}
+
+func (p partialVersion) lower() semver {
+	return semver{major: p.nums[0], minor: p.nums[1], patch: p.nums[2], pre: p.pre}
+}
+
+// bump returns the exclusive upper bound obtained by incrementing component
+// (0 = major, 1 = minor, 2 = patch) and zeroing the rest.
+func (p partialVersion) bump(component int) semver {
+	switch component {
+	case 0:
+		return semver{major: p.nums[0] + 1}
+	case 1:
+		return semver{major: p.nums[0], minor: p.nums[1] + 1}
+	default:
+		return semver{major: p.nums[0], minor: p.nums[1], patch: p.nums[2] + 1}
+	}
+}
+
+// span returns the [lower, upper) bounds covered by a partial version.
+func (p partialVersion) span() []semverComparator {
+	switch p.n {
+	case 0:
+		return []semverComparator{{op: ">=", v: semver{}}}
+	case 1, 2:
+		return []semverComparator{{op: ">=", v: p.lower()}, {op: "<", v: p.bump(p.n - 1)}}
+	default:
+		return []semverComparator{{op: "=", v: p.lower()}}
+	}
+}
+
+func parseComparator(s string) ([]semverComparator, error) {
+	switch {
+	case strings.HasPrefix(s, "^"):
+		p, err := parsePartial(s[1:])
+		if err != nil {
+			return nil, err
+		}
+		if p.n == 0 {
+			return p.span(), nil
+		}
+		// Bump the left-most non-zero concrete component; when all are zero,
+		// bump the last concrete one (^0.0.3 -> <0.0.4, ^0.0 -> <0.1.0).
+		component := p.n - 1
+		for i := 0; i < p.n; i++ {
+			if p.nums[i] != 0 {
+				component = i
+				break
+			}
+		}
+		return []semverComparator{{op: ">=", v: p.lower()}, {op: "<", v: p.bump(component)}}, nil
+	case strings.HasPrefix(s, "~"):
+		p, err := parsePartial(s[1:])
+		if err != nil {
+			return nil, err
+		}
+		if p.n <= 1 {
+			return p.span(), nil
+		}
+		return []semverComparator{{op: ">=", v: p.lower()}, {op: "<", v: p.bump(1)}}, nil
+	}
+
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = s[len(candidate):]
+			break
+		}
+	}
+	p, err := parsePartial(s)
+	if err != nil {
+		return nil, err
+	}
+	if op == "" || op == "=" || p.n == 0 {
+		if p.n == 0 && (op == "<" || op == ">") {
+			// "<*" and ">*" match nothing.
+			return []semverComparator{{op: "<", v: semver{}}}, nil
+		}
+		return p.span(), nil
+	}
+	if p.n == 3 {
+		return []semverComparator{{op: op, v: p.lower()}}, nil
+	}
+	// Partial versions with an operator compare against the covered span.
+	upper := p.bump(p.n - 1)
+	switch op {
+	case ">":
+		return []semverComparator{{op: ">=", v: upper}}, nil
+	case ">=":
+		return []semverComparator{{op: ">=", v: p.lower()}}, nil
+	case "<":
+		return []semverComparator{{op: "<", v: p.lower()}}, nil
+	default: // "<="
+		return []semverComparator{{op: "<", v: upper}}, nil
+	}
+}
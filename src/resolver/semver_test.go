@@ -0,0 +1,110 @@
+package resolver
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	valid := []string{"1.2.3", "v1.2.3", "0.0.0", "1.2.3-alpha.1", "1.2.3+build.5", "1.2.3-rc.1+sha.abc"}
+	for _, v := range valid {
+		if _, ok := parseSemver(v); !ok {
+			t.Fatalf("parseSemver(%q): expected valid", v)
+		}
+	}
+	invalid := []string{"", "1", "1.2", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "a.b.c", "final"}
+	for _, v := range invalid {
+		if _, ok := parseSemver(v); ok {
+			t.Fatalf("parseSemver(%q): expected error", v)
+		}
+	}
+}
+
+func TestCompareSemver_Precedence(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := parseSemver(ordered[i])
+		b, _ := parseSemver(ordered[i+1])
+		if compareSemver(a, b) >= 0 || compareSemver(b, a) <= 0 {
+			t.Fatalf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	// Equal precedence is broken deterministically by the raw string.
+	a, _ := parseSemver("1.0.0")
+	b, _ := parseSemver("v1.0.0")
+	if comparePrecedence(a, b) != 0 || compareSemver(a, b) >= 0 {
+		t.Fatalf("expected equal precedence with raw-string tiebreak")
+	}
+}
+
+func TestVersionSelector_Matches(t *testing.T) {
+	cases := []struct {
+		expr    string
+		version string
+		want    bool
+	}{
+		{"latest", "2.0.0", true},
+		{"latest", "2.0.0-rc.1", false},
+		{"latest", "final", false},
+		{"^1.2.0", "1.9.9", true},
+		{"^1.2.0", "2.0.0", false},
+		{"^1.2.0", "1.1.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"1.x", "1.4.0", true},
+		{"1.x", "2.0.0", false},
+		{"*", "3.1.4", true},
+		{">=1.0.0 <2.0.0", "1.5.0", true},
+		{">=1.0.0 <2.0.0", "2.0.0", false},
+		{"<1.0.0 || >=3.0.0", "3.0.0", true},
+		{"<1.0.0 || >=3.0.0", "2.0.0", false},
+		{">1.2", "1.2.9", false},
+		{">1.2", "1.3.0", true},
+		{"=1.2.3", "v1.2.3", true},
+		{">=1.2.3-beta.1", "1.2.3-beta.2", true},
+		{">=1.2.3-beta.1", "1.2.4-beta.1", false},
+		{">=1.2.3-beta.1", "1.2.4", true},
+		{"^1.0.0", "1.0.0+build.1", true},
+	}
+	for _, c := range cases {
+		sel, err := ParseVersionSelector(c.expr)
+		if err != nil {
+			t.Fatalf("ParseVersionSelector(%q): %v", c.expr, err)
+		}
+		if got := sel.matches(c.version); got != c.want {
+			t.Fatalf("%q matches %q: got %v want %v", c.expr, c.version, got, c.want)
+		}
+	}
+}
+
+func TestParseVersionSelector_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "^", ">=", "1.2.3.4", "^x.1", "1.2.3 ||", "~>1.0"} {
+		if _, err := ParseVersionSelector(expr); err == nil {
+			t.Fatalf("ParseVersionSelector(%q): expected error", expr)
+		}
+	}
+}
+
+func TestExactVersion(t *testing.T) {
+	if sel := ExactVersion(""); sel.Kind != VersionAny || !sel.matches("anything") {
+		t.Fatalf("expected empty exact version to match any version")
+	}
+	sel := ExactVersion("final")
+	if sel.Kind != VersionExact || !sel.matches("final") || sel.matches("1.0.0") {
+		t.Fatalf("unexpected exact selector behaviour")
+	}
+}
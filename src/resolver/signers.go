@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"sort"
+
+	"xdao.co/catf/catf"
+)
+
+// verifiedSigners returns the issuer keys of a, in sorted order, when every
+// signature verifies.
+//
+// Like catf.Verify, a co-signed attestation fails as a whole when any entry
+// fails: CRYPTO is outside the signed scope, so crediting the valid entries
+// would let anyone derive new, still-trusted CIDs by appending bad entries.
+func verifiedSigners(a *catf.CATF) ([]string, error) {
+	if !a.IsCoSigned() {
+		if err := a.Verify(); err != nil {
+			return nil, err
+		}
+		return []string{a.IssuerKey()}, nil
+	}
+	results, err := a.VerifySigners()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		keys = append(keys, r.IssuerKey)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// trustedSignerRoles maps each verified signer trusted by policy for a to its
+// roles (see policyTrust.rolesFor). Untrusted and denied signers are omitted;
+// the result is nil when no signer is trusted.
+func trustedSignerRoles(trust *policyTrust, signers []string, a *catf.CATF) map[string]map[string]bool {
+	var out map[string]map[string]bool
+	for _, k := range signers {
+		roles := trust.rolesFor(k, a)
+		if len(roles) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]map[string]bool)
+		}
+		out[k] = roles
+	}
+	return out
+}
+
+// unionRoles returns the union of all roles held by trusted signers.
+func unionRoles(signerRoles map[string]map[string]bool) map[string]bool {
+	out := make(map[string]bool)
+	for _, roles := range signerRoles {
+		for r := range roles {
+			out[r] = true
+		}
+	}
+	return out
+}
+
+// holdsAny reports whether roles contains any of want.
+func holdsAny(roles map[string]bool, want []string) bool {
+	for _, r := range want {
+		if roles[r] {
+			return true
+		}
+	}
+	return false
+}
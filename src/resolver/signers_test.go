@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/keys"
+)
+
+// mustCoSignedAttestation builds a co-signed attestation. Signers listed in
+// tamper get a signature over unrelated bytes so that only their entry fails.
+func mustCoSignedAttestation(t *testing.T, subjectCID, description string, claims map[string]string, privs []ed25519.PrivateKey, tamper map[int]bool) []byte {
+	t.Helper()
+
+	doc := catf.Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": subjectCID, "Description": description},
+		Claims:  claims,
+	}
+	entries := make([]catf.SignatureEntry, len(privs))
+	for i, priv := range privs {
+		entries[i] = catf.SignatureEntry{
+			IssuerKey:    issuerKey(priv.Public().(ed25519.PublicKey)),
+			SignatureAlg: "ed25519",
+			Signature:    "0",
+		}
+	}
+	crypto, err := catf.CoSignedCrypto("sha256", entries)
+	if err != nil {
+		t.Fatalf("co-signed crypto pre: %v", err)
+	}
+	doc.Crypto = crypto
+	pre, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render pre: %v", err)
+	}
+	parsed, err := catf.Parse(pre)
+	if err != nil {
+		t.Fatalf("parse pre: %v", err)
+	}
+	for i, priv := range privs {
+		msg := parsed.SignedBytes()
+		if tamper[i] {
+			msg = []byte("tampered")
+		}
+		entries[i].Signature = keys.SignEd25519SHA256(msg, priv)
+	}
+	crypto, err = catf.CoSignedCrypto("sha256", entries)
+	if err != nil {
+		t.Fatalf("co-signed crypto: %v", err)
+	}
+	doc.Crypto = crypto
+	out, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return out
+}
+
+func TestResolve_CoSignedAttestationSatisfiesQuorum(t *testing.T) {
+	subject := "bafy-contract-cosigned"
+	pubA, privA := mustKeypair(t, 0x51)
+	pubB, privB := mustKeypair(t, 0x52)
+
+	att := mustCoSignedAttestation(t, subject, "Contract", map[string]string{
+		"Effective-Date": "2026-01-10",
+		"Role":           "party",
+		"Type":           "approval",
+	}, []ed25519.PrivateKey{privA, privB}, nil)
+
+	policy := trustPolicy(
+		[]trustEntry{{issuerKey(pubA), "party"}, {issuerKey(pubB), "party"}},
+		[]requireRule{{"approval", "party", 2}},
+	)
+
+	res, err := Resolve([][]byte{att}, []byte(policy), subject)
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved, got %s", res.State)
+	}
+	if len(res.Verdicts) != 1 {
+		t.Fatalf("expected 1 verdict, got %d", len(res.Verdicts))
+	}
+	v := res.Verdicts[0]
+	if !v.Trusted || len(v.SignerKeys) != 2 {
+		t.Fatalf("expected trusted verdict with 2 signer keys, got %+v", v)
+	}
+	if len(res.PolicyVerdicts) != 1 || !res.PolicyVerdicts[0].Satisfied || res.PolicyVerdicts[0].Observed != 2 {
+		t.Fatalf("expected satisfied quorum with 2 observed signers, got %+v", res.PolicyVerdicts)
+	}
+}
+
+func TestResolve_CoSignedAttestationInvalidCoSignerRejected(t *testing.T) {
+	subject := "bafy-contract-cosigned-partial"
+	pubA, privA := mustKeypair(t, 0x51)
+	pubB, privB := mustKeypair(t, 0x52)
+
+	// B's signature covers unrelated bytes. As with catf.Verify (and so the
+	// index), the whole attestation is invalid; A is not credited either.
+	att := mustCoSignedAttestation(t, subject, "Contract", map[string]string{
+		"Effective-Date": "2026-01-10",
+		"Role":           "party",
+		"Type":           "approval",
+	}, []ed25519.PrivateKey{privA, privB}, map[int]bool{1: true})
+
+	policy := trustPolicy(
+		[]trustEntry{{issuerKey(pubA), "party"}, {issuerKey(pubB), "party"}},
+		[]requireRule{{"approval", "party", 1}},
+	)
+
+	res, err := Resolve([][]byte{att}, []byte(policy), subject)
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("expected Unresolved, got %s", res.State)
+	}
+	if len(res.Verdicts) != 1 {
+		t.Fatalf("expected 1 verdict, got %d", len(res.Verdicts))
+	}
+	v := res.Verdicts[0]
+	if v.Status != VerdictInvalid || v.ExcludedReason != "Signature invalid" || len(v.SignerKeys) != 0 {
+		t.Fatalf("expected invalid verdict, got %+v", v)
+	}
+	doc, err := catf.Parse(att)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Verify() == nil {
+		t.Fatalf("catf.Verify accepted an attestation the resolver rejects")
+	}
+}
@@ -1,6 +1,11 @@
 package resolver
 
-import "testing"
+import (
+	"testing"
+
+	"xdao.co/catf/compliance"
+	"xdao.co/catf/tpdl"
+)
 
 func TestResolveStrict_RejectsPolicyMissingExplicitQuorum(t *testing.T) {
 	subject := "bafy-strict-policy-quorum"
@@ -82,3 +87,64 @@ func TestResolveNameStrict_RejectsPolicyMissingExplicitQuorum(t *testing.T) {
 		t.Fatalf("expected strict mode error")
 	}
 }
+
+func TestResolveWithCASStrict_RequiresCanonicalPolicy(t *testing.T) {
+	subject := "bafy-strict-policy-canonical"
+	pub, priv := mustKeypair(t, 0x48)
+	issuer := issuerKey(pub)
+
+	att := mustAttestation(t, subject, "Doc", map[string]string{"Type": "authorship", "Role": "author"}, issuer, priv)
+
+	// Strict-valid (explicit Quorum) but not canonical: Role precedes Type.
+	policy := "" +
+		"-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n\n" +
+		"TRUST\n" +
+		"Key: " + issuer + "\n" +
+		"Role: author\n\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Role: author\n" +
+		"  Type: authorship\n" +
+		"  Quorum: 1\n" +
+		"-----END XDAO TRUST POLICY-----\n"
+	req := ResolveRequestCAS{
+		Attestations: []BlobRef{{Bytes: att}},
+		Policy:       BlobRef{Bytes: []byte(policy)},
+		SubjectCID:   subject,
+		Compliance:   compliance.Permissive,
+	}
+
+	// Permissive mode binds the policy bytes as given.
+	if _, err := ResolveWithCAS(req); err != nil {
+		t.Fatalf("ResolveWithCAS(permissive): %v", err)
+	}
+
+	req.Compliance = compliance.Strict
+	if _, err := ResolveWithCAS(req); tpdl.RuleID(err) != "TPDL-CANON-001" {
+		t.Fatalf("expected TPDL-CANON-001, got %v", err)
+	}
+
+	p, err := tpdl.Parse([]byte(policy))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	canon, err := tpdl.Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want, err := tpdl.CID(canon)
+	if err != nil {
+		t.Fatalf("CID: %v", err)
+	}
+	req.Policy = BlobRef{Bytes: canon}
+	out, err := ResolveWithCAS(req)
+	if err != nil {
+		t.Fatalf("ResolveWithCAS(strict, canonical): %v", err)
+	}
+	if out.TrustPolicyCID != want {
+		t.Fatalf("TrustPolicyCID = %s, want %s", out.TrustPolicyCID, want)
+	}
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trace phases, in the order a resolution records them.
+const (
+	TracePhaseInputs     = "inputs"
+	TracePhaseRevocation = "revocation"
+	TracePhaseSubject    = "subject"
+	TracePhasePolicy     = "policy"
+	TracePhasePaths      = "paths"
+	TracePhaseState      = "state"
+)
+
+// TraceStep is one resolver decision in a Resolution.Trace.
+//
+// Phase is one of the TracePhase* constants. Decision describes what was
+// decided and is intended for humans; do not match on it. Inputs lists the
+// attestation CIDs (or issuer keys, for policy rules) the decision considered,
+// sorted.
+type TraceStep struct {
+	Phase    string
+	Decision string
+	Inputs   []string
+}
+
+func (s TraceStep) String() string {
+	if len(s.Inputs) == 0 {
+		return s.Phase + ": " + s.Decision
+	}
+	return s.Phase + ": " + s.Decision + " [" + strings.Join(s.Inputs, ", ") + "]"
+}
+
+func newTracer(enabled bool) *tracer {
+	if !enabled {
+		return nil
+	}
+	return &tracer{}
+}
+
+// tracer collects trace steps. A nil tracer records nothing, so tracing costs
+// nothing unless requested.
+type tracer struct {
+	steps []TraceStep
+}
+
+func (t *tracer) add(phase, decision string, inputs ...string) {
+	if t == nil {
+		return
+	}
+	in := append([]string(nil), inputs...)
+	sort.Strings(in)
+	t.steps = append(t.steps, TraceStep{Phase: phase, Decision: decision, Inputs: in})
+}
+
+func (t *tracer) result() []TraceStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
+
+// traceInputs summarizes per-input verification, before revocations apply.
+func traceInputs(tr *tracer, verdicts []Verdict, asOf time.Time) {
+	counts := make(map[VerdictStatus]int)
+	ids := make([]string, 0, len(verdicts))
+	for _, v := range verdicts {
+		counts[v.Status]++
+		if v.CID != "" {
+			ids = append(ids, v.CID)
+		} else {
+			ids = append(ids, v.InputHash)
+		}
+	}
+	decision := itoa(len(verdicts)) + " input(s): " + itoa(counts[VerdictTrusted]) + " trusted, " +
+		itoa(counts[VerdictExcluded]) + " excluded, " + itoa(counts[VerdictInvalid]) + " invalid"
+	if !asOf.IsZero() {
+		decision += " as of " + asOf.Format(time.RFC3339)
+	}
+	tr.add(TracePhaseInputs, decision, ids...)
+}
+
+// tracePolicyVerdicts records each Require rule's outcome.
+func tracePolicyVerdicts(tr *tracer, verdicts []PolicyVerdict) {
+	if len(verdicts) == 0 {
+		tr.add(TracePhasePolicy, "no Require rules apply")
+		return
+	}
+	for _, v := range verdicts {
+		status := "unsatisfied"
+		if v.Satisfied {
+			status = "satisfied"
+		}
+		tr.add(TracePhasePolicy, v.Type+"/"+v.Role+" "+status+": "+itoa(v.Observed)+" of quorum "+itoa(v.Quorum), v.IssuerKeys...)
+	}
+}
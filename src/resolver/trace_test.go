@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestResolveWithOptions_Trace(t *testing.T) {
+	subject := "bafy-traced-deed"
+	aPub, aPriv := mustKeypair(t, 0x91)
+	bPub, bPriv := mustKeypair(t, 0x92)
+	xPub, xPriv := mustKeypair(t, 0x93)
+	a, b, stranger := issuerKey(aPub), issuerKey(bPub), issuerKey(xPub)
+
+	byA := approvalOn(t, subject, "2026-01-01", a, aPriv)
+	byB := approvalOn(t, subject, "2026-01-02", b, bPriv)
+	revoke := func(issuer string, priv []byte) []byte {
+		return mustAttestation(t, subject, "Deed", map[string]string{"Target-Attestation": mustCID(t, byB), "Type": "revocation"}, issuer, priv)
+	}
+	untrusted := revoke(stranger, xPriv)
+	policy := []byte(trustPolicy([]trustEntry{{a, "buyer"}, {b, "buyer"}}, []requireRule{{"approval", "buyer", 1}}))
+	atts := [][]byte{byA, byB, untrusted}
+
+	plain, err := ResolveWithOptions(atts, policy, subject, Options{})
+	if err != nil {
+		t.Fatalf("ResolveWithOptions: %v", err)
+	}
+	if plain.Trace != nil {
+		t.Fatalf("trace recorded without Options.Trace: %+v", plain.Trace)
+	}
+
+	res, err := ResolveWithOptions(atts, policy, subject, Options{Trace: true})
+	if err != nil {
+		t.Fatalf("ResolveWithOptions: %v", err)
+	}
+	if res.State != StateForked || !reflect.DeepEqual(res.Paths, plain.Paths) {
+		t.Fatalf("tracing changed the resolution: %+v", res)
+	}
+	var phases []string
+	for _, s := range res.Trace {
+		phases = append(phases, s.Phase)
+	}
+	want := []string{TracePhaseInputs, TracePhaseRevocation, TracePhaseSubject, TracePhasePolicy, TracePhasePaths, TracePhaseState}
+	if !reflect.DeepEqual(phases, want) {
+		t.Fatalf("phases %v, want %v", phases, want)
+	}
+	if s := res.Trace[1]; !strings.Contains(s.Decision, "not trusted") || !reflect.DeepEqual(s.Inputs, sorted(mustCID(t, untrusted), mustCID(t, byB))) {
+		t.Fatalf("unexpected revocation step %+v", s)
+	}
+	if s := res.Trace[4]; !strings.HasPrefix(s.Decision, "quorum ambiguity: approval/buyer") || len(s.Inputs) != 2 {
+		t.Fatalf("unexpected paths step %+v", s)
+	}
+
+	// The trace does not depend on input order.
+	again, err := ResolveWithOptions([][]byte{untrusted, byB, byA}, policy, subject, Options{Trace: true})
+	if err != nil || !reflect.DeepEqual(again.Trace, res.Trace) {
+		t.Fatalf("order-dependent trace: %v\n%+v\n%+v", err, again.Trace, res.Trace)
+	}
+
+	// A trusted revocation applies and removes the ambiguity.
+	res, err = ResolveWithOptions(append(atts, revoke(a, aPriv)), policy, subject, Options{Trace: true})
+	if err != nil {
+		t.Fatalf("ResolveWithOptions: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved, got %s", res.State)
+	}
+	found := false
+	for _, s := range res.Trace {
+		if s.Phase == TracePhaseRevocation && s.Decision == "revoked "+mustCID(t, byB) {
+			found = true
+		}
+	}
+	if !found || res.Trace[len(res.Trace)-2].Decision != "compatible: one combined path" {
+		t.Fatalf("unexpected trace %+v", res.Trace)
+	}
+}
+
+func sorted(s ...string) []string {
+	sort.Strings(s)
+	return s
+}
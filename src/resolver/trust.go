@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"sort"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/tpdl"
+)
+
+// ReasonIssuerDenied is reported when a signer matches a policy Deny entry.
+const ReasonIssuerDenied = "Issuer denied by policy"
+
+// trustScope restricts a role to a claim type and/or subject. Empty fields
+// are unrestricted. Scopes come from xdao-tpdl-2 TRUST entries and from
+// delegation attestations.
+type trustScope struct {
+	typ     string
+	subject string
+}
+
+// covers reports whether every attestation admitted by o is admitted by s.
+func (s trustScope) covers(o trustScope) bool {
+	return (s.typ == "" || s.typ == o.typ) && (s.subject == "" || s.subject == o.subject)
+}
+
+// narrow intersects two scopes; ok is false when they admit nothing in common.
+func (s trustScope) narrow(o trustScope) (trustScope, bool) {
+	out := s
+	if o.typ != "" {
+		if s.typ != "" && s.typ != o.typ {
+			return trustScope{}, false
+		}
+		out.typ = o.typ
+	}
+	if o.subject != "" {
+		if s.subject != "" && s.subject != o.subject {
+			return trustScope{}, false
+		}
+		out.subject = o.subject
+	}
+	return out, true
+}
+
+// admits reports whether a role in this scope applies to a.
+// Delegation attestations are admitted regardless of scope; their grants are
+// narrowed to the delegator's scope when chains are built.
+func (s trustScope) admits(a *catf.CATF) bool {
+	if a.ClaimType() == "delegation" {
+		return true
+	}
+	return (s.typ == "" || s.typ == a.ClaimType()) && (s.subject == "" || s.subject == a.SubjectCID())
+}
+
+type scopedRole struct {
+	role  string
+	scope trustScope
+}
+
+// policyTrust is the TRUST section of a policy indexed by key.
+type policyTrust struct {
+	roles  map[string]map[string]bool // unscoped Key/Role entries
+	scoped map[string][]scopedRole    // entries restricted by Type and/or Subject
+	deny   map[string][]string        // denied claim types per key; "" denies all
+}
+
+func indexTrust(policy *tpdl.Policy) *policyTrust {
+	idx := &policyTrust{
+		roles:  make(map[string]map[string]bool),
+		scoped: make(map[string][]scopedRole),
+		deny:   make(map[string][]string),
+	}
+	for _, t := range policy.Trust {
+		if t.Type != "" || t.Subject != "" {
+			idx.scoped[t.Key] = append(idx.scoped[t.Key], scopedRole{role: t.Role, scope: trustScope{typ: t.Type, subject: t.Subject}})
+			continue
+		}
+		m := idx.roles[t.Key]
+		if m == nil {
+			m = make(map[string]bool)
+			idx.roles[t.Key] = m
+		}
+		m[t.Role] = true
+	}
+	for _, d := range policy.Deny {
+		idx.deny[d.Key] = append(idx.deny[d.Key], d.Type)
+	}
+	return idx
+}
+
+// denies reports whether a Deny entry excludes key for claim type typ.
+func (t *policyTrust) denies(key, typ string) bool {
+	for _, d := range t.deny[key] {
+		if d == "" || d == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// rolesFor returns the roles key holds for a: its unscoped roles plus the
+// scoped roles whose scope admits a. It is nil when key is denied for a.
+func (t *policyTrust) rolesFor(key string, a *catf.CATF) map[string]bool {
+	if t.denies(key, a.ClaimType()) {
+		return nil
+	}
+	if len(t.scoped[key]) == 0 {
+		return t.roles[key]
+	}
+	var out map[string]bool
+	add := func(r string) {
+		if out == nil {
+			out = make(map[string]bool)
+		}
+		out[r] = true
+	}
+	for r := range t.roles[key] {
+		add(r)
+	}
+	for _, s := range t.scoped[key] {
+		if s.scope.admits(a) {
+			add(s.role)
+		}
+	}
+	return out
+}
+
+// holds reports whether key holds role through TRUST in a scope covering scope.
+func (t *policyTrust) holds(key, role string, scope trustScope) bool {
+	if t.roles[key][role] {
+		return true
+	}
+	for _, s := range t.scoped[key] {
+		if s.role == role && s.scope.covers(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// untrustedReason is the exclusion reason for an attestation none of whose
+// signers is trusted: ReasonIssuerDenied when a Deny entry matched a signer.
+func untrustedReason(t *policyTrust, signers []string, a *catf.CATF) string {
+	for _, k := range signers {
+		if t.denies(k, a.ClaimType()) {
+			return ReasonIssuerDenied
+		}
+	}
+	return "Issuer not trusted"
+}
+
+// withSuccessors returns a copy of t in which each key maps to the TRUST
+// entries and Deny entries of the keys it replaced, following chains.
+func (t *policyTrust) withSuccessors(predecessors map[string][]string) *policyTrust {
+	if len(predecessors) == 0 {
+		return t
+	}
+	out := &policyTrust{
+		roles:  make(map[string]map[string]bool, len(t.roles)),
+		scoped: make(map[string][]scopedRole, len(t.scoped)),
+		deny:   make(map[string][]string, len(t.deny)),
+	}
+	for k, roles := range t.roles {
+		m := make(map[string]bool, len(roles))
+		for r := range roles {
+			m[r] = true
+		}
+		out.roles[k] = m
+	}
+	for k, s := range t.scoped {
+		out.scoped[k] = append([]scopedRole(nil), s...)
+	}
+	for k, d := range t.deny {
+		out.deny[k] = append([]string(nil), d...)
+	}
+	newKeys := make([]string, 0, len(predecessors))
+	for k := range predecessors {
+		newKeys = append(newKeys, k)
+	}
+	sort.Strings(newKeys)
+	for changed := true; changed; {
+		changed = false
+		for _, newKey := range newKeys {
+			for _, old := range predecessors[newKey] {
+				for r := range out.roles[old] {
+					if out.roles[newKey] == nil {
+						out.roles[newKey] = make(map[string]bool)
+					}
+					if !out.roles[newKey][r] {
+						out.roles[newKey][r] = true
+						changed = true
+					}
+				}
+				for _, s := range out.scoped[old] {
+					if !containsScopedRole(out.scoped[newKey], s) {
+						out.scoped[newKey] = append(out.scoped[newKey], s)
+						changed = true
+					}
+				}
+				for _, d := range out.deny[old] {
+					if !containsString(out.deny[newKey], d) {
+						out.deny[newKey] = append(out.deny[newKey], d)
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+func containsScopedRole(items []scopedRole, s scopedRole) bool {
+	for _, it := range items {
+		if it == s {
+			return true
+		}
+	}
+	return false
+}
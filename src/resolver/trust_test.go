@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"testing"
+)
+
+func TestResolveTPDLV2_DenyOverridesTrust(t *testing.T) {
+	subject := "bafy-denied-deed"
+	buyerPub, buyerPriv := mustKeypair(t, 0x61)
+	sellerPub, sellerPriv := mustKeypair(t, 0x62)
+	buyer, seller := issuerKey(buyerPub), issuerKey(sellerPub)
+
+	byBuyer := approvalOn(t, subject, "2026-01-01", buyer, buyerPriv)
+	bySeller := mustAttestation(t, subject, "Deed", map[string]string{"Effective-Date": "2026-01-01", "Role": "seller", "Type": "approval"}, seller, sellerPriv)
+	authorship := mustAttestation(t, subject, "Deed", map[string]string{"Role": "seller", "Type": "authorship"}, seller, sellerPriv)
+	policy := []byte(`-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ` + buyer + `
+Role: buyer
+
+Key: ` + seller + `
+Role: seller
+
+Deny: ` + buyer + `
+
+Deny: ` + seller + `
+Type: approval
+
+RULES
+Require:
+  Type: approval
+  Any-Of: buyer, seller
+  Quorum: 1
+-----END XDAO TRUST POLICY-----
+`)
+
+	res, err := Resolve([][]byte{byBuyer, bySeller, authorship}, policy, subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, byBuyer), ReasonIssuerDenied)
+	assertExclusion(t, res, mustCID(t, bySeller), ReasonIssuerDenied)
+	// A type-scoped Deny leaves the seller trusted for other claim types.
+	if v := findVerdict(t, res.Verdicts, mustCID(t, authorship)); !v.Trusted {
+		t.Fatalf("authorship verdict %+v", v)
+	}
+}
+
+func TestResolveTPDLV2_ScopedTrustAndAnyOf(t *testing.T) {
+	subject := "bafy-inspected-deed"
+	buyerPub, buyerPriv := mustKeypair(t, 0x63)
+	inspectorPub, inspectorPriv := mustKeypair(t, 0x64)
+	buyer, inspector := issuerKey(buyerPub), issuerKey(inspectorPub)
+
+	byBuyer := approvalOn(t, subject, "2026-01-01", buyer, buyerPriv)
+	byInspector := mustAttestation(t, subject, "Deed", map[string]string{"Effective-Date": "2026-01-01", "Role": "inspector", "Type": "approval"}, inspector, inspectorPriv)
+	inspectorAuthorship := mustAttestation(t, subject, "Deed", map[string]string{"Role": "inspector", "Type": "authorship"}, inspector, inspectorPriv)
+	policy := func(scopeSubject string) []byte {
+		return []byte(`-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ` + buyer + `
+Role: buyer
+
+Key: ` + inspector + `
+Role: inspector
+Type: approval
+Subject: ` + scopeSubject + `
+
+RULES
+Require:
+  Type: approval
+  Any-Of: buyer, inspector
+  Quorum: 2
+-----END XDAO TRUST POLICY-----
+`)
+	}
+
+	res, err := Resolve([][]byte{byBuyer, byInspector, inspectorAuthorship}, policy(subject), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateResolved {
+		t.Fatalf("expected Resolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, inspectorAuthorship), "Issuer not trusted")
+	if len(res.PolicyVerdicts) != 1 {
+		t.Fatalf("expected one policy verdict, got %+v", res.PolicyVerdicts)
+	}
+	pv := res.PolicyVerdicts[0]
+	if pv.Role != "buyer|inspector" || pv.Observed != 2 || !pv.Satisfied {
+		t.Fatalf("unexpected policy verdict %+v", pv)
+	}
+
+	// Scoped to another subject, the inspector's role does not apply here.
+	res, err = Resolve([][]byte{byBuyer, byInspector}, policy("bafy-other-deed"), subject)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.State != StateUnresolved {
+		t.Fatalf("out of scope: expected Unresolved, got %s", res.State)
+	}
+	assertExclusion(t, res, mustCID(t, byInspector), "Issuer not trusted")
+}
@@ -82,7 +82,10 @@ func verdictLessV2(a, b Verdict) bool {
 	if strings.Join(a.Reasons, ",") != strings.Join(b.Reasons, ",") {
 		return strings.Join(a.Reasons, ",") < strings.Join(b.Reasons, ",")
 	}
-	return strings.Join(a.RevokedBy, ",") < strings.Join(b.RevokedBy, ",")
+	if strings.Join(a.RevokedBy, ",") != strings.Join(b.RevokedBy, ",") {
+		return strings.Join(a.RevokedBy, ",") < strings.Join(b.RevokedBy, ",")
+	}
+	return strings.Join(a.SignerKeys, ",") < strings.Join(b.SignerKeys, ",")
 }
 
 func evaluatePolicyRules(policy *tpdl.Policy, activeTrusted []*attestation, typFilter string) ([]PolicyVerdict, bool) {
@@ -107,15 +110,16 @@ func evaluatePolicyRules(policy *tpdl.Policy, activeTrusted []*attestation, typF
 		if typFilter != "" && a.catf.ClaimType() != typFilter {
 			continue
 		}
-		issuer := a.catf.IssuerKey()
-		for role := range a.trustRoles {
-			key := a.catf.ClaimType() + "|" + role
-			m := typeRoleToKeys[key]
-			if m == nil {
-				m = make(map[string]bool)
-				typeRoleToKeys[key] = m
+		for issuer, roles := range a.signerRoles {
+			for role := range roles {
+				key := a.catf.ClaimType() + "|" + role
+				m := typeRoleToKeys[key]
+				if m == nil {
+					m = make(map[string]bool)
+					typeRoleToKeys[key] = m
+				}
+				m[issuer] = true
 			}
-			m[issuer] = true
 		}
 	}
 
@@ -129,8 +133,7 @@ func evaluatePolicyRules(policy *tpdl.Policy, activeTrusted []*attestation, typF
 		if q < 1 {
 			q = 1
 		}
-		k := r.Type + "|" + r.Role
-		m := typeRoleToKeys[k]
+		m := ruleKeys(typeRoleToKeys, r)
 		issuerKeys := make([]string, 0, len(m))
 		for key := range m {
 			issuerKeys = append(issuerKeys, key)
@@ -138,7 +141,7 @@ func evaluatePolicyRules(policy *tpdl.Policy, activeTrusted []*attestation, typF
 		sort.Strings(issuerKeys)
 		observed := len(issuerKeys)
 		satisfied := observed >= q
-		pv := PolicyVerdict{Type: r.Type, Role: r.Role, Quorum: q, Observed: observed, IssuerKeys: issuerKeys, Satisfied: satisfied}
+		pv := PolicyVerdict{Type: r.Type, Role: r.RoleLabel(), Quorum: q, Observed: observed, IssuerKeys: issuerKeys, Satisfied: satisfied}
 		if satisfied {
 			pv.Reasons = []string{"Satisfied"}
 		} else {
@@ -164,3 +167,19 @@ func evaluatePolicyRules(policy *tpdl.Policy, activeTrusted []*attestation, typF
 
 	return out, ok
 }
+
+// ruleKeys returns the issuer keys in typeRoleToKeys that count towards r:
+// those holding any of r's roles for r's claim type.
+func ruleKeys(typeRoleToKeys map[string]map[string]bool, r tpdl.Rule) map[string]bool {
+	roles := r.Roles()
+	if len(roles) == 1 {
+		return typeRoleToKeys[r.Type+"|"+roles[0]]
+	}
+	out := make(map[string]bool)
+	for _, role := range roles {
+		for k := range typeRoleToKeys[r.Type+"|"+role] {
+			out[k] = true
+		}
+	}
+	return out
+}
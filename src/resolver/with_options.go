@@ -15,7 +15,7 @@ func ResolveWithOptions(attestationBytes [][]byte, policyBytes []byte, subjectCI
 	if err != nil {
 		return nil, err
 	}
-	res, err := resolveWithPolicy(attestationBytes, policy, subjectCID)
+	res, err := resolveWithPolicy(attestationBytes, policy, subjectCID, opts.AsOf, newTracer(opts.Trace))
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,26 @@ func ResolveNameWithOptions(attestationBytes [][]byte, policyBytes []byte, name,
 	if err != nil {
 		return nil, err
 	}
-	res, err := resolveNameWithPolicy(attestationBytes, policy, name, version)
+	res, err := resolveNameWithPolicy(attestationBytes, policy, name, ExactVersion(version), opts.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Mode == compliance.Strict {
+		if err := enforceStrictNameResolution(res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ResolveNameSelectWithOptions runs ResolveNameSelect and then applies the requested compliance mode.
+func ResolveNameSelectWithOptions(attestationBytes [][]byte, policyBytes []byte, name string, sel VersionSelector, opts Options) (*NameResolution, error) {
+	opts = opts.withDefaults()
+	policy, err := tpdl.ParseWithCompliance(policyBytes, opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resolveNameWithPolicy(attestationBytes, policy, name, sel, opts.AsOf)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,165 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"xdao.co/catf/model"
+)
+
+// Client calls a Resolver gRPC service using model DTOs.
+//
+// Errors are returned as *model.CodedError.
+type Client struct {
+	cc     *grpc.ClientConn
+	client ResolverClient
+
+	// Timeout applies per RPC when non-zero.
+	Timeout time.Duration
+}
+
+type DialOptions struct {
+	// Timeout applies to the initial dial when non-zero.
+	Timeout time.Duration
+
+	// MaxMsgBytes sets both send/recv max sizes when non-zero.
+	MaxMsgBytes int
+}
+
+func Dial(target string, opts DialOptions) (*Client, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if opts.MaxMsgBytes > 0 {
+		dialOpts = append(dialOpts,
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallRecvMsgSize(opts.MaxMsgBytes),
+				grpc.MaxCallSendMsgSize(opts.MaxMsgBytes),
+			),
+		)
+	}
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cc, err := grpc.DialContext(ctx, target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c := NewClient(cc)
+	c.cc = cc
+	return c, nil
+}
+
+// NewClient wraps an existing connection. Close does not close cc.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{client: NewResolverClient(cc)}
+}
+
+func (c *Client) Close() error {
+	if c == nil || c.cc == nil {
+		return nil
+	}
+	return c.cc.Close()
+}
+
+func (c *Client) Resolve(ctx context.Context, req model.ResolverRequest) (*model.ResolverResponse, error) {
+	var out model.ResolverResponse
+	if err := c.call(ctx, c.client.Resolve, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) ResolveName(ctx context.Context, req model.NameResolverRequest) (*model.NameResolverResponse, error) {
+	var out model.NameResolverResponse
+	if err := c.call(ctx, c.client.ResolveName, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) VerifyCROF(ctx context.Context, req model.DocumentRequest) (*model.CROFVerifyResponse, error) {
+	var out model.CROFVerifyResponse
+	if err := c.call(ctx, c.client.VerifyCROF, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RenderCROF resolves req on the server and returns only the CROF, signed
+// when the server holds a resolver key.
+func (c *Client) RenderCROF(ctx context.Context, req model.ResolverRequest) (*model.CROFDocument, error) {
+	var out model.CROFDocument
+	if err := c.call(ctx, c.client.RenderCROF, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type unaryCall func(context.Context, *wrapperspb.BytesValue, ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+
+func (c *Client) call(ctx context.Context, rpc unaryCall, req any, out any) error {
+	if c == nil || c.client == nil {
+		return model.NewError(model.ErrInternal, "nil client")
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return model.NewError(model.ErrInvalidRequest, err.Error())
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	reply, err := rpc(ctx, wrapperspb.Bytes(b))
+	if err != nil {
+		return fromStatus(err)
+	}
+	if err := json.Unmarshal(reply.GetValue(), out); err != nil {
+		return model.NewError(model.ErrInternal, "invalid response: "+err.Error())
+	}
+	return nil
+}
+
+// fromStatus recovers the model error code from a status message written by
+// Server, falling back to the gRPC code.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return model.NewError(model.ErrInternal, err.Error())
+	}
+	if code, msg, found := strings.Cut(st.Message(), ": "); found && CodeFor(model.ErrorCode(code)) == st.Code() && isKnownCode(model.ErrorCode(code)) {
+		return model.NewError(model.ErrorCode(code), msg)
+	}
+	switch st.Code() {
+	case codes.Canceled:
+		return model.NewError(model.ErrCanceled, st.Message())
+	case codes.DeadlineExceeded:
+		return model.NewError(model.ErrDeadlineExceeded, st.Message())
+	default:
+		return model.NewError(model.ErrInternal, st.Message())
+	}
+}
+
+func isKnownCode(code model.ErrorCode) bool {
+	switch code {
+	case model.ErrInvalidRequest, model.ErrInvalidCID, model.ErrMissingCAS, model.ErrNotFound,
+		model.ErrCIDMismatch, model.ErrCanceled, model.ErrDeadlineExceeded, model.ErrRequestTooLarge, model.ErrInternal:
+		return true
+	default:
+		return false
+	}
+}
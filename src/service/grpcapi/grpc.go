@@ -0,0 +1,166 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ResolverServer is the server API for the Resolver gRPC service.
+//
+// Like storage/grpccas, messages are protobuf well-known wrapper types so this
+// package does not require a protoc/codegen toolchain; each BytesValue holds
+// a JSON-encoded model DTO.
+//
+// Proto definition: resolver.proto.
+type ResolverServer interface {
+	Resolve(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+	ResolveName(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+	VerifyCROF(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+	RenderCROF(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+}
+
+// UnimplementedResolverServer can be embedded to have forward compatible implementations.
+type UnimplementedResolverServer struct{}
+
+func (UnimplementedResolverServer) Resolve(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Error(codes.Unimplemented, "method Resolve not implemented")
+}
+func (UnimplementedResolverServer) ResolveName(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveName not implemented")
+}
+func (UnimplementedResolverServer) VerifyCROF(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyCROF not implemented")
+}
+func (UnimplementedResolverServer) RenderCROF(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenderCROF not implemented")
+}
+
+// RegisterResolverServer registers the Resolver service on a gRPC server.
+func RegisterResolverServer(s grpc.ServiceRegistrar, srv ResolverServer) {
+	s.RegisterService(&Resolver_ServiceDesc, srv)
+}
+
+// ResolverClient is the client API for the Resolver gRPC service.
+type ResolverClient interface {
+	Resolve(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+	ResolveName(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+	VerifyCROF(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+	RenderCROF(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+}
+
+type resolverClient struct{ cc grpc.ClientConnInterface }
+
+func NewResolverClient(cc grpc.ClientConnInterface) ResolverClient { return &resolverClient{cc: cc} }
+
+func (c *resolverClient) Resolve(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := new(wrapperspb.BytesValue)
+	err := c.cc.Invoke(ctx, "/xdao.catf.service.grpcapi.v1.Resolver/Resolve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resolverClient) ResolveName(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := new(wrapperspb.BytesValue)
+	err := c.cc.Invoke(ctx, "/xdao.catf.service.grpcapi.v1.Resolver/ResolveName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resolverClient) VerifyCROF(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := new(wrapperspb.BytesValue)
+	err := c.cc.Invoke(ctx, "/xdao.catf.service.grpcapi.v1.Resolver/VerifyCROF", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *resolverClient) RenderCROF(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := new(wrapperspb.BytesValue)
+	err := c.cc.Invoke(ctx, "/xdao.catf.service.grpcapi.v1.Resolver/RenderCROF", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Resolver_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.BytesValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResolverServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/xdao.catf.service.grpcapi.v1.Resolver/Resolve"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResolverServer).Resolve(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resolver_ResolveName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.BytesValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResolverServer).ResolveName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/xdao.catf.service.grpcapi.v1.Resolver/ResolveName"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResolverServer).ResolveName(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resolver_VerifyCROF_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.BytesValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResolverServer).VerifyCROF(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/xdao.catf.service.grpcapi.v1.Resolver/VerifyCROF"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResolverServer).VerifyCROF(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Resolver_RenderCROF_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.BytesValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResolverServer).RenderCROF(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/xdao.catf.service.grpcapi.v1.Resolver/RenderCROF"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResolverServer).RenderCROF(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Resolver_ServiceDesc is the grpc.ServiceDesc for the Resolver service.
+var Resolver_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xdao.catf.service.grpcapi.v1.Resolver",
+	HandlerType: (*ResolverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Resolve", Handler: _Resolver_Resolve_Handler},
+		{MethodName: "ResolveName", Handler: _Resolver_ResolveName_Handler},
+		{MethodName: "VerifyCROF", Handler: _Resolver_VerifyCROF_Handler},
+		{MethodName: "RenderCROF", Handler: _Resolver_RenderCROF_Handler},
+	},
+	Metadata: "resolver.proto",
+}
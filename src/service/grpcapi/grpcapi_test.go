@@ -0,0 +1,187 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/crof"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/model"
+	"xdao.co/catf/storage"
+)
+
+type mapCAS map[string][]byte
+
+func (m mapCAS) Put(b []byte) (cid.Cid, error) {
+	id, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return cid.Undef, err
+	}
+	m[id.String()] = append([]byte(nil), b...)
+	return id, nil
+}
+
+func (m mapCAS) Get(id cid.Cid) ([]byte, error) {
+	b, ok := m[id.String()]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return b, nil
+}
+
+func (m mapCAS) Has(id cid.Cid) bool {
+	_, ok := m[id.String()]
+	return ok
+}
+
+func newBufconnClient(t *testing.T, srv *Server) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	RegisterResolverServer(gs, srv)
+	go func() {
+		_ = gs.Serve(lis)
+	}()
+	t.Cleanup(gs.Stop)
+
+	dialer := func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }
+	cc, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(dialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return NewClient(cc)
+}
+
+func mustSigner(t *testing.T, b byte) keys.Signer {
+	t.Helper()
+	s, err := keys.NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatalf("signer: %v", err)
+	}
+	return s
+}
+
+func mustAttestation(t *testing.T, subject string, signer keys.Signer) []byte {
+	t.Helper()
+	out, err := catf.Sign(catf.Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": subject, "Description": "grpcapi test"},
+		Claims:  map[string]string{"Role": "author", "Type": "authorship"},
+	}, "sha256", signer)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return out
+}
+
+func policyFor(issuer string) []byte {
+	return []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n" +
+		"\n" +
+		"TRUST\n" +
+		"Key: " + issuer + "\n" +
+		"Role: author\n" +
+		"\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Type: authorship\n" +
+		"  Role: author\n" +
+		"  Quorum: 1\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+}
+
+func TestResolverService_SignedCROFFromCAS(t *testing.T) {
+	author := mustSigner(t, 0x11)
+	cas := mapCAS{}
+	attCID, _ := cas.Put(mustAttestation(t, "bafy-grpc-subject", author))
+	policyCID, _ := cas.Put(policyFor(keys.IssuerKeyForSigner(author)))
+
+	client := newBufconnClient(t, &Server{
+		CAS:         cas,
+		CROFOptions: crof.RenderOptions{ResolverID: "central", Signer: mustSigner(t, 0x22)},
+	})
+	req := model.ResolverRequest{
+		SubjectCID:   "bafy-grpc-subject",
+		Policy:       model.BlobRef{CID: policyCID.String()},
+		Attestations: []model.BlobRef{{CID: attCID.String()}},
+		Compliance:   model.ComplianceStrict,
+	}
+
+	resp, err := client.Resolve(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resp.Resolution.State != "Resolved" {
+		t.Fatalf("expected Resolved, got %s", resp.Resolution.State)
+	}
+
+	doc, err := client.RenderCROF(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RenderCROF: %v", err)
+	}
+	if doc.CID != resp.CROF.CID || !bytes.Equal(doc.Bytes, resp.CROF.Bytes) {
+		t.Fatalf("RenderCROF differs from Resolve CROF")
+	}
+
+	v, err := client.VerifyCROF(context.Background(), model.DocumentRequest{Bytes: doc.Bytes})
+	if err != nil {
+		t.Fatalf("VerifyCROF: %v", err)
+	}
+	if !v.Valid || !v.Signed || v.CID != doc.CID {
+		t.Fatalf("unexpected verify result: %+v", v)
+	}
+}
+
+func TestResolverService_CodedErrors(t *testing.T) {
+	client := newBufconnClient(t, &Server{})
+	_, err := client.Resolve(context.Background(), model.ResolverRequest{
+		SubjectCID: "bafy-grpc-subject",
+		Policy:     model.BlobRef{CID: "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+		Compliance: model.CompliancePermissive,
+	})
+	var ce *model.CodedError
+	if !errors.As(err, &ce) || ce.Code != model.ErrMissingCAS {
+		t.Fatalf("expected MISSING_CAS, got %v", err)
+	}
+
+	_, err = client.ResolveName(context.Background(), model.NameResolverRequest{Compliance: model.CompliancePermissive})
+	if !errors.As(err, &ce) || ce.Code != model.ErrInvalidRequest {
+		t.Fatalf("expected INVALID_REQUEST, got %v", err)
+	}
+
+	// Unknown fields are rejected, as over HTTP.
+	_, err = (&Server{}).VerifyCROF(context.Background(), wrapperspb.Bytes([]byte(`{"bytes":"eA==","unknown":true}`)))
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), `unknown field "unknown"`) {
+		t.Fatalf("expected INVALID_REQUEST for unknown field, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.VerifyCROF(ctx, model.DocumentRequest{Bytes: []byte("x")})
+	if !errors.As(err, &ce) || ce.Code != model.ErrCanceled {
+		t.Fatalf("expected CANCELED, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"xdao.co/catf/crof"
+	"xdao.co/catf/model"
+	"xdao.co/catf/storage"
+)
+
+// Server exposes the model resolver API over the Resolver gRPC service.
+type Server struct {
+	UnimplementedResolverServer
+
+	// CAS hydrates inputs referenced by CID; nil means bytes-only requests.
+	CAS storage.CAS
+	// CROFOptions are applied to every rendered CROF. Set Signer to have the
+	// server sign CROF with its resolver key.
+	CROFOptions crof.RenderOptions
+}
+
+func (s *Server) options() model.ResolveOptions {
+	return model.ResolveOptions{CAS: s.CAS, CROFOptions: s.CROFOptions}
+}
+
+func (s *Server) Resolve(ctx context.Context, in *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	var req model.ResolverRequest
+	if err := decode(in, &req); err != nil {
+		return nil, err
+	}
+	return encode(model.ResolveAndRenderCROFContext(ctx, req, s.options()))
+}
+
+func (s *Server) ResolveName(ctx context.Context, in *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	var req model.NameResolverRequest
+	if err := decode(in, &req); err != nil {
+		return nil, err
+	}
+	return encode(model.ResolveNameAndRenderCROFContext(ctx, req, s.options()))
+}
+
+func (s *Server) VerifyCROF(_ context.Context, in *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	var req model.DocumentRequest
+	if err := decode(in, &req); err != nil {
+		return nil, err
+	}
+	return encode(model.VerifyCROF(req))
+}
+
+func (s *Server) RenderCROF(ctx context.Context, in *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	var req model.ResolverRequest
+	if err := decode(in, &req); err != nil {
+		return nil, err
+	}
+	resp, err := model.ResolveAndRenderCROFContext(ctx, req, s.options())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return encode(&resp.CROF, nil)
+}
+
+func decode(in *wrapperspb.BytesValue, v any) error {
+	if err := model.DecodeRequest(bytes.NewReader(in.GetValue()), v); err != nil {
+		return toStatus(model.NewError(model.ErrInvalidRequest, "invalid JSON: "+err.Error()))
+	}
+	return nil
+}
+
+func encode(v any, err error) (*wrapperspb.BytesValue, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return wrapperspb.Bytes(b), nil
+}
+
+// toStatus converts a model error to a gRPC status whose message is
+// "<CODE>: <message>" (CodedError.Error), so clients can recover the code.
+func toStatus(err error) error {
+	var ce *model.CodedError
+	if !errors.As(err, &ce) {
+		ce = model.NewError(model.ErrInternal, err.Error())
+	}
+	return status.Error(CodeFor(ce.Code), ce.Error())
+}
+
+// CodeFor maps a model.ErrorCode to its gRPC status code.
+func CodeFor(code model.ErrorCode) codes.Code {
+	switch code {
+	case model.ErrInvalidRequest, model.ErrInvalidCID:
+		return codes.InvalidArgument
+	case model.ErrRequestTooLarge:
+		return codes.ResourceExhausted
+	case model.ErrMissingCAS:
+		return codes.FailedPrecondition
+	case model.ErrNotFound:
+		return codes.NotFound
+	case model.ErrCIDMismatch:
+		return codes.DataLoss
+	case model.ErrCanceled:
+		return codes.Canceled
+	case model.ErrDeadlineExceeded:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,202 @@
+// Package httpapi serves the model DTOs over HTTP/JSON.
+//
+// Every endpoint takes a JSON request body via POST and answers with JSON:
+//
+//	POST /v1/resolve       model.ResolverRequest     -> model.ResolverResponse
+//	POST /v1/resolve-name  model.NameResolverRequest -> model.NameResolverResponse
+//	POST /v1/crof/verify   model.DocumentRequest     -> model.CROFVerifyResponse
+//	POST /v1/catf/verify   model.DocumentRequest     -> model.CATFVerifyResponse
+//	POST /v1/cid           model.CIDRequest          -> model.CIDResponse
+//	GET  /healthz          -> {"status":"ok"}
+//
+// Failures are a model.CodedError body with the status from StatusForCode.
+// Request bodies larger than Options.MaxBodyBytes are rejected with
+// REQUEST_TOO_LARGE, and unknown JSON fields are rejected with
+// INVALID_REQUEST.
+//
+// API stability: see STABILITY.md (repository root) for Stable vs Experimental tiers.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"xdao.co/catf/crof"
+	"xdao.co/catf/model"
+	"xdao.co/catf/storage"
+)
+
+// DefaultMaxBodyBytes bounds request bodies when Options.MaxBodyBytes is zero.
+const DefaultMaxBodyBytes = 8 << 20
+
+// Options configures the handler.
+type Options struct {
+	// CAS hydrates inputs referenced by CID. Without it, requests that
+	// reference inputs by CID fail with MISSING_CAS.
+	CAS storage.CAS
+
+	// CROFOptions are applied to every rendered CROF (ResolverID, signing).
+	CROFOptions crof.RenderOptions
+
+	// MaxBodyBytes limits request bodies; zero means DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+
+	// RequestTimeout, when positive, bounds each request (including CAS
+	// hydration).
+	RequestTimeout time.Duration
+}
+
+// NewHandler returns an http.Handler serving the resolver API.
+func NewHandler(opts Options) http.Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	s := &server{opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/v1/resolve", s.resolve)
+	mux.HandleFunc("/v1/resolve-name", s.resolveName)
+	mux.HandleFunc("/v1/crof/verify", s.verifyCROF)
+	mux.HandleFunc("/v1/catf/verify", s.verifyCATF)
+	mux.HandleFunc("/v1/cid", s.cid)
+	return mux
+}
+
+// StatusForCode maps a model.ErrorCode to its HTTP status.
+func StatusForCode(code model.ErrorCode) int {
+	switch code {
+	case model.ErrInvalidRequest, model.ErrInvalidCID:
+		return http.StatusBadRequest
+	case model.ErrMissingCAS:
+		return http.StatusUnprocessableEntity
+	case model.ErrNotFound:
+		return http.StatusNotFound
+	case model.ErrRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case model.ErrCIDMismatch:
+		return http.StatusBadGateway
+	case model.ErrDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case model.ErrCanceled:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type server struct {
+	opts Options
+}
+
+func (s *server) resolveOptions() model.ResolveOptions {
+	return model.ResolveOptions{CAS: s.opts.CAS, CROFOptions: s.opts.CROFOptions}
+}
+
+func (s *server) context(r *http.Request) (context.Context, context.CancelFunc) {
+	if s.opts.RequestTimeout > 0 {
+		return context.WithTimeout(r.Context(), s.opts.RequestTimeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+func (s *server) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, model.NewError(model.ErrInvalidRequest, "method not allowed"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *server) resolve(w http.ResponseWriter, r *http.Request) {
+	var req model.ResolverRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	resp, err := model.ResolveAndRenderCROFContext(ctx, req, s.resolveOptions())
+	respond(w, resp, err)
+}
+
+func (s *server) resolveName(w http.ResponseWriter, r *http.Request) {
+	var req model.NameResolverRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	resp, err := model.ResolveNameAndRenderCROFContext(ctx, req, s.resolveOptions())
+	respond(w, resp, err)
+}
+
+func (s *server) verifyCROF(w http.ResponseWriter, r *http.Request) {
+	var req model.DocumentRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	resp, err := model.VerifyCROF(req)
+	respond(w, resp, err)
+}
+
+func (s *server) verifyCATF(w http.ResponseWriter, r *http.Request) {
+	var req model.DocumentRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	resp, err := model.VerifyCATF(req)
+	respond(w, resp, err)
+}
+
+func (s *server) cid(w http.ResponseWriter, r *http.Request) {
+	var req model.CIDRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	resp, err := model.ComputeCID(req)
+	respond(w, resp, err)
+}
+
+// decode reads a single JSON object from a POST body into v. On failure it
+// writes the error response and returns false.
+func (s *server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, model.NewError(model.ErrInvalidRequest, "method not allowed"))
+		return false
+	}
+	if err := model.DecodeRequest(http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes), v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, model.NewError(model.ErrRequestTooLarge, "request body too large"))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, model.NewError(model.ErrInvalidRequest, "invalid JSON: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func respond(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		var ce *model.CodedError
+		if !errors.As(err, &ce) {
+			ce = model.NewError(model.ErrInternal, err.Error())
+		}
+		writeError(w, StatusForCode(ce.Code), ce)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func writeError(w http.ResponseWriter, status int, ce *model.CodedError) {
+	writeJSON(w, status, ce)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"xdao.co/catf/catf"
+	"xdao.co/catf/keys"
+	"xdao.co/catf/model"
+)
+
+func mustAttestation(t *testing.T, subject string) ([]byte, string) {
+	t.Helper()
+	seed := bytes.Repeat([]byte{0x5a}, ed25519.SeedSize)
+	priv := ed25519.NewKeyFromSeed(seed)
+	issuer := "ed25519:" + base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey))
+	doc := catf.Document{
+		Meta:    map[string]string{"Spec": "xdao-catf-1", "Version": "1"},
+		Subject: map[string]string{"CID": subject, "Description": "httpapi test"},
+		Claims:  map[string]string{"Role": "author", "Type": "authorship"},
+		Crypto: map[string]string{
+			"Hash-Alg":      "sha256",
+			"Issuer-Key":    issuer,
+			"Signature":     "0",
+			"Signature-Alg": "ed25519",
+		},
+	}
+	pre, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	parsed, err := catf.Parse(pre)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	doc.Crypto["Signature"] = keys.SignEd25519SHA256(parsed.SignedBytes(), priv)
+	out, err := catf.Render(doc)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return out, issuer
+}
+
+func policyFor(issuer string) []byte {
+	return []byte("-----BEGIN XDAO TRUST POLICY-----\n" +
+		"META\n" +
+		"Spec: xdao-tpdl-1\n" +
+		"Version: 1\n" +
+		"\n" +
+		"TRUST\n" +
+		"Key: " + issuer + "\n" +
+		"Role: author\n" +
+		"\n" +
+		"RULES\n" +
+		"Require:\n" +
+		"  Type: authorship\n" +
+		"  Role: author\n" +
+		"  Quorum: 1\n" +
+		"-----END XDAO TRUST POLICY-----\n")
+}
+
+func post(t *testing.T, h http.Handler, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var b []byte
+	switch v := body.(type) {
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		b, err = json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b)))
+	return rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) model.CodedError {
+	t.Helper()
+	var ce model.CodedError
+	if err := json.Unmarshal(rec.Body.Bytes(), &ce); err != nil {
+		t.Fatalf("decode error body %q: %v", rec.Body.String(), err)
+	}
+	return ce
+}
+
+func TestHandler_ResolveAndVerify(t *testing.T) {
+	h := NewHandler(Options{})
+	att, issuer := mustAttestation(t, "bafy-http-subject")
+
+	rec := post(t, h, "/v1/resolve", model.ResolverRequest{
+		SubjectCID:   "bafy-http-subject",
+		Policy:       model.BlobRef{Bytes: policyFor(issuer)},
+		Attestations: []model.BlobRef{{Bytes: att}},
+		Compliance:   model.CompliancePermissive,
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resolve: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp model.ResolverResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Resolution.State != "Resolved" || resp.CROF.CID == "" {
+		t.Fatalf("unexpected response: %+v", resp.Resolution)
+	}
+
+	rec = post(t, h, "/v1/crof/verify", model.DocumentRequest{Bytes: resp.CROF.Bytes})
+	var crofResp model.CROFVerifyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &crofResp); err != nil || !crofResp.Valid || crofResp.CID != resp.CROF.CID {
+		t.Fatalf("crof verify: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post(t, h, "/v1/catf/verify", model.DocumentRequest{Bytes: att})
+	var catfResp model.CATFVerifyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &catfResp); err != nil || !catfResp.Valid || catfResp.SignerKeys[0] != issuer {
+		t.Fatalf("catf verify: %d %s", rec.Code, rec.Body.String())
+	}
+	attCID := catfResp.CID
+
+	rec = post(t, h, "/v1/catf/verify", model.DocumentRequest{Bytes: []byte("not catf")})
+	catfResp = model.CATFVerifyResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &catfResp); err != nil || rec.Code != http.StatusOK || catfResp.Valid || catfResp.Error == "" {
+		t.Fatalf("catf verify invalid: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post(t, h, "/v1/cid", model.CIDRequest{Kind: model.CIDKindCATF, Bytes: att})
+	var cidResp model.CIDResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &cidResp); err != nil || cidResp.CID != attCID {
+		t.Fatalf("cid: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := NewHandler(Options{MaxBodyBytes: 64})
+
+	rec := post(t, h, "/v1/resolve", model.ResolverRequest{
+		SubjectCID: "bafy-http-subject",
+		Policy:     model.BlobRef{CID: "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+		Compliance: model.CompliancePermissive,
+	})
+	if rec.Code != http.StatusRequestEntityTooLarge || decodeError(t, rec).Code != model.ErrRequestTooLarge {
+		t.Fatalf("expected 413, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	h = NewHandler(Options{})
+	rec = post(t, h, "/v1/resolve", model.ResolverRequest{
+		SubjectCID: "bafy-http-subject",
+		Policy:     model.BlobRef{CID: "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+		Compliance: model.CompliancePermissive,
+	})
+	if rec.Code != http.StatusUnprocessableEntity || decodeError(t, rec).Code != model.ErrMissingCAS {
+		t.Fatalf("expected MISSING_CAS, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post(t, h, "/v1/cid", `{"kind":"raw","bytes":"","extra":1}`)
+	if rec.Code != http.StatusBadRequest || decodeError(t, rec).Code != model.ErrInvalidRequest {
+		t.Fatalf("expected 400 for unknown field, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/resolve", strings.NewReader("")))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
@@ -3,6 +3,7 @@ package bundle
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,6 +35,15 @@ func Export(w io.Writer, cas storage.CAS, ids []cid.Cid, opts ExportOptions) err
 	if cas == nil {
 		return fmt.Errorf("bundle: nil CAS")
 	}
+	return ExportContext(context.Background(), w, storage.AsContextCAS(cas), ids, opts)
+}
+
+// ExportContext is Export with cancellation. It stops before the next block
+// once ctx is done; w may then hold a truncated bundle.
+func ExportContext(ctx context.Context, w io.Writer, cas storage.ContextCAS, ids []cid.Cid, opts ExportOptions) error {
+	if cas == nil {
+		return fmt.Errorf("bundle: nil CAS")
+	}
 
 	uniq := make(map[string]cid.Cid, len(ids))
 	for _, id := range ids {
@@ -54,7 +64,11 @@ func Export(w io.Writer, cas storage.CAS, ids []cid.Cid, opts ExportOptions) err
 	blocks := make([]indexBlock, 0, len(cidStrings))
 	for _, s := range cidStrings {
 		id := uniq[s]
-		b, err := cas.Get(id)
+		if err := ctx.Err(); err != nil {
+			_ = tw.Close()
+			return err
+		}
+		b, err := cas.GetContext(ctx, id)
 		if err != nil {
 			_ = tw.Close()
 			return err
@@ -153,6 +167,15 @@ func Import(r io.Reader, cas storage.CAS) error {
 //
 // It validates that each block's bytes match both the filename CID and the computed CID.
 func ImportWithOptions(r io.Reader, cas storage.CAS, opts ImportOptions) error {
+	if cas == nil {
+		return fmt.Errorf("bundle: nil CAS")
+	}
+	return ImportContext(context.Background(), r, storage.AsContextCAS(cas), opts)
+}
+
+// ImportContext is ImportWithOptions with cancellation. It stops before the
+// next entry once ctx is done; blocks already written remain in cas.
+func ImportContext(ctx context.Context, r io.Reader, cas storage.ContextCAS, opts ImportOptions) error {
 	if cas == nil {
 		return fmt.Errorf("bundle: nil CAS")
 	}
@@ -164,6 +187,9 @@ func ImportWithOptions(r io.Reader, cas storage.CAS, opts ImportOptions) error {
 	seen := map[string]struct{}{}
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		h, err := tr.Next()
 		if err == io.EOF {
 			return nil
@@ -221,7 +247,7 @@ func ImportWithOptions(r io.Reader, cas storage.CAS, opts ImportOptions) error {
 		}
 		seen[key] = struct{}{}
 
-		putID, perr := cas.Put(payload)
+		putID, perr := cas.PutContext(ctx, payload)
 		if perr != nil {
 			return perr
 		}
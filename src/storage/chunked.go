@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+
+	"xdao.co/catf/cidutil"
+)
+
+// ChunkSize is the fixed chunk size of the chunked blob layout.
+//
+// It is part of the layout: changing it changes every chunked root CID.
+const ChunkSize = 1 << 20
+
+// ChunkedFormat identifies the chunked blob manifest format.
+const ChunkedFormat = "xdao-chunked-1"
+
+// ErrInvalidManifest reports a chunked root whose manifest is malformed or
+// inconsistent with its chunks.
+var ErrInvalidManifest = errors.New("storage: invalid chunked manifest")
+
+// chunkManifest is the canonical JSON manifest of a chunked blob.
+//
+// Field order is lexicographic so encoding/json emits canonical bytes.
+type chunkManifest struct {
+	ChunkSize int      `json:"chunkSize"`
+	Chunks    []string `json:"chunks"`
+	Format    string   `json:"format"`
+	Size      int64    `json:"size"`
+}
+
+// StreamCAS is implemented by CAS backends that move blobs as streams
+// instead of whole byte slices. Roots follow the chunked layout (see
+// ChunkedCID).
+type StreamCAS interface {
+	PutReader(ctx context.Context, r io.Reader) (cid.Cid, error)
+	GetReader(ctx context.Context, id cid.Cid) (io.ReadCloser, error)
+}
+
+// ChunkedCID derives the chunked root CID of the bytes read from r without
+// storing them.
+//
+// Blobs of at most ChunkSize bytes have their plain CIDv1(raw, sha2-256), so
+// ChunkedCID agrees with cidutil.CIDv1RawSHA256CID for them. Larger blobs are
+// split into ChunkSize chunks (the last may be shorter), each stored as a raw
+// block, and listed in a canonical JSON manifest; the root is
+// CIDv1(dag-json, sha2-256(manifest)). See chunked_format.md.
+func ChunkedCID(r io.Reader) (cid.Cid, error) {
+	var h ChunkedHasher
+	if _, err := io.Copy(&h, r); err != nil {
+		return cid.Undef, err
+	}
+	return h.Root()
+}
+
+// ChunkedHasher incrementally derives the chunked root CID of the bytes
+// written to it, e.g. to verify a stream as it is consumed.
+type ChunkedHasher struct {
+	w chunkWriter
+}
+
+func (h *ChunkedHasher) Write(p []byte) (int, error) { return h.w.Write(p) }
+
+// Root returns the chunked root CID of everything written so far. It must be
+// called at most once.
+func (h *ChunkedHasher) Root() (cid.Cid, error) { return h.w.finish() }
+
+// PutReader stores the bytes read from r in cas using the chunked layout and
+// returns the root CID. Backends implementing StreamCAS handle the stream
+// themselves; others receive one Put per chunk plus one for the manifest.
+func PutReader(ctx context.Context, cas CAS, r io.Reader) (cid.Cid, error) {
+	if cas == nil {
+		return cid.Undef, errors.New("storage: nil CAS")
+	}
+	if s, ok := cas.(StreamCAS); ok {
+		return s.PutReader(ctx, r)
+	}
+	cc := AsContextCAS(cas)
+	w := &chunkWriter{emit: func(b []byte, id cid.Cid) error {
+		got, err := cc.PutContext(ctx, b)
+		if err != nil {
+			return err
+		}
+		if got != id {
+			return ErrCIDMismatch
+		}
+		return nil
+	}}
+	if _, err := io.Copy(w, r); err != nil {
+		return cid.Undef, err
+	}
+	return w.finish()
+}
+
+// GetReader returns a reader over the blob rooted at id. Every block is
+// verified against its CID before its bytes are returned; a chunked blob is
+// fetched one chunk at a time.
+func GetReader(ctx context.Context, cas CAS, id cid.Cid) (io.ReadCloser, error) {
+	if cas == nil {
+		return nil, errors.New("storage: nil CAS")
+	}
+	if s, ok := cas.(StreamCAS); ok {
+		return s.GetReader(ctx, id)
+	}
+	cc := AsContextCAS(cas)
+	switch {
+	case !id.Defined():
+		return nil, ErrInvalidCID
+	case id.Type() == cid.Raw:
+		b, err := getVerified(ctx, cc, id)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(b)), nil
+	case id.Type() == cid.DagJSON:
+		m, err := getManifest(ctx, cc, id)
+		if err != nil {
+			return nil, err
+		}
+		return &chunkReader{ctx: ctx, cas: cc, m: m}, nil
+	default:
+		return nil, ErrInvalidCID
+	}
+}
+
+func getVerified(ctx context.Context, cas ContextCAS, id cid.Cid) ([]byte, error) {
+	b, err := cas.GetContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	got, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return nil, err
+	}
+	if got != id {
+		return nil, ErrCIDMismatch
+	}
+	return b, nil
+}
+
+func getManifest(ctx context.Context, cas ContextCAS, root cid.Cid) (*chunkManifest, error) {
+	dec, err := multihash.Decode(root.Hash())
+	if err != nil || dec.Code != multihash.SHA2_256 {
+		return nil, ErrInvalidCID
+	}
+	b, err := getVerified(ctx, cas, cid.NewCidV1(cid.Raw, root.Hash()))
+	if err != nil {
+		return nil, err
+	}
+	return parseManifest(b)
+}
+
+func parseManifest(b []byte) (*chunkManifest, error) {
+	var m chunkManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	canon, err := json.Marshal(m)
+	if err != nil || !bytes.Equal(canon, b) {
+		return nil, fmt.Errorf("%w: not canonical", ErrInvalidManifest)
+	}
+	if m.Format != ChunkedFormat || m.ChunkSize != ChunkSize {
+		return nil, fmt.Errorf("%w: unsupported format", ErrInvalidManifest)
+	}
+	want := (m.Size + ChunkSize - 1) / ChunkSize
+	if m.Size <= ChunkSize || int64(len(m.Chunks)) != want {
+		return nil, fmt.Errorf("%w: size does not match chunk count", ErrInvalidManifest)
+	}
+	for _, s := range m.Chunks {
+		id, err := cid.Decode(s)
+		if err != nil || id.Type() != cid.Raw || id.String() != s {
+			return nil, fmt.Errorf("%w: invalid chunk CID", ErrInvalidManifest)
+		}
+	}
+	return &m, nil
+}
+
+// chunkWriter splits written bytes into ChunkSize chunks and derives the root.
+// emit, when set, receives every block (chunks, then the manifest) in order.
+type chunkWriter struct {
+	emit   func(b []byte, id cid.Cid) error
+	buf    []byte
+	chunks []string
+	size   int64
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	w.size += int64(len(p))
+	// A chunk is only cut once more bytes follow it, so a blob of exactly
+	// ChunkSize bytes stays a single raw block. Emitted chunks are never
+	// reused, since a CAS may retain the slice.
+	for len(w.buf) > ChunkSize {
+		chunk := w.buf[:ChunkSize:ChunkSize]
+		w.buf = append([]byte(nil), w.buf[ChunkSize:]...)
+		if err := w.cut(chunk); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *chunkWriter) cut(b []byte) error {
+	id, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return err
+	}
+	if w.emit != nil {
+		if err := w.emit(b, id); err != nil {
+			return err
+		}
+	}
+	w.chunks = append(w.chunks, id.String())
+	return nil
+}
+
+func (w *chunkWriter) finish() (cid.Cid, error) {
+	if len(w.chunks) == 0 {
+		id, err := cidutil.CIDv1RawSHA256CID(w.buf)
+		if err != nil {
+			return cid.Undef, err
+		}
+		if w.emit != nil {
+			if err := w.emit(w.buf, id); err != nil {
+				return cid.Undef, err
+			}
+		}
+		return id, nil
+	}
+	if len(w.buf) > 0 {
+		if err := w.cut(w.buf); err != nil {
+			return cid.Undef, err
+		}
+	}
+	mb, err := json.Marshal(chunkManifest{ChunkSize: ChunkSize, Chunks: w.chunks, Format: ChunkedFormat, Size: w.size})
+	if err != nil {
+		return cid.Undef, err
+	}
+	raw, err := cidutil.CIDv1RawSHA256CID(mb)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if w.emit != nil {
+		if err := w.emit(mb, raw); err != nil {
+			return cid.Undef, err
+		}
+	}
+	return cid.NewCidV1(cid.DagJSON, raw.Hash()), nil
+}
+
+// chunkReader streams a chunked blob, fetching and verifying one chunk at a time.
+type chunkReader struct {
+	ctx  context.Context
+	cas  ContextCAS
+	m    *chunkManifest
+	next int
+	cur  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.cur) == 0 {
+		if r.next == len(r.m.Chunks) {
+			return 0, io.EOF
+		}
+		id, _ := cid.Decode(r.m.Chunks[r.next])
+		b, err := getVerified(r.ctx, r.cas, id)
+		if err != nil {
+			return 0, err
+		}
+		want := int64(ChunkSize)
+		if r.next == len(r.m.Chunks)-1 {
+			want = r.m.Size - int64(r.next)*ChunkSize
+		}
+		if int64(len(b)) != want {
+			return 0, fmt.Errorf("%w: chunk %d has %d bytes, want %d", ErrInvalidManifest, r.next, len(b), want)
+		}
+		r.cur = b
+		r.next++
+	}
+	n := copy(p, r.cur)
+	r.cur = r.cur[n:]
+	return n, nil
+}
+
+func (r *chunkReader) Close() error {
+	r.cur = nil
+	r.next = len(r.m.Chunks)
+	return nil
+}
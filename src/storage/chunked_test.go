@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/cidutil"
+)
+
+func patterned(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i*7 + i/251)
+	}
+	return b
+}
+
+func TestChunked_SmallBlobKeepsRawCID(t *testing.T) {
+	for _, n := range []int{0, 1, ChunkSize} {
+		data := patterned(n)
+		cas := mapCAS{}
+		root, err := PutReader(context.Background(), cas, bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("PutReader(%d): %v", n, err)
+		}
+		want, _ := cidutil.CIDv1RawSHA256CID(data)
+		if root != want {
+			t.Fatalf("size %d: root %s, want raw CID %s", n, root, want)
+		}
+		if len(cas) != 1 {
+			t.Fatalf("size %d: expected a single block, got %d", n, len(cas))
+		}
+	}
+}
+
+func TestChunked_LargeBlobRoundTrip(t *testing.T) {
+	data := patterned(2*ChunkSize + 7)
+	cas := mapCAS{}
+	root, err := PutReader(context.Background(), cas, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PutReader: %v", err)
+	}
+	if root.Type() != cid.DagJSON {
+		t.Fatalf("expected dag-json root, got codec %x", root.Type())
+	}
+	// Three chunks plus the manifest.
+	if len(cas) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(cas))
+	}
+	hashed, err := ChunkedCID(bytes.NewReader(data))
+	if err != nil || hashed != root {
+		t.Fatalf("ChunkedCID = %s (%v), want %s", hashed, err, root)
+	}
+
+	manifest := cas[cid.NewCidV1(cid.Raw, root.Hash()).String()]
+	if !bytes.HasPrefix(manifest, []byte(`{"chunkSize":1048576,"chunks":["`)) || !bytes.HasSuffix(manifest, []byte(`"],"format":"xdao-chunked-1","size":2097159}`)) {
+		t.Fatalf("unexpected manifest: %s", manifest)
+	}
+
+	rc, err := GetReader(context.Background(), cas, root)
+	if err != nil {
+		t.Fatalf("GetReader: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("round trip mismatch (err=%v, %d bytes)", err, len(got))
+	}
+}
+
+func TestChunked_GetReaderRejectsTamperedChunk(t *testing.T) {
+	data := patterned(ChunkSize + 1)
+	cas := mapCAS{}
+	root, err := PutReader(context.Background(), cas, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PutReader: %v", err)
+	}
+	first, _ := cidutil.CIDv1RawSHA256CID(data[:ChunkSize])
+	cas[first.String()] = []byte("tampered")
+
+	rc, err := GetReader(context.Background(), cas, root)
+	if err != nil {
+		t.Fatalf("GetReader: %v", err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("expected ErrCIDMismatch, got %v", err)
+	}
+
+	bad, _ := cidutil.CIDv1RawSHA256CID([]byte(`{"chunkSize":1,"chunks":[],"format":"xdao-chunked-1","size":0}`))
+	cas[bad.String()] = []byte(`{"chunkSize":1,"chunks":[],"format":"xdao-chunked-1","size":0}`)
+	if _, err := GetReader(context.Background(), cas, cid.NewCidV1(cid.DagJSON, bad.Hash())); !errors.Is(err, ErrInvalidManifest) {
+		t.Fatalf("expected ErrInvalidManifest, got %v", err)
+	}
+}
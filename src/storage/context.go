@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ContextCAS is the context-aware counterpart of CAS.
+//
+// It carries the same contract as CAS, plus:
+// - Implementations SHOULD abandon work and return ctx.Err() once ctx is done.
+// - HasContext MUST report lookup failures as errors rather than as false.
+//
+// The method names differ from CAS so a single type can implement both.
+type ContextCAS interface {
+	PutContext(ctx context.Context, bytes []byte) (cid.Cid, error)
+	GetContext(ctx context.Context, id cid.Cid) ([]byte, error)
+	HasContext(ctx context.Context, id cid.Cid) (bool, error)
+}
+
+// AsContextCAS adapts a CAS to ContextCAS.
+//
+// If c already implements ContextCAS it is returned as-is. Otherwise the
+// adapter checks ctx before and after each call; it cannot interrupt a call
+// that is already blocked inside c.
+func AsContextCAS(c CAS) ContextCAS {
+	if c == nil {
+		return nil
+	}
+	if cc, ok := c.(ContextCAS); ok {
+		return cc
+	}
+	return contextAdapter{CAS: c}
+}
+
+// AsCAS adapts a ContextCAS to CAS, using context.Background for every call.
+//
+// If c already implements CAS it is returned as-is. The adapter's Has returns
+// false when HasContext fails; AsContextCAS on the adapter recovers c.
+func AsCAS(c ContextCAS) CAS {
+	if c == nil {
+		return nil
+	}
+	if legacy, ok := c.(CAS); ok {
+		return legacy
+	}
+	return legacyAdapter{ContextCAS: c}
+}
+
+// contextAdapter embeds the wrapped CAS so AsCAS can unwrap it.
+type contextAdapter struct {
+	CAS
+}
+
+func (a contextAdapter) PutContext(ctx context.Context, bytes []byte) (cid.Cid, error) {
+	if err := ctx.Err(); err != nil {
+		return cid.Undef, err
+	}
+	id, err := a.CAS.Put(bytes)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if err := ctx.Err(); err != nil {
+		return cid.Undef, err
+	}
+	return id, nil
+}
+
+func (a contextAdapter) GetContext(ctx context.Context, id cid.Cid) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b, err := a.CAS.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (a contextAdapter) HasContext(ctx context.Context, id cid.Cid) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	ok := a.CAS.Has(id)
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// legacyAdapter embeds the wrapped ContextCAS so AsContextCAS keeps its
+// context handling and Has errors.
+type legacyAdapter struct {
+	ContextCAS
+}
+
+func (a legacyAdapter) Put(bytes []byte) (cid.Cid, error) {
+	return a.ContextCAS.PutContext(context.Background(), bytes)
+}
+
+func (a legacyAdapter) Get(id cid.Cid) ([]byte, error) {
+	return a.ContextCAS.GetContext(context.Background(), id)
+}
+
+func (a legacyAdapter) Has(id cid.Cid) bool {
+	ok, err := a.ContextCAS.HasContext(context.Background(), id)
+	return err == nil && ok
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/cidutil"
+)
+
+type mapCAS map[string][]byte
+
+func (m mapCAS) Put(b []byte) (cid.Cid, error) {
+	id, err := cidutil.CIDv1RawSHA256CID(b)
+	if err != nil {
+		return cid.Undef, err
+	}
+	m[id.String()] = append([]byte(nil), b...)
+	return id, nil
+}
+
+func (m mapCAS) Get(id cid.Cid) ([]byte, error) {
+	b, ok := m[id.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return b, nil
+}
+
+func (m mapCAS) Has(id cid.Cid) bool {
+	_, ok := m[id.String()]
+	return ok
+}
+
+// failingCAS is a ContextCAS whose lookups always fail.
+type failingCAS struct{ err error }
+
+func (f failingCAS) PutContext(context.Context, []byte) (cid.Cid, error) { return cid.Undef, f.err }
+func (f failingCAS) GetContext(context.Context, cid.Cid) ([]byte, error) { return nil, f.err }
+func (f failingCAS) HasContext(context.Context, cid.Cid) (bool, error)   { return false, f.err }
+
+func TestAsContextCAS_HonorsCanceledContext(t *testing.T) {
+	cas := AsContextCAS(mapCAS{})
+	id, err := cas.PutContext(context.Background(), []byte("payload"))
+	if err != nil {
+		t.Fatalf("PutContext: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cas.GetContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContext: got %v want Canceled", err)
+	}
+	if _, err := cas.HasContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Fatalf("HasContext: got %v want Canceled", err)
+	}
+
+	// Round-tripping through AsCAS unwraps to the original behavior.
+	if !AsCAS(cas).Has(id) {
+		t.Fatalf("AsCAS(...).Has: expected true")
+	}
+}
+
+func TestMultiCAS_HasContextReportsAdapterErrors(t *testing.T) {
+	present := mapCAS{}
+	id, err := present.Put([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	boom := errors.New("backend unavailable")
+	failing := AsCAS(failingCAS{err: boom})
+
+	ok, err := MultiCAS{Adapters: []CAS{failing, present}}.HasContext(context.Background(), id)
+	if err != nil || !ok {
+		t.Fatalf("expected fallback to find CID, got ok=%v err=%v", ok, err)
+	}
+	ok, err = MultiCAS{Adapters: []CAS{failing, mapCAS{}}}.HasContext(context.Background(), id)
+	if ok || !errors.Is(err, boom) {
+		t.Fatalf("expected adapter error, got ok=%v err=%v", ok, err)
+	}
+	if (MultiCAS{Adapters: []CAS{failing}}).Has(id) {
+		t.Fatalf("Has: expected false on error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (MultiCAS{Adapters: []CAS{present}}).GetContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetContext: got %v want Canceled", err)
+	}
+}
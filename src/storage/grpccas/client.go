@@ -64,7 +64,27 @@ func (c *Client) Close() error {
 	return c.cc.Close()
 }
 
+var (
+	_ storage.CAS        = (*Client)(nil)
+	_ storage.ContextCAS = (*Client)(nil)
+)
+
 func (c *Client) Put(data []byte) (cid.Cid, error) {
+	return c.PutContext(context.Background(), data)
+}
+
+func (c *Client) Get(id cid.Cid) ([]byte, error) {
+	return c.GetContext(context.Background(), id)
+}
+
+// Has reports false when the RPC fails; use HasContext to observe the error.
+func (c *Client) Has(id cid.Cid) bool {
+	ok, err := c.HasContext(context.Background(), id)
+	return err == nil && ok
+}
+
+// PutContext stores data; ctx bounds the RPC in addition to Timeout.
+func (c *Client) PutContext(ctx context.Context, data []byte) (cid.Cid, error) {
 	if c == nil || c.client == nil {
 		return cid.Undef, storage.ErrNotFound
 	}
@@ -73,7 +93,7 @@ func (c *Client) Put(data []byte) (cid.Cid, error) {
 		return cid.Undef, err
 	}
 
-	ctx, cancel := c.ctx()
+	ctx, cancel := c.ctx(ctx)
 	defer cancel()
 
 	reply, err := c.client.Put(ctx, wrapperspb.Bytes(data))
@@ -90,11 +110,15 @@ func (c *Client) Put(data []byte) (cid.Cid, error) {
 	return id, nil
 }
 
-func (c *Client) Get(id cid.Cid) ([]byte, error) {
+// GetContext fetches and verifies the bytes for id; ctx bounds the RPC in addition to Timeout.
+func (c *Client) GetContext(ctx context.Context, id cid.Cid) ([]byte, error) {
 	if !id.Defined() {
 		return nil, storage.ErrInvalidCID
 	}
-	ctx, cancel := c.ctx()
+	if c == nil || c.client == nil {
+		return nil, storage.ErrNotFound
+	}
+	ctx, cancel := c.ctx(ctx)
 	defer cancel()
 
 	reply, err := c.client.Get(ctx, wrapperspb.String(id.String()))
@@ -112,23 +136,28 @@ func (c *Client) Get(id cid.Cid) ([]byte, error) {
 	return b, nil
 }
 
-func (c *Client) Has(id cid.Cid) bool {
+// HasContext reports whether the server has id. RPC failures (including
+// cancellation and deadline expiry) are returned as errors.
+func (c *Client) HasContext(ctx context.Context, id cid.Cid) (bool, error) {
 	if !id.Defined() {
-		return false
+		return false, nil
+	}
+	if c == nil || c.client == nil {
+		return false, storage.ErrNotFound
 	}
-	ctx, cancel := c.ctx()
+	ctx, cancel := c.ctx(ctx)
 	defer cancel()
 
 	reply, err := c.client.Has(ctx, wrapperspb.String(id.String()))
 	if err != nil {
-		return false
+		return false, mapRPC(err)
 	}
-	return reply.GetValue()
+	return reply.GetValue(), nil
 }
 
-func (c *Client) ctx() (context.Context, context.CancelFunc) {
+func (c *Client) ctx(parent context.Context) (context.Context, context.CancelFunc) {
 	if c.Timeout <= 0 {
-		return context.WithCancel(context.Background())
+		return context.WithCancel(parent)
 	}
-	return context.WithTimeout(context.Background(), c.Timeout)
+	return context.WithTimeout(parent, c.Timeout)
 }
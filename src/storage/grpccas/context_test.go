@@ -0,0 +1,96 @@
+package grpccas
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"xdao.co/catf/cidutil"
+	"xdao.co/catf/storage"
+)
+
+// hungCAS blocks every call until the request context is done, and fails Has.
+type hungCAS struct{ *memCAS }
+
+func (h hungCAS) PutContext(ctx context.Context, b []byte) (cid.Cid, error) {
+	<-ctx.Done()
+	return cid.Undef, ctx.Err()
+}
+
+func (h hungCAS) GetContext(ctx context.Context, id cid.Cid) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (h hungCAS) HasContext(ctx context.Context, id cid.Cid) (bool, error) {
+	return false, errors.New("backend unavailable")
+}
+
+func newBufconnClient(t *testing.T, cas storage.CAS) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterCASServer(srv, &Server{CAS: cas})
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	dialer := func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }
+	cc, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(dialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return &Client{cc: cc, client: NewCASClient(cc)}
+}
+
+func TestGRPCCAS_ContextDeadlineUnblocksHungBackend(t *testing.T) {
+	client := newBufconnClient(t, hungCAS{newMemCAS()})
+	id, err := cidutil.CIDv1RawSHA256CID([]byte("never returned"))
+	if err != nil {
+		t.Fatalf("CIDv1RawSHA256CID: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetContext(ctx, id); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetContext: got %v want DeadlineExceeded", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("GetContext did not honor the deadline")
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := client.PutContext(canceled, []byte("x")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PutContext: got %v want Canceled", err)
+	}
+}
+
+func TestGRPCCAS_HasContextReportsErrors(t *testing.T) {
+	client := newBufconnClient(t, hungCAS{newMemCAS()})
+	id, err := cidutil.CIDv1RawSHA256CID([]byte("probe"))
+	if err != nil {
+		t.Fatalf("CIDv1RawSHA256CID: %v", err)
+	}
+	if ok, err := client.HasContext(context.Background(), id); err == nil || ok {
+		t.Fatalf("HasContext: expected error, got ok=%v err=%v", ok, err)
+	}
+	if client.Has(id) {
+		t.Fatalf("Has: expected false on error")
+	}
+}
@@ -1,6 +1,8 @@
 package grpccas
 
 import (
+	"context"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -17,6 +19,10 @@ func mapRPC(err error) error {
 	}
 
 	switch st.Code() {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
 	case codes.NotFound:
 		return storage.ErrNotFound
 	case codes.InvalidArgument:
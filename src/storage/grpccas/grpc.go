@@ -19,6 +19,8 @@ type CASServer interface {
 	Put(context.Context, *wrapperspb.BytesValue) (*wrapperspb.StringValue, error)
 	Get(context.Context, *wrapperspb.StringValue) (*wrapperspb.BytesValue, error)
 	Has(context.Context, *wrapperspb.StringValue) (*wrapperspb.BoolValue, error)
+	PutStream(CAS_PutStreamServer) error
+	GetStream(*wrapperspb.StringValue, CAS_GetStreamServer) error
 }
 
 // UnimplementedCASServer can be embedded to have forward compatible implementations.
@@ -33,6 +35,12 @@ func (UnimplementedCASServer) Get(context.Context, *wrapperspb.StringValue) (*wr
 func (UnimplementedCASServer) Has(context.Context, *wrapperspb.StringValue) (*wrapperspb.BoolValue, error) {
 	return nil, status.Error(codes.Unimplemented, "method Has not implemented")
 }
+func (UnimplementedCASServer) PutStream(CAS_PutStreamServer) error {
+	return status.Error(codes.Unimplemented, "method PutStream not implemented")
+}
+func (UnimplementedCASServer) GetStream(*wrapperspb.StringValue, CAS_GetStreamServer) error {
+	return status.Error(codes.Unimplemented, "method GetStream not implemented")
+}
 
 // RegisterCASServer registers the CAS service on a gRPC server.
 func RegisterCASServer(s grpc.ServiceRegistrar, srv CASServer) {
@@ -44,6 +52,8 @@ type CASClient interface {
 	Put(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.StringValue, error)
 	Get(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
 	Has(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.BoolValue, error)
+	PutStream(ctx context.Context, opts ...grpc.CallOption) (CAS_PutStreamClient, error)
+	GetStream(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (CAS_GetStreamClient, error)
 }
 
 type casClient struct{ cc grpc.ClientConnInterface }
@@ -77,6 +87,69 @@ func (c *casClient) Has(ctx context.Context, in *wrapperspb.StringValue, opts ..
 	return out, nil
 }
 
+func (c *casClient) PutStream(ctx context.Context, opts ...grpc.CallOption) (CAS_PutStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CAS_ServiceDesc.Streams[0], "/xdao.catf.storage.grpccas.v1.CAS/PutStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &casPutStreamClient{stream}, nil
+}
+
+// CAS_PutStreamClient is the client side of PutStream.
+type CAS_PutStreamClient interface {
+	Send(*wrapperspb.BytesValue) error
+	CloseAndRecv() (*wrapperspb.StringValue, error)
+	grpc.ClientStream
+}
+
+type casPutStreamClient struct{ grpc.ClientStream }
+
+func (x *casPutStreamClient) Send(m *wrapperspb.BytesValue) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *casPutStreamClient) CloseAndRecv() (*wrapperspb.StringValue, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(wrapperspb.StringValue)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *casClient) GetStream(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (CAS_GetStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CAS_ServiceDesc.Streams[1], "/xdao.catf.storage.grpccas.v1.CAS/GetStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &casGetStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// CAS_GetStreamClient is the client side of GetStream.
+type CAS_GetStreamClient interface {
+	Recv() (*wrapperspb.BytesValue, error)
+	grpc.ClientStream
+}
+
+type casGetStreamClient struct{ grpc.ClientStream }
+
+func (x *casGetStreamClient) Recv() (*wrapperspb.BytesValue, error) {
+	m := new(wrapperspb.BytesValue)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CAS_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(wrapperspb.BytesValue)
 	if err := dec(in); err != nil {
@@ -122,6 +195,51 @@ func _CAS_Has_Handler(srv interface{}, ctx context.Context, dec func(interface{}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CAS_PutStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CASServer).PutStream(&casPutStreamServer{stream})
+}
+
+// CAS_PutStreamServer is the server side of PutStream.
+type CAS_PutStreamServer interface {
+	SendAndClose(*wrapperspb.StringValue) error
+	Recv() (*wrapperspb.BytesValue, error)
+	grpc.ServerStream
+}
+
+type casPutStreamServer struct{ grpc.ServerStream }
+
+func (x *casPutStreamServer) SendAndClose(m *wrapperspb.StringValue) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *casPutStreamServer) Recv() (*wrapperspb.BytesValue, error) {
+	m := new(wrapperspb.BytesValue)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CAS_GetStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(wrapperspb.StringValue)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CASServer).GetStream(m, &casGetStreamServer{stream})
+}
+
+// CAS_GetStreamServer is the server side of GetStream.
+type CAS_GetStreamServer interface {
+	Send(*wrapperspb.BytesValue) error
+	grpc.ServerStream
+}
+
+type casGetStreamServer struct{ grpc.ServerStream }
+
+func (x *casGetStreamServer) Send(m *wrapperspb.BytesValue) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // CAS_ServiceDesc is the grpc.ServiceDesc for CAS service.
 var CAS_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "xdao.catf.storage.grpccas.v1.CAS",
@@ -131,6 +249,9 @@ var CAS_ServiceDesc = grpc.ServiceDesc{
 		{MethodName: "Get", Handler: _CAS_Get_Handler},
 		{MethodName: "Has", Handler: _CAS_Has_Handler},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "PutStream", Handler: _CAS_PutStream_Handler, ClientStreams: true},
+		{StreamName: "GetStream", Handler: _CAS_GetStream_Handler, ServerStreams: true},
+	},
 	Metadata: "cas.proto",
 }
@@ -2,6 +2,7 @@ package grpccas
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ipfs/go-cid"
 	"google.golang.org/grpc/codes"
@@ -13,13 +14,15 @@ import (
 )
 
 // Server exposes a storage.CAS over the CAS gRPC service.
+//
+// If CAS also implements storage.ContextCAS, the RPC context is passed through
+// and Has failures are returned as RPC errors.
 type Server struct {
 	UnimplementedCASServer
 	CAS storage.CAS
 }
 
 func (s *Server) Put(ctx context.Context, in *wrapperspb.BytesValue) (*wrapperspb.StringValue, error) {
-	_ = ctx
 	if s == nil || s.CAS == nil {
 		return nil, status.Error(codes.FailedPrecondition, "missing CAS")
 	}
@@ -29,7 +32,7 @@ func (s *Server) Put(ctx context.Context, in *wrapperspb.BytesValue) (*wrappersp
 	if err != nil {
 		return nil, status.Error(codes.Internal, "cid computation failed")
 	}
-	id, err := s.CAS.Put(b)
+	id, err := storage.AsContextCAS(s.CAS).PutContext(ctx, b)
 	if err != nil {
 		return nil, mapErr(err)
 	}
@@ -40,7 +43,6 @@ func (s *Server) Put(ctx context.Context, in *wrapperspb.BytesValue) (*wrappersp
 }
 
 func (s *Server) Get(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.BytesValue, error) {
-	_ = ctx
 	if s == nil || s.CAS == nil {
 		return nil, status.Error(codes.FailedPrecondition, "missing CAS")
 	}
@@ -48,7 +50,7 @@ func (s *Server) Get(ctx context.Context, in *wrapperspb.StringValue) (*wrappers
 	if err != nil || !id.Defined() {
 		return nil, status.Error(codes.InvalidArgument, storage.ErrInvalidCID.Error())
 	}
-	b, err := s.CAS.Get(id)
+	b, err := storage.AsContextCAS(s.CAS).GetContext(ctx, id)
 	if err != nil {
 		return nil, mapErr(err)
 	}
@@ -63,7 +65,6 @@ func (s *Server) Get(ctx context.Context, in *wrapperspb.StringValue) (*wrappers
 }
 
 func (s *Server) Has(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.BoolValue, error) {
-	_ = ctx
 	if s == nil || s.CAS == nil {
 		return nil, status.Error(codes.FailedPrecondition, "missing CAS")
 	}
@@ -71,7 +72,11 @@ func (s *Server) Has(ctx context.Context, in *wrapperspb.StringValue) (*wrappers
 	if err != nil || !id.Defined() {
 		return nil, status.Error(codes.InvalidArgument, storage.ErrInvalidCID.Error())
 	}
-	return wrapperspb.Bool(s.CAS.Has(id)), nil
+	ok, err := storage.AsContextCAS(s.CAS).HasContext(ctx, id)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return wrapperspb.Bool(ok), nil
 }
 
 func mapErr(err error) error {
@@ -79,6 +84,10 @@ func mapErr(err error) error {
 		return nil
 	}
 	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
 	case err == storage.ErrNotFound:
 		return status.Error(codes.NotFound, err.Error())
 	case err == storage.ErrInvalidCID:
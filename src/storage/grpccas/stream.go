@@ -0,0 +1,202 @@
+package grpccas
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"xdao.co/catf/storage"
+)
+
+// StreamPieceSize is the size of the byte pieces sent on PutStream and
+// GetStream. It stays well below gRPC's default 4 MiB message limit.
+const StreamPieceSize = 256 << 10
+
+var _ storage.StreamCAS = (*Client)(nil)
+
+// PutReader streams r to the server with PutStream and returns the chunked
+// root CID. The root is recomputed locally and must match the server's.
+//
+// Client.Timeout does not apply to streams; bound them with ctx.
+func (c *Client) PutReader(ctx context.Context, r io.Reader) (cid.Cid, error) {
+	if c == nil || c.client == nil {
+		return cid.Undef, storage.ErrNotFound
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.PutStream(ctx)
+	if err != nil {
+		return cid.Undef, mapRPC(err)
+	}
+	var h storage.ChunkedHasher
+	buf := make([]byte, StreamPieceSize)
+	for {
+		n, rerr := io.ReadFull(r, buf)
+		if n > 0 {
+			_, _ = h.Write(buf[:n])
+			if err := stream.Send(wrapperspb.Bytes(buf[:n])); err != nil {
+				// The server's status is only available from CloseAndRecv.
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return cid.Undef, mapRPC(err)
+			}
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			return cid.Undef, rerr
+		}
+	}
+	reply, err := stream.CloseAndRecv()
+	if err != nil {
+		return cid.Undef, mapRPC(err)
+	}
+	id, err := cid.Decode(reply.GetValue())
+	if err != nil || !id.Defined() {
+		return cid.Undef, storage.ErrInvalidCID
+	}
+	want, err := h.Root()
+	if err != nil {
+		return cid.Undef, err
+	}
+	if id != want {
+		return cid.Undef, storage.ErrCIDMismatch
+	}
+	return id, nil
+}
+
+// GetReader streams the blob rooted at id with GetStream. The returned reader
+// recomputes the root as it reads and returns storage.ErrCIDMismatch instead
+// of io.EOF if the streamed bytes do not match id.
+//
+// Client.Timeout does not apply to streams; bound them with ctx. Close
+// cancels the stream.
+func (c *Client) GetReader(ctx context.Context, id cid.Cid) (io.ReadCloser, error) {
+	if !id.Defined() {
+		return nil, storage.ErrInvalidCID
+	}
+	if c == nil || c.client == nil {
+		return nil, storage.ErrNotFound
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.GetStream(ctx, wrapperspb.String(id.String()))
+	if err != nil {
+		cancel()
+		return nil, mapRPC(err)
+	}
+	return &streamReader{stream: stream, cancel: cancel, want: id}, nil
+}
+
+type streamReader struct {
+	stream CAS_GetStreamClient
+	cancel context.CancelFunc
+	want   cid.Cid
+	hasher storage.ChunkedHasher
+	cur    []byte
+	err    error
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	for len(r.cur) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		m, err := r.stream.Recv()
+		if err == io.EOF {
+			r.err = io.EOF
+			got, herr := r.hasher.Root()
+			if herr != nil {
+				r.err = herr
+			} else if got != r.want {
+				r.err = storage.ErrCIDMismatch
+			}
+			continue
+		}
+		if err != nil {
+			r.err = mapRPC(err)
+			continue
+		}
+		r.cur = m.GetValue()
+		_, _ = r.hasher.Write(r.cur)
+	}
+	n := copy(p, r.cur)
+	r.cur = r.cur[n:]
+	return n, nil
+}
+
+func (r *streamReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// PutStream stores the streamed pieces in the backing CAS with
+// storage.PutReader, so server memory stays bounded by storage.ChunkSize.
+func (s *Server) PutStream(stream CAS_PutStreamServer) error {
+	if s == nil || s.CAS == nil {
+		return status.Error(codes.FailedPrecondition, "missing CAS")
+	}
+	id, err := storage.PutReader(stream.Context(), s.CAS, &pieceReader{stream: stream})
+	if err != nil {
+		return mapErr(err)
+	}
+	return stream.SendAndClose(wrapperspb.String(id.String()))
+}
+
+// GetStream sends the blob rooted at the requested CID in StreamPieceSize pieces.
+func (s *Server) GetStream(in *wrapperspb.StringValue, stream CAS_GetStreamServer) error {
+	if s == nil || s.CAS == nil {
+		return status.Error(codes.FailedPrecondition, "missing CAS")
+	}
+	id, err := cid.Decode(in.GetValue())
+	if err != nil || !id.Defined() {
+		return status.Error(codes.InvalidArgument, storage.ErrInvalidCID.Error())
+	}
+	rc, err := storage.GetReader(stream.Context(), s.CAS, id)
+	if err != nil {
+		return mapErr(err)
+	}
+	defer rc.Close()
+
+	buf := make([]byte, StreamPieceSize)
+	for {
+		n, rerr := io.ReadFull(rc, buf)
+		if n > 0 {
+			if err := stream.Send(wrapperspb.Bytes(buf[:n])); err != nil {
+				return err
+			}
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if rerr != nil {
+			return mapErr(rerr)
+		}
+	}
+}
+
+// pieceReader adapts the PutStream receive side to io.Reader.
+type pieceReader struct {
+	stream CAS_PutStreamServer
+	cur    []byte
+}
+
+func (r *pieceReader) Read(p []byte) (int, error) {
+	for len(r.cur) == 0 {
+		m, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.cur = m.GetValue()
+	}
+	n := copy(p, r.cur)
+	r.cur = r.cur[n:]
+	return n, nil
+}
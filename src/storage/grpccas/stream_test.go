@@ -0,0 +1,51 @@
+package grpccas
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"xdao.co/catf/storage"
+)
+
+func TestGRPCCAS_StreamRoundTrip(t *testing.T) {
+	backend := newMemCAS()
+	client := newBufconnClient(t, backend)
+
+	data := make([]byte, 2*storage.ChunkSize+12345)
+	for i := range data {
+		data[i] = byte(i % 253)
+	}
+	ctx := context.Background()
+	root, err := client.PutReader(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PutReader: %v", err)
+	}
+	want, err := storage.ChunkedCID(bytes.NewReader(data))
+	if err != nil || root != want {
+		t.Fatalf("root %s, want %s (%v)", root, want, err)
+	}
+	// The server stored the chunked layout in its backend, not one large block.
+	if len(backend.m) != 4 {
+		t.Fatalf("expected 4 backend blocks, got %d", len(backend.m))
+	}
+
+	rc, err := client.GetReader(ctx, root)
+	if err != nil {
+		t.Fatalf("GetReader: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("stream mismatch (err=%v, %d bytes)", err, len(got))
+	}
+
+	small, err := storage.PutReader(ctx, client, bytes.NewReader([]byte("small")))
+	if err != nil {
+		t.Fatalf("PutReader(small): %v", err)
+	}
+	if b, err := client.Get(small); err != nil || string(b) != "small" {
+		t.Fatalf("small blob should be a plain raw block: %q %v", b, err)
+	}
+}
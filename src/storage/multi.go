@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ipfs/go-cid"
@@ -12,41 +13,70 @@ import (
 // This avoids map-iteration nondeterminism and makes the retrieval strategy explicit.
 //
 // Put is defined to write only to the first adapter.
+//
+// MultiCAS implements both CAS and ContextCAS; adapters that implement
+// ContextCAS receive the caller's context.
 type MultiCAS struct {
 	Adapters []CAS
 }
 
+var _ ContextCAS = MultiCAS{}
+
 func (m MultiCAS) Put(bytes []byte) (cid.Cid, error) {
+	return m.PutContext(context.Background(), bytes)
+}
+
+func (m MultiCAS) Get(id cid.Cid) ([]byte, error) {
+	return m.GetContext(context.Background(), id)
+}
+
+func (m MultiCAS) Has(id cid.Cid) bool {
+	ok, _ := m.HasContext(context.Background(), id)
+	return ok
+}
+
+func (m MultiCAS) PutContext(ctx context.Context, bytes []byte) (cid.Cid, error) {
 	if len(m.Adapters) == 0 {
 		return cid.Undef, errors.New("storage: MultiCAS has no adapters")
 	}
-	return m.Adapters[0].Put(bytes)
+	return AsContextCAS(m.Adapters[0]).PutContext(ctx, bytes)
 }
 
-func (m MultiCAS) Get(id cid.Cid) ([]byte, error) {
-	var sawNotFound bool
+func (m MultiCAS) GetContext(ctx context.Context, id cid.Cid) ([]byte, error) {
 	for _, cas := range m.Adapters {
-		b, err := cas.Get(id)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		b, err := AsContextCAS(cas).GetContext(ctx, id)
 		if err == nil {
 			return b, nil
 		}
 		if IsNotFound(err) {
-			sawNotFound = true
 			continue
 		}
 		return nil, err
 	}
-	if sawNotFound {
-		return nil, ErrNotFound
-	}
 	return nil, ErrNotFound
 }
 
-func (m MultiCAS) Has(id cid.Cid) bool {
+// HasContext reports true if any adapter has id. Adapter errors do not stop
+// the fallback; if no adapter reports true, the first error is returned.
+func (m MultiCAS) HasContext(ctx context.Context, id cid.Cid) (bool, error) {
+	var firstErr error
 	for _, cas := range m.Adapters {
-		if cas.Has(id) {
-			return true
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		ok, err := AsContextCAS(cas).HasContext(ctx, id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			return true, nil
 		}
 	}
-	return false
+	return false, firstErr
 }
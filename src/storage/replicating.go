@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ipfs/go-cid"
@@ -23,11 +24,17 @@ type NamedCAS struct {
 // CIDs to match (otherwise ErrCIDMismatch is returned).
 //
 // Use PutAll when you need the per-backend CID mapping.
+//
+// ReplicatingCAS implements both CAS and ContextCAS; backends that implement
+// ContextCAS receive the caller's context.
 type ReplicatingCAS struct {
 	Backends []NamedCAS
 }
 
-var _ CAS = (*ReplicatingCAS)(nil)
+var (
+	_ CAS        = (*ReplicatingCAS)(nil)
+	_ ContextCAS = (*ReplicatingCAS)(nil)
+)
 
 // PutAll writes the same bytes to all backends.
 //
@@ -37,6 +44,11 @@ var _ CAS = (*ReplicatingCAS)(nil)
 //
 // If any backend returns a different CID, ErrCIDMismatch is returned.
 func (r ReplicatingCAS) PutAll(bytes []byte) (cid.Cid, map[string]cid.Cid, error) {
+	return r.PutAllContext(context.Background(), bytes)
+}
+
+// PutAllContext is PutAll with cancellation; it stops before the next backend once ctx is done.
+func (r ReplicatingCAS) PutAllContext(ctx context.Context, bytes []byte) (cid.Cid, map[string]cid.Cid, error) {
 	want, err := cidutil.CIDv1RawSHA256CID(bytes)
 	if err != nil {
 		return cid.Undef, nil, err
@@ -53,7 +65,10 @@ func (r ReplicatingCAS) PutAll(bytes []byte) (cid.Cid, map[string]cid.Cid, error
 		if b.CAS == nil {
 			return cid.Undef, nil, fmt.Errorf("storage: nil CAS for backend %q", b.Name)
 		}
-		got, err := b.CAS.Put(bytes)
+		if err := ctx.Err(); err != nil {
+			return cid.Undef, out, err
+		}
+		got, err := AsContextCAS(b.CAS).PutContext(ctx, bytes)
 		if err != nil {
 			return cid.Undef, nil, err
 		}
@@ -71,32 +86,60 @@ func (r ReplicatingCAS) Put(bytes []byte) (cid.Cid, error) {
 }
 
 func (r ReplicatingCAS) Get(id cid.Cid) ([]byte, error) {
-	var sawNotFound bool
+	return r.GetContext(context.Background(), id)
+}
+
+func (r ReplicatingCAS) Has(id cid.Cid) bool {
+	ok, _ := r.HasContext(context.Background(), id)
+	return ok
+}
+
+func (r ReplicatingCAS) PutContext(ctx context.Context, bytes []byte) (cid.Cid, error) {
+	id, _, err := r.PutAllContext(ctx, bytes)
+	return id, err
+}
+
+func (r ReplicatingCAS) GetContext(ctx context.Context, id cid.Cid) ([]byte, error) {
 	for _, b := range r.Backends {
 		if b.CAS == nil {
 			continue
 		}
-		out, err := b.CAS.Get(id)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out, err := AsContextCAS(b.CAS).GetContext(ctx, id)
 		if err == nil {
 			return out, nil
 		}
 		if IsNotFound(err) {
-			sawNotFound = true
 			continue
 		}
 		return nil, err
 	}
-	if sawNotFound {
-		return nil, ErrNotFound
-	}
 	return nil, ErrNotFound
 }
 
-func (r ReplicatingCAS) Has(id cid.Cid) bool {
+// HasContext reports true if any backend has id. Backend errors do not stop
+// the fallback; if no backend reports true, the first error is returned.
+func (r ReplicatingCAS) HasContext(ctx context.Context, id cid.Cid) (bool, error) {
+	var firstErr error
 	for _, b := range r.Backends {
-		if b.CAS != nil && b.CAS.Has(id) {
-			return true
+		if b.CAS == nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		ok, err := AsContextCAS(b.CAS).HasContext(ctx, id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ok {
+			return true, nil
 		}
 	}
-	return false
+	return false, firstErr
 }
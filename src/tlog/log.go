@@ -0,0 +1,210 @@
+// Package tlog implements an append-only Merkle transparency log of CATF and
+// CROF CIDs.
+//
+// Tree hashing follows RFC 6962: a leaf hashes as SHA-256(0x00 || CID string)
+// and an interior node as SHA-256(0x01 || left || right). The log operator
+// periodically signs a tree head (TreeHead) committing to the current size and
+// root; auditors holding two signed heads and a consistency proof can confirm
+// nothing was removed or rewritten, and an inclusion proof against a signed
+// head proves a CID was logged no later than the head's timestamp.
+//
+// On disk a log is a directory holding a single "leaves" file with one
+// canonical CID string per line, in append order. The tree is recomputed from
+// that file on Open.
+//
+// Proof and tree head verification needs only the proof, the signed head and
+// the log's public key, so it can be done offline.
+//
+// See format.md in this directory for the tree head and proof encodings.
+//
+// API stability: see STABILITY.md (repository root) for Stable vs Experimental tiers.
+package tlog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+)
+
+// leavesFile is the name of the append-only leaves file within a log directory.
+const leavesFile = "leaves"
+
+// ErrNotFound is returned when a CID has not been appended to the log.
+var ErrNotFound = errors.New("tlog: CID not in log")
+
+// Log is an on-disk transparency log. It is safe for concurrent use within a
+// process; only one process should append to a log directory at a time.
+type Log struct {
+	dir string
+
+	mu     sync.RWMutex
+	cids   []string
+	leaves []Hash
+	index  map[string]uint64
+}
+
+// Open opens (creating if needed) the log stored in dir.
+func Open(dir string) (*Log, error) {
+	if dir == "" {
+		return nil, errors.New("tlog: empty directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("tlog: %w", err)
+	}
+	l := &Log{dir: dir, index: make(map[string]uint64)}
+	b, err := os.ReadFile(filepath.Join(dir, leavesFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("tlog: %w", err)
+	}
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		return nil, errors.New("tlog: leaves file is truncated")
+	}
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; sc.Scan(); line++ {
+		s := sc.Text()
+		if _, err := canonicalCID(s); err != nil {
+			return nil, fmt.Errorf("tlog: leaves line %d: %w", line, err)
+		}
+		if _, dup := l.index[s]; dup {
+			return nil, fmt.Errorf("tlog: leaves line %d: duplicate CID %s", line, s)
+		}
+		l.add(s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("tlog: %w", err)
+	}
+	return l, nil
+}
+
+// Append adds a CID to the log and returns its leaf index. Appending a CID
+// that is already logged returns its existing index and leaves the log
+// unchanged.
+func (l *Log) Append(id cid.Cid) (uint64, error) {
+	if !id.Defined() {
+		return 0, errors.New("tlog: undefined CID")
+	}
+	s := id.String()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i, ok := l.index[s]; ok {
+		return i, nil
+	}
+	f, err := os.OpenFile(filepath.Join(l.dir, leavesFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("tlog: %w", err)
+	}
+	_, werr := f.WriteString(s + "\n")
+	if werr == nil {
+		werr = f.Sync()
+	}
+	cerr := f.Close()
+	if werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return 0, fmt.Errorf("tlog: append: %w", werr)
+	}
+	return l.add(s), nil
+}
+
+func (l *Log) add(s string) uint64 {
+	i := uint64(len(l.cids))
+	l.cids = append(l.cids, s)
+	l.leaves = append(l.leaves, LeafHash([]byte(s)))
+	l.index[s] = i
+	return i
+}
+
+// Size returns the number of logged CIDs.
+func (l *Log) Size() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return uint64(len(l.leaves))
+}
+
+// Root returns the root hash of the tree of the given size.
+func (l *Log) Root(size uint64) (Hash, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if size > uint64(len(l.leaves)) {
+		return Hash{}, fmt.Errorf("tlog: size %d exceeds log size %d", size, len(l.leaves))
+	}
+	return RootHash(l.leaves[:size]), nil
+}
+
+// Lookup returns the leaf index of a logged CID.
+func (l *Log) Lookup(id cid.Cid) (uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	i, ok := l.index[id.String()]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return i, nil
+}
+
+// Entries returns the logged CIDs in append order.
+func (l *Log) Entries() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]string(nil), l.cids...)
+}
+
+// ProveInclusion returns a proof that id is included in the tree of the given
+// size.
+func (l *Log) ProveInclusion(id cid.Cid, size uint64) (*InclusionProof, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if size > uint64(len(l.leaves)) {
+		return nil, fmt.Errorf("tlog: size %d exceeds log size %d", size, len(l.leaves))
+	}
+	i, ok := l.index[id.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if i >= size {
+		return nil, fmt.Errorf("tlog: %s was appended at index %d, after tree size %d", id, i, size)
+	}
+	return &InclusionProof{
+		CID:       id.String(),
+		LeafIndex: i,
+		TreeSize:  size,
+		Hashes:    hexHashes(inclusionPath(int(i), l.leaves[:size])),
+	}, nil
+}
+
+// ProveConsistency returns a proof that the tree of oldSize is a prefix of the
+// tree of newSize.
+func (l *Log) ProveConsistency(oldSize, newSize uint64) (*ConsistencyProof, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if newSize > uint64(len(l.leaves)) {
+		return nil, fmt.Errorf("tlog: size %d exceeds log size %d", newSize, len(l.leaves))
+	}
+	if oldSize > newSize {
+		return nil, fmt.Errorf("tlog: old size %d exceeds new size %d", oldSize, newSize)
+	}
+	p := &ConsistencyProof{OldSize: oldSize, NewSize: newSize, Hashes: []string{}}
+	if oldSize > 0 && oldSize < newSize {
+		p.Hashes = hexHashes(consistencyPath(int(oldSize), l.leaves[:newSize], true))
+	}
+	return p, nil
+}
+
+func canonicalCID(s string) (cid.Cid, error) {
+	id, err := cid.Decode(s)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if id.String() != s {
+		return cid.Undef, fmt.Errorf("non-canonical CID %q", s)
+	}
+	return id, nil
+}
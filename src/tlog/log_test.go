@@ -0,0 +1,94 @@
+package tlog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	"xdao.co/catf/cidutil"
+)
+
+func mustCID(t *testing.T, s string) cid.Cid {
+	t.Helper()
+	id, err := cidutil.CIDv1RawSHA256CID([]byte(s))
+	if err != nil {
+		t.Fatalf("cid: %v", err)
+	}
+	return id
+}
+
+func TestLog_AppendReopenProve(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	var ids []cid.Cid
+	for i := 0; i < 7; i++ {
+		id := mustCID(t, fmt.Sprintf("attestation-%d", i))
+		idx, err := l.Append(id)
+		if err != nil || idx != uint64(i) {
+			t.Fatalf("Append %d = %d, %v", i, idx, err)
+		}
+		ids = append(ids, id)
+	}
+	if idx, err := l.Append(ids[2]); err != nil || idx != 2 {
+		t.Fatalf("re-Append = %d, %v", idx, err)
+	}
+	oldRoot, err := l.Root(4)
+	if err != nil {
+		t.Fatalf("Root: %v", err)
+	}
+
+	l, err = Open(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if l.Size() != 7 {
+		t.Fatalf("size = %d", l.Size())
+	}
+	newRoot, _ := l.Root(7)
+
+	p, err := l.ProveInclusion(ids[3], 7)
+	if err != nil {
+		t.Fatalf("ProveInclusion: %v", err)
+	}
+	if err := p.Verify(newRoot); err != nil {
+		t.Fatalf("inclusion: %v", err)
+	}
+	if _, err := l.ProveInclusion(ids[5], 4); err == nil {
+		t.Fatalf("expected error proving a later leaf in an older tree")
+	}
+	if _, err := l.ProveInclusion(mustCID(t, "absent"), 7); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	c, err := l.ProveConsistency(4, 7)
+	if err != nil {
+		t.Fatalf("ProveConsistency: %v", err)
+	}
+	if err := c.Verify(oldRoot, newRoot); err != nil {
+		t.Fatalf("consistency: %v", err)
+	}
+}
+
+func TestOpen_RejectsCorruptLeaves(t *testing.T) {
+	dir := t.TempDir()
+	id := mustCID(t, "a").String()
+	for name, content := range map[string]string{
+		"truncated": id,
+		"invalid":   "not-a-cid\n",
+		"duplicate": id + "\n" + id + "\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, leavesFile), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(dir); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
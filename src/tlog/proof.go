@@ -0,0 +1,87 @@
+package tlog
+
+import (
+	"fmt"
+)
+
+// InclusionProof proves that CID is the LeafIndex-th leaf of the tree of
+// TreeSize leaves. Hashes is the RFC 6962 audit path, lowercase hex.
+type InclusionProof struct {
+	CID       string   `json:"cid"`
+	LeafIndex uint64   `json:"leaf_index"`
+	TreeSize  uint64   `json:"tree_size"`
+	Hashes    []string `json:"hashes"`
+}
+
+// ConsistencyProof proves that the tree of OldSize leaves is a prefix of the
+// tree of NewSize leaves. Hashes is the RFC 6962 consistency proof, lowercase
+// hex.
+type ConsistencyProof struct {
+	OldSize uint64   `json:"old_size"`
+	NewSize uint64   `json:"new_size"`
+	Hashes  []string `json:"hashes"`
+}
+
+// Verify checks the proof against the root of a tree of p.TreeSize leaves.
+func (p *InclusionProof) Verify(root Hash) error {
+	if _, err := canonicalCID(p.CID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	hashes, err := parseHashes(p.Hashes)
+	if err != nil {
+		return err
+	}
+	return VerifyInclusion(LeafHash([]byte(p.CID)), p.LeafIndex, p.TreeSize, hashes, root)
+}
+
+// VerifyTreeHead checks the proof against a signed tree head of the same size.
+// The tree head signature is not checked here; see TreeHead.Verify.
+func (p *InclusionProof) VerifyTreeHead(th *TreeHead) error {
+	if th.TreeSize != p.TreeSize {
+		return fmt.Errorf("%w: proof is for tree size %d, tree head has %d", ErrInvalidProof, p.TreeSize, th.TreeSize)
+	}
+	return p.Verify(th.RootHash)
+}
+
+// Verify checks the proof against the roots of the old and new trees.
+func (p *ConsistencyProof) Verify(oldRoot, newRoot Hash) error {
+	hashes, err := parseHashes(p.Hashes)
+	if err != nil {
+		return err
+	}
+	return VerifyConsistency(p.OldSize, p.NewSize, oldRoot, newRoot, hashes)
+}
+
+// VerifyTreeHeads checks the proof against two signed tree heads whose sizes
+// match the proof. Tree head signatures are not checked here; see
+// TreeHead.Verify.
+func (p *ConsistencyProof) VerifyTreeHeads(oldHead, newHead *TreeHead) error {
+	if oldHead.TreeSize != p.OldSize || newHead.TreeSize != p.NewSize {
+		return fmt.Errorf("%w: proof is for sizes %d->%d, tree heads have %d->%d",
+			ErrInvalidProof, p.OldSize, p.NewSize, oldHead.TreeSize, newHead.TreeSize)
+	}
+	if oldHead.LogKey != newHead.LogKey {
+		return fmt.Errorf("%w: tree heads are signed by different log keys", ErrInvalidProof)
+	}
+	return p.Verify(oldHead.RootHash, newHead.RootHash)
+}
+
+func hexHashes(hs []Hash) []string {
+	out := make([]string, len(hs))
+	for i, h := range hs {
+		out[i] = h.String()
+	}
+	return out
+}
+
+func parseHashes(ss []string) ([]Hash, error) {
+	out := make([]Hash, len(ss))
+	for i, s := range ss {
+		h, err := ParseHash(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		}
+		out[i] = h
+	}
+	return out, nil
+}
@@ -0,0 +1,181 @@
+package tlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// HashSize is the size of tree hashes (SHA-256).
+const HashSize = sha256.Size
+
+// Hash is a Merkle tree hash.
+type Hash [HashSize]byte
+
+func (h Hash) String() string { return hex.EncodeToString(h[:]) }
+
+// ParseHash decodes a lowercase hex tree hash.
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != HashSize || hex.EncodeToString(b) != s {
+		return h, fmt.Errorf("tlog: invalid hash %q", s)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// ErrInvalidProof reports a proof that does not verify.
+var ErrInvalidProof = errors.New("tlog: invalid proof")
+
+// LeafHash returns the RFC 6962 leaf hash SHA-256(0x00 || data).
+func LeafHash(data []byte) Hash {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	var out Hash
+	h.Sum(out[:0])
+	return out
+}
+
+// nodeHash returns the RFC 6962 interior hash SHA-256(0x01 || left || right).
+func nodeHash(left, right Hash) Hash {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left[:])
+	h.Write(right[:])
+	var out Hash
+	h.Sum(out[:0])
+	return out
+}
+
+// RootHash returns the Merkle tree hash of the given leaf hashes. The empty
+// tree hashes to SHA-256 of the empty string.
+func RootHash(leaves []Hash) Hash {
+	switch len(leaves) {
+	case 0:
+		return sha256.Sum256(nil)
+	case 1:
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return nodeHash(RootHash(leaves[:k]), RootHash(leaves[k:]))
+}
+
+// split returns the largest power of two smaller than n (n >= 2).
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+// inclusionPath returns the RFC 6962 audit path for leaf m.
+func inclusionPath(m int, leaves []Hash) []Hash {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := split(len(leaves))
+	if m < k {
+		return append(inclusionPath(m, leaves[:k]), RootHash(leaves[k:]))
+	}
+	return append(inclusionPath(m-k, leaves[k:]), RootHash(leaves[:k]))
+}
+
+// consistencyPath returns the RFC 6962 consistency proof between the first m
+// leaves and all leaves.
+func consistencyPath(m int, leaves []Hash, complete bool) []Hash {
+	n := len(leaves)
+	if m == n {
+		if complete {
+			return nil
+		}
+		return []Hash{RootHash(leaves)}
+	}
+	k := split(n)
+	if m <= k {
+		return append(consistencyPath(m, leaves[:k], complete), RootHash(leaves[k:]))
+	}
+	return append(consistencyPath(m-k, leaves[k:], false), RootHash(leaves[:k]))
+}
+
+// VerifyInclusion checks that leaf is the index-th leaf of the tree of the
+// given size and root (RFC 9162, section 2.1.3.2).
+func VerifyInclusion(leaf Hash, index, size uint64, proof []Hash, root Hash) error {
+	if index >= size {
+		return fmt.Errorf("%w: index %d outside tree of size %d", ErrInvalidProof, index, size)
+	}
+	fn, sn := index, size-1
+	r := leaf
+	for _, p := range proof {
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || r != root {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size1 with root1 is a prefix of
+// the tree of size2 with root2 (RFC 9162, section 2.1.4.2).
+func VerifyConsistency(size1, size2 uint64, root1, root2 Hash, proof []Hash) error {
+	switch {
+	case size1 > size2:
+		return fmt.Errorf("%w: old size %d exceeds new size %d", ErrInvalidProof, size1, size2)
+	case size1 == size2:
+		if len(proof) != 0 || root1 != root2 {
+			return fmt.Errorf("%w: equal sizes require equal roots and an empty proof", ErrInvalidProof)
+		}
+		return nil
+	case size1 == 0:
+		if len(proof) != 0 {
+			return fmt.Errorf("%w: proof from the empty tree must be empty", ErrInvalidProof)
+		}
+		return nil
+	}
+	if len(proof) == 0 {
+		return fmt.Errorf("%w: empty proof", ErrInvalidProof)
+	}
+	if size1&(size1-1) == 0 {
+		proof = append([]Hash{root1}, proof...)
+	}
+	fn, sn := size1-1, size2-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || fr != root1 || sr != root2 {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
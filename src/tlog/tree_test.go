@@ -0,0 +1,72 @@
+package tlog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testLeaves(n int) []Hash {
+	out := make([]Hash, n)
+	for i := range out {
+		out[i] = LeafHash([]byte(fmt.Sprintf("leaf-%d", i)))
+	}
+	return out
+}
+
+func TestRootHash_Empty(t *testing.T) {
+	if got := RootHash(nil).String(); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("empty root = %s", got)
+	}
+}
+
+func TestInclusionProofs_AllSizes(t *testing.T) {
+	leaves := testLeaves(33)
+	for n := 1; n <= len(leaves); n++ {
+		root := RootHash(leaves[:n])
+		for m := 0; m < n; m++ {
+			proof := inclusionPath(m, leaves[:n])
+			if err := VerifyInclusion(leaves[m], uint64(m), uint64(n), proof, root); err != nil {
+				t.Fatalf("size %d index %d: %v", n, m, err)
+			}
+			if err := VerifyInclusion(leaves[(m+1)%n], uint64(m), uint64(n), proof, root); n > 1 && !errors.Is(err, ErrInvalidProof) {
+				t.Fatalf("size %d index %d: wrong leaf verified", n, m)
+			}
+			if len(proof) > 0 {
+				bad := append([]Hash(nil), proof...)
+				bad[0][0] ^= 1
+				if err := VerifyInclusion(leaves[m], uint64(m), uint64(n), bad, root); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("size %d index %d: tampered proof verified", n, m)
+				}
+			}
+		}
+	}
+}
+
+func TestConsistencyProofs_AllSizes(t *testing.T) {
+	leaves := testLeaves(33)
+	for n := 1; n <= len(leaves); n++ {
+		newRoot := RootHash(leaves[:n])
+		for m := 1; m <= n; m++ {
+			oldRoot := RootHash(leaves[:m])
+			var proof []Hash
+			if m < n {
+				proof = consistencyPath(m, leaves[:n], true)
+			}
+			if err := VerifyConsistency(uint64(m), uint64(n), oldRoot, newRoot, proof); err != nil {
+				t.Fatalf("%d->%d: %v", m, n, err)
+			}
+			if m < n {
+				if err := VerifyConsistency(uint64(m), uint64(n), newRoot, newRoot, proof); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("%d->%d: wrong old root verified", m, n)
+				}
+			}
+		}
+	}
+	if err := VerifyConsistency(0, 5, Hash{}, RootHash(leaves[:5]), nil); err != nil {
+		t.Fatalf("empty old tree: %v", err)
+	}
+	if err := VerifyConsistency(5, 4, Hash{}, Hash{}, nil); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected shrinking tree to fail, got %v", err)
+	}
+}
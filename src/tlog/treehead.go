@@ -0,0 +1,195 @@
+package tlog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"xdao.co/catf/keys"
+)
+
+// TreeHeadSpec is the Spec value of signed tree heads.
+const TreeHeadSpec = "xdao-sth-1"
+
+const (
+	treeHeadBegin = "-----BEGIN XDAO TREE HEAD-----"
+	treeHeadEnd   = "-----END XDAO TREE HEAD-----"
+)
+
+// treeHeadFields is the fixed field order of a rendered tree head.
+var treeHeadFields = []string{"Spec", "Log-Key", "Tree-Size", "Root-Hash", "Timestamp", "Hash-Alg", "Signature-Alg", "Signature"}
+
+// TreeHead is a signed commitment to the log's size and root at a point in
+// time.
+type TreeHead struct {
+	// LogKey is the algorithm-qualified public key of the log (<alg>:<base64>).
+	LogKey    string
+	TreeSize  uint64
+	RootHash  Hash
+	Timestamp time.Time
+	// HashAlg is the digest algorithm the signature is computed over.
+	HashAlg      string
+	SignatureAlg string
+	// Signature is the base64 signature over every rendered byte preceding
+	// the Signature line.
+	Signature string
+}
+
+// SignTreeHead signs the current size and root of the log.
+//
+// hashAlg selects the signature digest (sha256, sha512 or sha3-256); empty
+// means sha256. The timestamp is truncated to seconds and stored in UTC.
+func (l *Log) SignTreeHead(s keys.Signer, hashAlg string, at time.Time) (*TreeHead, error) {
+	l.mu.RLock()
+	size := uint64(len(l.leaves))
+	root := RootHash(l.leaves)
+	l.mu.RUnlock()
+	return SignTreeHead(s, hashAlg, size, root, at)
+}
+
+// SignTreeHead signs an arbitrary size and root with s.
+func SignTreeHead(s keys.Signer, hashAlg string, size uint64, root Hash, at time.Time) (*TreeHead, error) {
+	if s == nil {
+		return nil, errors.New("tlog: missing signer")
+	}
+	if hashAlg == "" {
+		hashAlg = "sha256"
+	}
+	th := &TreeHead{
+		LogKey:       keys.IssuerKeyForSigner(s),
+		TreeSize:     size,
+		RootHash:     root,
+		Timestamp:    at.UTC().Truncate(time.Second),
+		HashAlg:      hashAlg,
+		SignatureAlg: s.Algorithm(),
+	}
+	sig, err := keys.SignMessage(s, hashAlg, th.signedBytes())
+	if err != nil {
+		return nil, fmt.Errorf("tlog: sign tree head: %w", err)
+	}
+	th.Signature = sig
+	return th, nil
+}
+
+func (th *TreeHead) values() map[string]string {
+	return map[string]string{
+		"Spec":          TreeHeadSpec,
+		"Log-Key":       th.LogKey,
+		"Tree-Size":     strconv.FormatUint(th.TreeSize, 10),
+		"Root-Hash":     th.RootHash.String(),
+		"Timestamp":     th.Timestamp.UTC().Format(time.RFC3339),
+		"Hash-Alg":      th.HashAlg,
+		"Signature-Alg": th.SignatureAlg,
+		"Signature":     th.Signature,
+	}
+}
+
+// signedBytes returns the rendered tree head up to (excluding) the Signature
+// line.
+func (th *TreeHead) signedBytes() []byte {
+	var b bytes.Buffer
+	b.WriteString(treeHeadBegin + "\n")
+	v := th.values()
+	for _, k := range treeHeadFields {
+		if k == "Signature" {
+			break
+		}
+		b.WriteString(k + ": " + v[k] + "\n")
+	}
+	return b.Bytes()
+}
+
+// Bytes renders the canonical tree head text.
+func (th *TreeHead) Bytes() []byte {
+	b := th.signedBytes()
+	b = append(b, "Signature: "+th.Signature+"\n"+treeHeadEnd+"\n"...)
+	return b
+}
+
+// ParseTreeHead parses canonical tree head text. It does not verify the
+// signature; see Verify.
+func ParseTreeHead(b []byte) (*TreeHead, error) {
+	if !bytes.HasSuffix(b, []byte("\n")) {
+		return nil, errors.New("tlog: tree head must end with a newline")
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != len(treeHeadFields)+2 || lines[0] != treeHeadBegin || lines[len(lines)-1] != treeHeadEnd {
+		return nil, errors.New("tlog: malformed tree head")
+	}
+	v := make(map[string]string, len(treeHeadFields))
+	for i, k := range treeHeadFields {
+		val, ok := strings.CutPrefix(lines[i+1], k+": ")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("tlog: tree head line %d: expected %s", i+2, k)
+		}
+		v[k] = val
+	}
+	if v["Spec"] != TreeHeadSpec {
+		return nil, fmt.Errorf("tlog: unsupported tree head Spec %q", v["Spec"])
+	}
+	size, err := strconv.ParseUint(v["Tree-Size"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("tlog: invalid Tree-Size: %w", err)
+	}
+	root, err := ParseHash(v["Root-Hash"])
+	if err != nil {
+		return nil, err
+	}
+	ts, err := time.Parse(time.RFC3339, v["Timestamp"])
+	if err != nil {
+		return nil, fmt.Errorf("tlog: invalid Timestamp: %w", err)
+	}
+	th := &TreeHead{
+		LogKey:       v["Log-Key"],
+		TreeSize:     size,
+		RootHash:     root,
+		Timestamp:    ts,
+		HashAlg:      v["Hash-Alg"],
+		SignatureAlg: v["Signature-Alg"],
+		Signature:    v["Signature"],
+	}
+	if !bytes.Equal(th.Bytes(), b) {
+		return nil, errors.New("tlog: tree head is not canonical")
+	}
+	return th, nil
+}
+
+// Verify checks the tree head signature against its Log-Key.
+//
+// Verify only proves the head was signed by the key it names; callers must
+// also compare LogKey against the log key they trust.
+func (th *TreeHead) Verify() error {
+	pub, err := parseLogKey(th.LogKey, th.SignatureAlg)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(th.Signature)
+	if err != nil {
+		return fmt.Errorf("tlog: invalid Signature encoding: %w", err)
+	}
+	if err := keys.VerifyMessage(th.SignatureAlg, th.HashAlg, pub, th.signedBytes(), sig); err != nil {
+		return fmt.Errorf("tlog: tree head: %w", err)
+	}
+	return nil
+}
+
+// parseLogKey decodes an algorithm-qualified Log-Key and checks it matches
+// sigAlg. Key length is checked by keys.VerifyMessage.
+func parseLogKey(s, sigAlg string) ([]byte, error) {
+	alg, b64, ok := strings.Cut(s, ":")
+	if !ok || (alg != "ed25519" && alg != "dilithium3") {
+		return nil, fmt.Errorf("tlog: unsupported Log-Key %q", s)
+	}
+	if alg != sigAlg {
+		return nil, fmt.Errorf("tlog: Log-Key algorithm %q does not match Signature-Alg %q", alg, sigAlg)
+	}
+	b, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("tlog: invalid Log-Key encoding: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,99 @@
+package tlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"xdao.co/catf/keys"
+)
+
+type deterministicReader struct{}
+
+func (deterministicReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0x42
+	}
+	return len(p), nil
+}
+
+func testSigners(t *testing.T) map[string]keys.Signer {
+	t.Helper()
+	ed, err := keys.NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x17}, ed25519.SeedSize)))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer: %v", err)
+	}
+	_, sk, err := keys.GenerateDilithium3Keypair(io.Reader(deterministicReader{}))
+	if err != nil {
+		t.Fatalf("GenerateDilithium3Keypair: %v", err)
+	}
+	pq, err := keys.NewDilithium3Signer(sk)
+	if err != nil {
+		t.Fatalf("NewDilithium3Signer: %v", err)
+	}
+	return map[string]keys.Signer{"ed25519": ed, "dilithium3": pq}
+}
+
+func TestTreeHead_SignParseVerify(t *testing.T) {
+	l, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if _, err := l.Append(mustCID(t, s)); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for alg, signer := range testSigners(t) {
+		t.Run(alg, func(t *testing.T) {
+			th, err := l.SignTreeHead(signer, "", at)
+			if err != nil {
+				t.Fatalf("SignTreeHead: %v", err)
+			}
+			b := th.Bytes()
+			parsed, err := ParseTreeHead(b)
+			if err != nil {
+				t.Fatalf("ParseTreeHead: %v\n%s", err, b)
+			}
+			if err := parsed.Verify(); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if parsed.TreeSize != 3 || parsed.LogKey != keys.IssuerKeyForSigner(signer) || !parsed.Timestamp.Equal(at) {
+				t.Fatalf("unexpected tree head: %+v", parsed)
+			}
+
+			tampered, err := ParseTreeHead(bytes.Replace(b, []byte("Tree-Size: 3"), []byte("Tree-Size: 2"), 1))
+			if err != nil {
+				t.Fatalf("ParseTreeHead tampered: %v", err)
+			}
+			if err := tampered.Verify(); err == nil {
+				t.Fatalf("expected tampered tree head to fail verification")
+			}
+		})
+	}
+}
+
+func TestParseTreeHead_RejectsNonCanonical(t *testing.T) {
+	th, err := SignTreeHead(testSigners(t)["ed25519"], "sha256", 0, RootHash(nil), time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("SignTreeHead: %v", err)
+	}
+	b := string(th.Bytes())
+	for name, in := range map[string]string{
+		"crlf":         strings.ReplaceAll(b, "\n", "\r\n"),
+		"no newline":   strings.TrimSuffix(b, "\n"),
+		"padded size":  strings.Replace(b, "Tree-Size: 0", "Tree-Size: 00", 1),
+		"upper hash":   strings.Replace(b, th.RootHash.String(), strings.ToUpper(th.RootHash.String()), 1),
+		"wrong spec":   strings.Replace(b, TreeHeadSpec, "xdao-sth-2", 1),
+		"local offset": strings.Replace(b, "1970-01-01T00:00:00Z", "1970-01-01T01:00:00+01:00", 1),
+	} {
+		if _, err := ParseTreeHead([]byte(in)); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tpdl
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"xdao.co/catf/compliance"
+)
+
+func TestConformanceVectors_TPDL_Errors(t *testing.T) {
+	root := filepath.Join("..", "testdata", "conformance", "tpdl", "xdao-tpdl-errors-1")
+	manifest, err := os.ReadFile(filepath.Join(root, "vectors.txt"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+
+	n := 0
+	for _, line := range strings.Split(string(manifest), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			t.Fatalf("malformed manifest line %q", line)
+		}
+		name, mode, wantRule := fields[0], fields[1], fields[2]
+		wantLine, err := strconv.Atoi(fields[3])
+		if err != nil {
+			t.Fatalf("malformed line number in %q", line)
+		}
+		b, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		m := compliance.Permissive
+		if mode == "strict" {
+			m = compliance.Strict
+		}
+		n++
+
+		_, err = ParseWithCompliance(b, m)
+		if wantRule == "-" {
+			if err != nil {
+				t.Errorf("%s: expected policy to be accepted, got %v", name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected %s, got no error", name, wantRule)
+			continue
+		}
+		if got := RuleID(err); got != wantRule {
+			t.Errorf("%s: RuleID = %q, want %q (%v)", name, got, wantRule, err)
+		}
+		if got := Line(err); got != wantLine {
+			t.Errorf("%s: Line = %d, want %d (%v)", name, got, wantLine, err)
+		}
+	}
+	if n == 0 {
+		t.Fatalf("no vectors in manifest")
+	}
+}
@@ -0,0 +1,76 @@
+package tpdl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind is a stable category for programmatic error handling.
+//
+// Callers should branch on Kind/RuleID rather than matching error strings.
+// Use errors.As to extract *Error for structured handling.
+type Kind string
+
+const (
+	KindParse      Kind = "Parse"
+	KindCanonical  Kind = "Canonical"
+	KindValidation Kind = "Validation"
+	KindRender     Kind = "Render"
+)
+
+// Error is the package's structured error type.
+//
+// RuleID is a stable identifier (e.g., TPDL-STR-010, TPDL-VAL-201) that names
+// the violated rule; see docs/spec/TPDL-ERRORS-1.md. Line is the 1-based line
+// of the policy that failed the rule, or 0 when the failure is not tied to a
+// line.
+//
+// Message is intended for humans; do not match on it.
+type Error struct {
+	Kind    Kind
+	RuleID  string
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// newError builds a structured error; line is a 1-based line number or 0.
+func newError(kind Kind, ruleID string, line int, msg string) error {
+	return &Error{Kind: kind, RuleID: ruleID, Line: line, Message: msg}
+}
+
+// IsKind reports whether err is (or wraps) a *Error with the given Kind.
+func IsKind(err error, kind Kind) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.Kind == kind
+}
+
+// RuleID returns the stable RuleID for a structured error, or "" if unknown.
+func RuleID(err error) string {
+	var e *Error
+	if !errors.As(err, &e) {
+		return ""
+	}
+	return e.RuleID
+}
+
+// Line returns the 1-based policy line of a structured error, or 0 if unknown.
+func Line(err error) int {
+	var e *Error
+	if !errors.As(err, &e) {
+		return 0
+	}
+	return e.Line
+}
@@ -0,0 +1,163 @@
+package tpdl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"xdao.co/catf/keys"
+)
+
+// Warning is a Lint finding: a construct that parses but is probably a
+// mistake, such as a rule no trusted key can ever satisfy.
+//
+// ID is a stable identifier (TPDL-LINT-###); see docs/spec/TPDL-ERRORS-1.md.
+// Line is the 1-based policy line the finding is attributed to. Message is
+// intended for humans; do not match on it.
+type Warning struct {
+	ID      string
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s %s", w.Line, w.ID, w.Message)
+}
+
+// knownMeta lists the META fields defined by the TPDL specs.
+var knownMeta = map[string]bool{"Spec": true, "Version": true}
+
+// Lint parses a policy and reports likely mistakes, sorted by line and ID.
+//
+// Lint never changes what a policy means: a policy with warnings resolves
+// exactly as it would without them. A policy that does not parse is reported
+// as the Parse error, with no warnings.
+func Lint(data []byte) ([]Warning, error) {
+	p, pos, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Warning
+	warn := func(id string, line int, format string, args ...any) {
+		out = append(out, Warning{ID: id, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for k := range p.Meta {
+		if !knownMeta[k] {
+			warn("TPDL-LINT-001", pos.meta[k], "unknown META field %q", k)
+		}
+	}
+
+	held := make(map[string]bool)
+	seen := make(map[TrustEntry]bool)
+	for i, e := range p.Trust {
+		held[e.Role] = true
+		if reason := issuerKeyProblem(e.Key); reason != "" {
+			warn("TPDL-LINT-101", pos.trust[i], "malformed Key %q: %s", e.Key, reason)
+		}
+		if seen[e] {
+			warn("TPDL-LINT-102", pos.trust[i], "duplicate trust entry for %q as %q", e.Key, e.Role)
+		}
+		seen[e] = true
+	}
+	seenDeny := make(map[DenyEntry]bool)
+	for i, d := range p.Deny {
+		if reason := issuerKeyProblem(d.Key); reason != "" {
+			warn("TPDL-LINT-101", pos.deny[i], "malformed Deny key %q: %s", d.Key, reason)
+		}
+		if seenDeny[d] {
+			warn("TPDL-LINT-102", pos.deny[i], "duplicate Deny entry for %q", d.Key)
+		}
+		seenDeny[d] = true
+	}
+
+	for i, r := range p.Rules {
+		rp := pos.rules[i]
+		missing := false
+		for _, role := range r.Roles() {
+			if !held[role] {
+				missing = true
+				warn("TPDL-LINT-201", rp.roles, "no TRUST entry holds role %q required for %s", role, r.Type)
+			}
+		}
+		// Delegates count toward quorums, so keys in TRUST are only a lower
+		// bound when delegation is enabled.
+		if missing || p.DelegationMaxDepth > 0 {
+			continue
+		}
+		if n := p.holders(r.Roles(), r.Type); r.Quorum > n {
+			line := rp.quorum
+			if line == 0 {
+				line = rp.block
+			}
+			warn("TPDL-LINT-202", line, "Quorum %d for %s exceeds the %d key(s) holding %s", r.Quorum, r.Type, n, r.RoleLabel())
+		}
+	}
+
+	for _, b := range []struct {
+		id, block string
+	}{
+		{"TPDL-LINT-301", "Supersedes:"},
+		{"TPDL-LINT-302", "Key-Recovery:"},
+		{"TPDL-LINT-303", "Policy-Supersedes:"},
+	} {
+		for role, line := range pos.allowedBy[b.block] {
+			if !held[role] {
+				warn(b.id, line, "no TRUST entry holds %s Allowed-By role %q", strings.TrimSuffix(b.block, ":"), role)
+			}
+		}
+	}
+	if q := p.PolicySupersedesQuorum; q > 0 {
+		if n := p.holders(p.PolicySupersedesAllowedBy, "policy-supersedes"); q > n {
+			warn("TPDL-LINT-304", pos.policySupersedes, "Policy-Supersedes Quorum %d exceeds the %d key(s) holding an Allowed-By role", q, n)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Line != out[j].Line {
+			return out[i].Line < out[j].Line
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// holders counts the distinct keys that hold one of roles for claims of type
+// typ and are not denied for it. Subject-scoped entries are counted, since
+// they hold the role for some subject.
+func (p *Policy) holders(roles []string, typ string) int {
+	want := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		want[r] = true
+	}
+	held := make(map[string]bool)
+	for _, e := range p.Trust {
+		if want[e.Role] && (e.Type == "" || e.Type == typ) {
+			held[e.Key] = true
+		}
+	}
+	for _, d := range p.Deny {
+		if d.Type == "" || d.Type == typ {
+			delete(held, d.Key)
+		}
+	}
+	return len(held)
+}
+
+// issuerKeyProblem describes why key is not an <alg>:<base64> public key
+// that CATF can verify against, or returns "".
+func issuerKeyProblem(key string) string {
+	_, _, err := keys.ParsePublicKey(key)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, keys.ErrKeyEncoding):
+		return "want <alg>:<base64>"
+	case errors.Is(err, keys.ErrKeyBase64):
+		return "invalid base64"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,106 @@
+package tpdl
+
+import (
+	"strings"
+	"testing"
+)
+
+const lintKey = "ed25519:A6EHv/POEL4dcN0Y50vAmWfk1jCbpQ1fHdyGZBJVMbg="
+
+func TestLint_ReportsLikelyMistakes(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Owner: registry
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ` + lintKey + `
+Role: buyer
+
+Key: ` + lintKey + `
+Role: buyer
+
+Key: ed25519:NOT_A_KEY
+Role: seller
+
+RULES
+Require:
+  Type: approval
+  Role: byuer
+  Quorum: 1
+
+Require:
+  Type: approval
+  Role: buyer
+  Quorum: 2
+
+Supersedes:
+  Allowed-By: buyer, editor
+-----END XDAO TRUST POLICY-----
+`
+	warnings, err := Lint([]byte(policyText))
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	want := []struct {
+		id   string
+		line int
+	}{
+		{"TPDL-LINT-001", 3},
+		{"TPDL-LINT-102", 11},
+		{"TPDL-LINT-101", 14},
+		{"TPDL-LINT-201", 20},
+		{"TPDL-LINT-202", 26},
+		{"TPDL-LINT-301", 29},
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("expected %d warnings, got %v", len(want), warnings)
+	}
+	for i, w := range want {
+		if warnings[i].ID != w.id || warnings[i].Line != w.line {
+			t.Fatalf("warning %d: got %s, want %s at line %d", i, warnings[i], w.id, w.line)
+		}
+	}
+}
+
+func TestLint_CleanPolicyAndDelegation(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ` + lintKey + `
+Role: governor
+
+RULES
+Require:
+  Type: approval
+  Role: governor
+  Quorum: 2
+
+Delegation:
+  Max-Depth: 1
+
+Policy-Supersedes:
+  Allowed-By: governor
+  Quorum: 1
+-----END XDAO TRUST POLICY-----
+`
+	// Delegates may make up the approval quorum, so it is not reported.
+	warnings, err := Lint([]byte(policyText))
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v, %v", warnings, err)
+	}
+
+	raised := strings.Replace(policyText, "  Quorum: 1\n-----END", "  Quorum: 3\n-----END", 1)
+	warnings, err = Lint([]byte(raised))
+	if err != nil || len(warnings) != 1 || warnings[0].ID != "TPDL-LINT-304" || warnings[0].Line != 19 {
+		t.Fatalf("expected TPDL-LINT-304 at line 19, got %v, %v", warnings, err)
+	}
+
+	if _, err := Lint([]byte(strings.Replace(policyText, "Version: 1", "Version: 2", 1))); RuleID(err) != "TPDL-VAL-004" {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package tpdl
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"xdao.co/catf/cidutil"
+)
+
+// Render produces canonical TPDL bytes from a Policy.
+//
+// Canonical TPDL (ReferenceDesign.md §16.12) has:
+//   - META pairs sorted by key
+//   - TRUST entries sorted by Key, Role, Type, Subject, then Deny entries
+//     sorted by Key, Type; each entry followed by one blank line
+//   - Require blocks sorted by Type, roles and Quorum, with fields in the order
+//     Type, Role (or Any-Of, when it lists more than one role), Quorum and an
+//     explicit Quorum, followed by the Supersedes, Delegation, Key-Recovery and
+//     Policy-Supersedes blocks when present
+//   - two-space block indentation, ", "-separated role lists, and exactly one
+//     trailing newline
+//
+// Duplicate entries and blocks are rendered once. The rendered bytes are
+// parsed before they are returned, so Render never emits a policy that Parse
+// would reject.
+func Render(p *Policy) ([]byte, error) {
+	if p == nil {
+		return nil, newError(KindRender, "TPDL-RENDER-001", 0, "nil policy")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("-----BEGIN XDAO TRUST POLICY-----\n")
+
+	sb.WriteString("META\n")
+	keys := make([]string, 0, len(p.Meta))
+	for k := range p.Meta {
+		if k == "" || strings.Contains(k, ": ") {
+			return nil, newError(KindRender, "TPDL-RENDER-001", 0, "invalid META key")
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := checkValue("META "+k, p.Meta[k]); err != nil {
+			return nil, err
+		}
+		sb.WriteString(k + ": " + p.Meta[k] + "\n")
+	}
+	sb.WriteString("\nTRUST\n")
+
+	trust := append([]TrustEntry(nil), p.Trust...)
+	sort.Slice(trust, func(i, j int) bool { return trustLess(trust[i], trust[j]) })
+	for i, e := range trust {
+		if i > 0 && e == trust[i-1] {
+			continue
+		}
+		for _, f := range []struct{ name, v string }{{"Key", e.Key}, {"Role", e.Role}} {
+			if err := checkValue(f.name, f.v); err != nil {
+				return nil, err
+			}
+		}
+		sb.WriteString("Key: " + e.Key + "\nRole: " + e.Role + "\n")
+		if e.Type != "" {
+			if err := checkValue("Type", e.Type); err != nil {
+				return nil, err
+			}
+			sb.WriteString("Type: " + e.Type + "\n")
+		}
+		if e.Subject != "" {
+			if err := checkValue("Subject", e.Subject); err != nil {
+				return nil, err
+			}
+			sb.WriteString("Subject: " + e.Subject + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	deny := append([]DenyEntry(nil), p.Deny...)
+	sort.Slice(deny, func(i, j int) bool {
+		if deny[i].Key != deny[j].Key {
+			return deny[i].Key < deny[j].Key
+		}
+		return deny[i].Type < deny[j].Type
+	})
+	for i, d := range deny {
+		if i > 0 && d == deny[i-1] {
+			continue
+		}
+		if err := checkValue("Deny", d.Key); err != nil {
+			return nil, err
+		}
+		sb.WriteString("Deny: " + d.Key + "\n")
+		if d.Type != "" {
+			if err := checkValue("Type", d.Type); err != nil {
+				return nil, err
+			}
+			sb.WriteString("Type: " + d.Type + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("RULES\n")
+	// Normalize each rule (sorted, unique roles; a single Any-Of role is a
+	// Role) so that equal rules render identically and sort adjacently.
+	type require struct {
+		typ    string
+		roles  []string
+		anyOf  bool
+		quorum int
+	}
+	reqs := make([]require, 0, len(p.Rules))
+	for _, r := range p.Rules {
+		if err := checkValue("Require Type", r.Type); err != nil {
+			return nil, err
+		}
+		if r.Role != "" && len(r.AnyOf) > 0 {
+			return nil, newError(KindRender, "TPDL-RENDER-001", 0, "Require has both Role and Any-Of")
+		}
+		roles, err := roleSet("Require Role", r.Roles())
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, require{typ: r.Type, roles: roles, anyOf: len(roles) > 1, quorum: quorum(r)})
+	}
+	sort.Slice(reqs, func(i, j int) bool {
+		a, b := reqs[i], reqs[j]
+		if a.typ != b.typ {
+			return a.typ < b.typ
+		}
+		if la, lb := strings.Join(a.roles, "|"), strings.Join(b.roles, "|"); la != lb {
+			return la < lb
+		}
+		if a.anyOf != b.anyOf {
+			return !a.anyOf
+		}
+		return a.quorum < b.quorum
+	})
+	var blocks []string
+	for _, r := range reqs {
+		field := "Role"
+		if r.anyOf {
+			field = "Any-Of"
+		}
+		block := "Require:\n  Type: " + r.typ + "\n  " + field + ": " + strings.Join(r.roles, ", ") + "\n  Quorum: " + strconv.Itoa(r.quorum) + "\n"
+		if len(blocks) == 0 || blocks[len(blocks)-1] != block {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(p.SupersedesAllowedBy) > 0 {
+		roles, err := roleSet("Supersedes Allowed-By", p.SupersedesAllowedBy)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, "Supersedes:\n  Allowed-By: "+strings.Join(roles, ", ")+"\n")
+	}
+	if p.DelegationMaxDepth > 0 {
+		blocks = append(blocks, "Delegation:\n  Max-Depth: "+strconv.Itoa(p.DelegationMaxDepth)+"\n")
+	}
+	if len(p.KeyRecoveryAllowedBy) > 0 {
+		roles, err := roleSet("Key-Recovery Allowed-By", p.KeyRecoveryAllowedBy)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, "Key-Recovery:\n  Allowed-By: "+strings.Join(roles, ", ")+"\n")
+	}
+	if len(p.PolicySupersedesAllowedBy) > 0 {
+		roles, err := roleSet("Policy-Supersedes Allowed-By", p.PolicySupersedesAllowedBy)
+		if err != nil {
+			return nil, err
+		}
+		q := p.PolicySupersedesQuorum
+		if q < 1 {
+			q = 1
+		}
+		blocks = append(blocks, "Policy-Supersedes:\n  Allowed-By: "+strings.Join(roles, ", ")+"\n  Quorum: "+strconv.Itoa(q)+"\n")
+	}
+	sb.WriteString(strings.Join(blocks, "\n"))
+	sb.WriteString("-----END XDAO TRUST POLICY-----\n")
+
+	out := []byte(sb.String())
+	if _, err := Parse(out); err != nil {
+		return nil, newError(KindRender, "TPDL-RENDER-001", 0, fmt.Sprintf("rendered policy is invalid: %v", err))
+	}
+	return out, nil
+}
+
+// CanonicalizeTPDL is the mandatory canonicalization choke point for TPDL.
+//
+// A policy's CID identifies it in CROF evidence (Trust-Policy-CID), so it MUST
+// be canonical before CID derivation. This function rejects any input that
+// does not parse or that differs from Render of its parsed form; the error
+// (TPDL-CANON-001) carries the first line that differs.
+func CanonicalizeTPDL(input []byte) ([]byte, error) {
+	p, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return requireCanonical(p, input)
+}
+
+// requireCanonical returns the rendering of p, the parse of input, and
+// reports TPDL-CANON-001 unless it equals input.
+func requireCanonical(p *Policy, input []byte) ([]byte, error) {
+	canon, err := Render(p)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(canon, input) {
+		return nil, newError(KindCanonical, "TPDL-CANON-001", firstDiffLine(input, canon), "policy is not in canonical form")
+	}
+	return canon, nil
+}
+
+// CID returns an IPFS-compatible CIDv1 (raw + sha2-256) for TPDL bytes.
+//
+// The policy must be canonical (see CanonicalizeTPDL); otherwise CID fails, so
+// semantically identical policies cannot be given different CIDs.
+func CID(policyBytes []byte) (string, error) {
+	canon, err := CanonicalizeTPDL(policyBytes)
+	if err != nil {
+		return "", err
+	}
+	return cidutil.CIDv1RawSHA256(canon), nil
+}
+
+func trustLess(a, b TrustEntry) bool {
+	if a.Key != b.Key {
+		return a.Key < b.Key
+	}
+	if a.Role != b.Role {
+		return a.Role < b.Role
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Subject < b.Subject
+}
+
+func quorum(r Rule) int {
+	if r.Quorum < 1 {
+		return 1
+	}
+	return r.Quorum
+}
+
+// checkValue rejects values that cannot be rendered as a single TPDL line.
+func checkValue(field, v string) error {
+	if v == "" || strings.TrimSpace(v) != v || strings.ContainsAny(v, "\r\n") {
+		return newError(KindRender, "TPDL-RENDER-001", 0, "invalid "+field+" value")
+	}
+	return nil
+}
+
+// roleSet returns roles sorted and de-duplicated, rejecting roles that cannot
+// appear in a ", "-separated list.
+func roleSet(field string, roles []string) ([]string, error) {
+	sorted := append([]string(nil), roles...)
+	sort.Strings(sorted)
+	out := make([]string, 0, len(sorted))
+	for i, r := range sorted {
+		if err := checkValue(field, r); err != nil {
+			return nil, err
+		}
+		if strings.Contains(r, ",") {
+			return nil, newError(KindRender, "TPDL-RENDER-001", 0, "invalid "+field+" value")
+		}
+		if i == 0 || r != sorted[i-1] {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// firstDiffLine returns the 1-based line at which a and b first differ.
+func firstDiffLine(a, b []byte) int {
+	la := strings.Split(string(a), "\n")
+	lb := strings.Split(string(b), "\n")
+	for i := range la {
+		if i >= len(lb) || la[i] != lb[i] {
+			return i + 1
+		}
+	}
+	return len(la)
+}
@@ -0,0 +1,155 @@
+package tpdl
+
+import (
+	"testing"
+)
+
+const messyTPDL = `-----BEGIN XDAO TRUST POLICY-----
+META
+Version: 1
+Spec: xdao-tpdl-2
+
+TRUST
+Key: ed25519:K2
+Role: seller
+
+Deny: ed25519:K9
+
+Key: ed25519:K1
+Role: inspector
+Type: approval
+
+Key: ed25519:K1
+Role: buyer
+
+RULES
+Key-Recovery:
+  Allowed-By: seller, buyer
+Require:
+	Role: seller
+	Type: approval
+Require:
+  Type: approval
+  Any-Of: seller, buyer
+  Quorum: 2
+Supersedes:
+  Allowed-By: seller
+-----END XDAO TRUST POLICY-----`
+
+const canonicalMessyTPDL = `-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ed25519:K1
+Role: buyer
+
+Key: ed25519:K1
+Role: inspector
+Type: approval
+
+Key: ed25519:K2
+Role: seller
+
+Deny: ed25519:K9
+
+RULES
+Require:
+  Type: approval
+  Any-Of: buyer, seller
+  Quorum: 2
+
+Require:
+  Type: approval
+  Role: seller
+  Quorum: 1
+
+Supersedes:
+  Allowed-By: seller
+
+Key-Recovery:
+  Allowed-By: buyer, seller
+-----END XDAO TRUST POLICY-----
+`
+
+func TestRender_CanonicalForm(t *testing.T) {
+	p, err := Parse([]byte(messyTPDL))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	out, err := Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if string(out) != canonicalMessyTPDL {
+		t.Fatalf("unexpected canonical form:\n%s", out)
+	}
+
+	// Rendering is idempotent and its output passes the canonical check.
+	canon, err := CanonicalizeTPDL(out)
+	if err != nil {
+		t.Fatalf("CanonicalizeTPDL(Render): %v", err)
+	}
+	if string(canon) != string(out) {
+		t.Fatalf("canonical bytes mismatch")
+	}
+}
+
+func TestRender_RejectsInvalidPolicy(t *testing.T) {
+	if _, err := Render(nil); RuleID(err) != "TPDL-RENDER-001" {
+		t.Fatalf("expected TPDL-RENDER-001 for nil policy, got %v", err)
+	}
+	p := &Policy{
+		Meta:  map[string]string{"Spec": SpecV1, "Version": "1"},
+		Rules: []Rule{{Type: "approval", AnyOf: []string{"buyer", "seller"}}},
+	}
+	if _, err := Render(p); !IsKind(err, KindRender) {
+		t.Fatalf("expected Any-Of under xdao-tpdl-1 to fail rendering, got %v", err)
+	}
+	p.Rules = []Rule{{Type: "approval", Role: "buyer\nRole: seller"}}
+	if _, err := Render(p); RuleID(err) != "TPDL-RENDER-001" {
+		t.Fatalf("expected multi-line value to be rejected, got %v", err)
+	}
+}
+
+func TestCanonicalizeTPDL_RejectsNonCanonicalInput(t *testing.T) {
+	_, err := CanonicalizeTPDL([]byte(messyTPDL))
+	if RuleID(err) != "TPDL-CANON-001" || !IsKind(err, KindCanonical) {
+		t.Fatalf("expected TPDL-CANON-001, got %v", err)
+	}
+	if Line(err) != 3 {
+		t.Fatalf("expected first differing line 3, got %d", Line(err))
+	}
+
+	noNewline := canonicalMessyTPDL[:len(canonicalMessyTPDL)-1]
+	if _, err := CanonicalizeTPDL([]byte(noNewline)); RuleID(err) != "TPDL-CANON-001" {
+		t.Fatalf("expected missing trailing newline to be rejected, got %v", err)
+	}
+}
+
+func TestCID_RequiresCanonicalPolicy(t *testing.T) {
+	if _, err := CID([]byte(messyTPDL)); err == nil {
+		t.Fatalf("expected CID to refuse non-canonical policy")
+	}
+	cid, err := CID([]byte(canonicalMessyTPDL))
+	if err != nil {
+		t.Fatalf("CID: %v", err)
+	}
+	if cid == "" {
+		t.Fatalf("empty CID")
+	}
+
+	// Two spellings of the same policy share one canonical form and CID.
+	p, err := Parse([]byte(messyTPDL))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	out, err := Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got, err := CID(out); err != nil || got != cid {
+		t.Fatalf("CID(Render(messy)) = %q, %v; want %q", got, err, cid)
+	}
+}
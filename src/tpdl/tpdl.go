@@ -1,9 +1,14 @@
 // Package tpdl implements parsing for the Trust Policy Domain Language (TPDL).
+//
+// Two spec versions are accepted. xdao-tpdl-1 is the original language.
+// xdao-tpdl-2 is a superset that adds Deny entries and Type/Subject-scoped
+// trust entries in TRUST, Any-Of role sets in Require blocks and the
+// Policy-Supersedes block in RULES. A v1 document that uses a v2 feature is
+// rejected.
 package tpdl
 
 import (
 	"bytes"
-	"errors"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,32 +16,91 @@ import (
 	"xdao.co/catf/compliance"
 )
 
+// Spec versions accepted in META Spec.
+const (
+	SpecV1 = "xdao-tpdl-1"
+	SpecV2 = "xdao-tpdl-2"
+)
+
 type Policy struct {
 	Meta  map[string]string
 	Trust []TrustEntry
 	Rules []Rule
 
+	// Deny lists keys that are never trusted, optionally only for one claim
+	// type. Deny entries override Trust entries, delegation and key rotation.
+	// xdao-tpdl-2 only.
+	Deny []DenyEntry
+
 	// SupersedesAllowedBy restricts which trusted roles may issue supersession attestations.
 	// When empty, supersession attestations are not additionally restricted by policy.
 	SupersedesAllowedBy []string
+
+	// KeyRecoveryAllowedBy lists the roles whose keys may rotate or revoke
+	// other issuer keys (Key-Recovery block, xdao-tpdl-2). Keys may always
+	// rotate or revoke themselves.
+	KeyRecoveryAllowedBy []string
+
+	// DelegationMaxDepth bounds delegation chains: a delegate may be at most
+	// this many delegation attestations away from a key listed in TRUST.
+	// Zero (no Delegation block, xdao-tpdl-2) disables delegation entirely.
+	DelegationMaxDepth int
+
+	// PolicySupersedesAllowedBy lists the roles whose keys may authorize a
+	// successor to this policy with policy-supersedes attestations, and
+	// PolicySupersedesQuorum how many distinct such keys must agree. When
+	// empty (no Policy-Supersedes block) the policy cannot be superseded.
+	// xdao-tpdl-2 only.
+	PolicySupersedesAllowedBy []string
+	PolicySupersedesQuorum    int
 }
 
 type TrustEntry struct {
 	Key  string
 	Role string
+
+	// Type and Subject, when set, restrict the role to attestations with this
+	// claim type and/or subject CID. xdao-tpdl-2 only.
+	Type    string
+	Subject string
+}
+
+// DenyEntry excludes a key's signatures; an empty Type denies every claim type.
+type DenyEntry struct {
+	Key  string
+	Type string
 }
 
 type Rule struct {
 	Type   string
 	Role   string
 	Quorum int
+
+	// AnyOf, when set, replaces Role with a set of roles any of which
+	// satisfies the rule (sorted, unique). xdao-tpdl-2 only.
+	AnyOf []string
+}
+
+// Roles returns the roles that satisfy the rule: AnyOf when set, else Role.
+func (r Rule) Roles() []string {
+	if len(r.AnyOf) > 0 {
+		return r.AnyOf
+	}
+	return []string{r.Role}
+}
+
+// RoleLabel identifies the rule's roles in evidence: Role, or the AnyOf
+// roles joined with "|".
+func (r Rule) RoleLabel() string {
+	return strings.Join(r.Roles(), "|")
 }
 
 // ParseWithCompliance parses a TPDL policy and optionally enforces additional
 // compliance-mode constraints.
 //
 // In compliance.Strict mode, this enforces "no defaults": every Require block
-// must include an explicit Quorum field.
+// must include an explicit Quorum field. The policy must also be canonical
+// (TPDL-CANON-001 otherwise), so it has a policy CID (see CID).
 func ParseWithCompliance(data []byte, mode compliance.ComplianceMode) (*Policy, error) {
 	p, err := Parse(data)
 	if err != nil {
@@ -46,6 +110,11 @@ func ParseWithCompliance(data []byte, mode compliance.ComplianceMode) (*Policy,
 		if err := enforceStrictTPDL(data); err != nil {
 			return nil, err
 		}
+		// Strict mode binds a policy by its policy CID, which only canonical
+		// bytes have (see CID).
+		if _, err := requireCanonical(p, data); err != nil {
+			return nil, err
+		}
 	}
 	return p, nil
 }
@@ -55,6 +124,16 @@ func ParseStrict(data []byte) (*Policy, error) {
 	return ParseWithCompliance(data, compliance.Strict)
 }
 
+// isBlockHeader reports whether the policy line l starts a RULES block or a
+// section, which ends the block before it.
+func isBlockHeader(l string) bool {
+	switch l {
+	case "Require:", "Supersedes:", "Delegation:", "Key-Recovery:", "Policy-Supersedes:", "META", "TRUST", "RULES":
+		return true
+	}
+	return false
+}
+
 func enforceStrictTPDL(data []byte) error {
 	lines := strings.Split(string(data), "\n")
 	if len(lines) > 0 && lines[len(lines)-1] == "" {
@@ -75,7 +154,7 @@ func enforceStrictTPDL(data []byte) error {
 			if l == "" {
 				break
 			}
-			if l == "Require:" || l == "Supersedes:" || l == "META" || l == "TRUST" || l == "RULES" || strings.HasPrefix(l, "-----END ") {
+			if isBlockHeader(l) || strings.HasPrefix(l, "-----END ") {
 				break
 			}
 			l = stripIndent(l)
@@ -85,23 +164,64 @@ func enforceStrictTPDL(data []byte) error {
 			}
 		}
 		if !hasQuorum {
-			return errors.New("strict mode: Require block missing Quorum")
+			return newError(KindValidation, "TPDL-VAL-205", i+1, "strict mode: Require block missing Quorum")
 		}
 	}
 	return nil
 }
 
 // Parse parses a TPDL policy from bytes.
+//
+// Failures are reported as *Error with a stable RuleID and, where the failure
+// is tied to a line, its 1-based line number.
 func Parse(data []byte) (*Policy, error) {
-	if bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
-		return nil, errors.New("BOM not allowed")
+	p, _, err := parse(data)
+	return p, err
+}
+
+// positions records the 1-based lines of a parsed policy's entries, so that
+// Lint can attribute findings to lines.
+type positions struct {
+	meta  map[string]int // META key (and "META" itself) -> line
+	trust []int          // line of each Policy.Trust entry's Key
+	deny  []int          // line of each Policy.Deny entry
+	rules []rulePosition // one per Policy.Rules entry
+
+	// allowedBy maps a block header ("Supersedes:", "Key-Recovery:",
+	// "Policy-Supersedes:") to the first Allowed-By line naming each role.
+	allowedBy map[string]map[string]int
+
+	policySupersedes int // Policy-Supersedes header line
+}
+
+type rulePosition struct {
+	block, roles, quorum int // Require header, Role/Any-Of and Quorum lines
+}
+
+// allowed records the line of each role in an Allowed-By list.
+func (pos *positions) allowed(block string, line int, roles map[string]bool) {
+	m := pos.allowedBy[block]
+	if m == nil {
+		m = make(map[string]int)
+		pos.allowedBy[block] = m
 	}
-	if bytes.Contains(data, []byte("\r")) {
-		return nil, errors.New("CR line endings not allowed")
+	for r := range roles {
+		if _, ok := m[r]; !ok {
+			m[r] = line
+		}
+	}
+}
+
+func parse(data []byte) (*Policy, *positions, error) {
+	if bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
+		return nil, nil, newError(KindParse, "TPDL-STR-001", 1, "BOM not allowed")
 	}
-	for _, line := range bytes.Split(data, []byte("\n")) {
+	for n, line := range bytes.Split(data, []byte("\n")) {
+		if bytes.Contains(line, []byte("\r")) {
+			return nil, nil, newError(KindParse, "TPDL-STR-002", n+1, "CR line endings not allowed")
+		}
 		if len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
-			return nil, errors.New("trailing whitespace forbidden")
+			return nil, nil, newError(KindParse, "TPDL-STR-003", n+1, "trailing whitespace forbidden")
 		}
 	}
 
@@ -111,19 +231,35 @@ func Parse(data []byte) (*Policy, error) {
 		lines = lines[:len(lines)-1]
 	}
 	if len(lines) < 2 {
-		return nil, errors.New("TPDL too short")
+		return nil, nil, newError(KindParse, "TPDL-STR-010", 1, "TPDL too short")
 	}
 	if lines[0] != "-----BEGIN XDAO TRUST POLICY-----" {
-		return nil, errors.New("missing TPDL preamble")
+		return nil, nil, newError(KindParse, "TPDL-STR-010", 1, "missing TPDL preamble")
 	}
 	if lines[len(lines)-1] != "-----END XDAO TRUST POLICY-----" {
-		return nil, errors.New("missing TPDL postamble")
+		return nil, nil, newError(KindParse, "TPDL-STR-011", len(lines), "missing TPDL postamble")
 	}
 
 	meta := make(map[string]string)
+	metaLine := make(map[string]int) // META key (and "META" itself) -> line
+	pos := &positions{meta: metaLine, allowedBy: make(map[string]map[string]int)}
 	var trust []TrustEntry
+	var deny []DenyEntry
 	var rules []Rule
 	allowedBy := make(map[string]bool)
+	delegationDepth := 0
+	recoveryBy := make(map[string]bool)
+	policyBy := make(map[string]bool)
+	policyQuorum := 0
+
+	// v2Line and v2Feature record the first xdao-tpdl-2 feature, which an
+	// xdao-tpdl-1 policy must not use.
+	v2Line, v2Feature := 0, ""
+	useV2 := func(i int, feature string) {
+		if v2Line == 0 {
+			v2Line, v2Feature = i+1, feature
+		}
+	}
 
 	stripIndent := func(s string) string {
 		return strings.TrimLeft(s, " \t")
@@ -142,54 +278,94 @@ func Parse(data []byte) (*Policy, error) {
 
 		// Section headers must appear in fixed order.
 		if line == "META" || line == "TRUST" || line == "RULES" {
-			if currSection != "" {
-				// ok
-			}
 			sectionIndex++
 			if sectionIndex >= len(sectionOrder) || sectionOrder[sectionIndex] != line {
-				return nil, errors.New("sections missing or out of order")
+				return nil, nil, newError(KindParse, "TPDL-STR-020", i+1, "sections missing or out of order")
+			}
+			if line == "META" {
+				metaLine["META"] = i + 1
 			}
 			currSection = line
 			i++
 			continue
 		}
 		if currSection == "" {
-			return nil, errors.New("unexpected content before first section")
+			return nil, nil, newError(KindParse, "TPDL-STR-020", i+1, "unexpected content before first section")
 		}
 
 		switch currSection {
 		case "META":
 			if !strings.Contains(line, ": ") {
-				return nil, errors.New("invalid META key-value")
+				return nil, nil, newError(KindParse, "TPDL-STR-030", i+1, "invalid META key-value")
 			}
 			kv := strings.SplitN(line, ": ", 2)
 			meta[kv[0]] = kv[1]
+			metaLine[kv[0]] = i + 1
 			i++
 		case "TRUST":
+			if strings.HasPrefix(line, "Deny: ") {
+				useV2(i, "Deny")
+				start := i
+				d := DenyEntry{Key: strings.TrimPrefix(line, "Deny: ")}
+				if d.Key == "" {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-101", i+1, "empty Deny key")
+				}
+				i++
+				if i < len(lines)-1 && strings.HasPrefix(lines[i], "Type: ") {
+					d.Type = strings.TrimPrefix(lines[i], "Type: ")
+					if d.Type == "" {
+						return nil, nil, newError(KindValidation, "TPDL-VAL-103", i+1, "empty Deny Type")
+					}
+					i++
+				}
+				deny = append(deny, d)
+				pos.deny = append(pos.deny, start+1)
+				continue
+			}
 			if !strings.HasPrefix(line, "Key: ") {
-				return nil, errors.New("expected Key in TRUST")
+				return nil, nil, newError(KindParse, "TPDL-STR-040", i+1, "expected Key in TRUST")
 			}
 			key := strings.TrimPrefix(line, "Key: ")
 			if key == "" {
-				return nil, errors.New("empty Key")
+				return nil, nil, newError(KindValidation, "TPDL-VAL-101", i+1, "empty Key")
 			}
 			if i+1 >= len(lines)-1 {
-				return nil, errors.New("expected Role after Key")
+				return nil, nil, newError(KindParse, "TPDL-STR-041", i+2, "expected Role after Key")
 			}
 			roleLine := lines[i+1]
 			if !strings.HasPrefix(roleLine, "Role: ") {
-				return nil, errors.New("expected Role after Key")
+				return nil, nil, newError(KindParse, "TPDL-STR-041", i+2, "expected Role after Key")
 			}
 			role := strings.TrimPrefix(roleLine, "Role: ")
 			if role == "" {
-				return nil, errors.New("empty Role")
+				return nil, nil, newError(KindValidation, "TPDL-VAL-102", i+2, "empty Role")
 			}
-			trust = append(trust, TrustEntry{Key: key, Role: role})
+			e := TrustEntry{Key: key, Role: role}
+			pos.trust = append(pos.trust, i+1)
 			i += 2
+			if i < len(lines)-1 && strings.HasPrefix(lines[i], "Type: ") {
+				useV2(i, "scoped trust")
+				e.Type = strings.TrimPrefix(lines[i], "Type: ")
+				if e.Type == "" {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-103", i+1, "empty trust Type")
+				}
+				i++
+			}
+			if i < len(lines)-1 && strings.HasPrefix(lines[i], "Subject: ") {
+				useV2(i, "scoped trust")
+				e.Subject = strings.TrimPrefix(lines[i], "Subject: ")
+				if e.Subject == "" {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-104", i+1, "empty trust Subject")
+				}
+				i++
+			}
+			trust = append(trust, e)
 		case "RULES":
 			if line == "Require:" {
+				start := i
 				var r Rule
 				r.Quorum = 1
+				rp := rulePosition{block: i + 1}
 				i++
 				for i < len(lines)-1 {
 					l := lines[i]
@@ -198,7 +374,7 @@ func Parse(data []byte) (*Policy, error) {
 						break
 					}
 					// New block or section header.
-					if l == "Require:" || l == "Supersedes:" || l == "META" || l == "TRUST" || l == "RULES" {
+					if isBlockHeader(l) {
 						break
 					}
 					l = stripIndent(l)
@@ -207,22 +383,48 @@ func Parse(data []byte) (*Policy, error) {
 						r.Type = strings.TrimPrefix(l, "Type: ")
 					case strings.HasPrefix(l, "Role: "):
 						r.Role = strings.TrimPrefix(l, "Role: ")
+						rp.roles = i + 1
+					case strings.HasPrefix(l, "Any-Of: "):
+						useV2(i, "Any-Of")
+						rp.roles = i + 1
+						set := make(map[string]bool)
+						for _, part := range strings.Split(strings.TrimPrefix(l, "Any-Of: "), ",") {
+							if role := strings.TrimSpace(part); role != "" {
+								set[role] = true
+							}
+						}
+						if len(set) == 0 {
+							return nil, nil, newError(KindValidation, "TPDL-VAL-203", i+1, "Any-Of must not be empty")
+						}
+						r.AnyOf = r.AnyOf[:0]
+						for role := range set {
+							if strings.Contains(role, "|") {
+								return nil, nil, newError(KindValidation, "TPDL-VAL-203", i+1, "invalid Any-Of role")
+							}
+							r.AnyOf = append(r.AnyOf, role)
+						}
+						sort.Strings(r.AnyOf)
 					case strings.HasPrefix(l, "Quorum: "):
 						qStr := strings.TrimPrefix(l, "Quorum: ")
 						q, qErr := strconv.Atoi(qStr)
 						if qErr != nil || q < 1 {
-							return nil, errors.New("invalid Quorum")
+							return nil, nil, newError(KindValidation, "TPDL-VAL-202", i+1, "invalid Quorum")
 						}
 						r.Quorum = q
+						rp.quorum = i + 1
 					default:
-						return nil, errors.New("unknown field in Require block")
+						return nil, nil, newError(KindParse, "TPDL-STR-051", i+1, "unknown field in Require block")
 					}
 					i++
 				}
-				if r.Type == "" || r.Role == "" {
-					return nil, errors.New("Require block missing Type or Role")
+				if r.Role != "" && len(r.AnyOf) > 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-204", start+1, "Require block has both Role and Any-Of")
+				}
+				if r.Type == "" || (r.Role == "" && len(r.AnyOf) == 0) {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-201", start+1, "Require block missing Type or Role")
 				}
 				rules = append(rules, r)
+				pos.rules = append(pos.rules, rp)
 				continue
 			}
 			if line == "Supersedes:" {
@@ -233,7 +435,7 @@ func Parse(data []byte) (*Policy, error) {
 						i++
 						break
 					}
-					if l == "Require:" || l == "Supersedes:" || l == "META" || l == "TRUST" || l == "RULES" {
+					if isBlockHeader(l) {
 						break
 					}
 					l = stripIndent(l)
@@ -246,24 +448,133 @@ func Parse(data []byte) (*Policy, error) {
 							}
 							allowedBy[role] = true
 						}
+						pos.allowed("Supersedes:", i+1, allowedBy)
 						if len(list) == 0 {
-							return nil, errors.New("Allowed-By must not be empty")
+							return nil, nil, newError(KindValidation, "TPDL-VAL-211", i+1, "Allowed-By must not be empty")
 						}
 					} else {
-						return nil, errors.New("unknown field in Supersedes block")
+						return nil, nil, newError(KindParse, "TPDL-STR-051", i+1, "unknown field in Supersedes block")
+					}
+					i++
+				}
+				continue
+			}
+			if line == "Delegation:" {
+				start := i
+				useV2(i, "Delegation")
+				if delegationDepth != 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-221", i+1, "duplicate Delegation block")
+				}
+				i++
+				for i < len(lines)-1 {
+					l := lines[i]
+					if l == "" {
+						i++
+						break
+					}
+					if isBlockHeader(l) {
+						break
+					}
+					l = stripIndent(l)
+					if !strings.HasPrefix(l, "Max-Depth: ") {
+						return nil, nil, newError(KindParse, "TPDL-STR-051", i+1, "unknown field in Delegation block")
+					}
+					d, dErr := strconv.Atoi(strings.TrimPrefix(l, "Max-Depth: "))
+					if dErr != nil || d < 1 {
+						return nil, nil, newError(KindValidation, "TPDL-VAL-222", i+1, "invalid Max-Depth")
 					}
+					delegationDepth = d
 					i++
 				}
+				if delegationDepth == 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-223", start+1, "Delegation block missing Max-Depth")
+				}
 				continue
 			}
-			return nil, errors.New("unexpected content in RULES")
+			if line == "Key-Recovery:" {
+				start := i
+				useV2(i, "Key-Recovery")
+				if len(recoveryBy) != 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-232", i+1, "duplicate Key-Recovery block")
+				}
+				i++
+				for i < len(lines)-1 {
+					l := lines[i]
+					if l == "" {
+						i++
+						break
+					}
+					if isBlockHeader(l) {
+						break
+					}
+					l = stripIndent(l)
+					if !strings.HasPrefix(l, "Allowed-By: ") {
+						return nil, nil, newError(KindParse, "TPDL-STR-051", i+1, "unknown field in Key-Recovery block")
+					}
+					for _, part := range strings.Split(strings.TrimPrefix(l, "Allowed-By: "), ",") {
+						if role := strings.TrimSpace(part); role != "" {
+							recoveryBy[role] = true
+						}
+					}
+					pos.allowed("Key-Recovery:", i+1, recoveryBy)
+					i++
+				}
+				if len(recoveryBy) == 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-231", start+1, "Key-Recovery block missing Allowed-By")
+				}
+				continue
+			}
+			if line == "Policy-Supersedes:" {
+				start := i
+				useV2(i, "Policy-Supersedes")
+				pos.policySupersedes = i + 1
+				if policyQuorum != 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-243", i+1, "duplicate Policy-Supersedes block")
+				}
+				policyQuorum = 1
+				i++
+				for i < len(lines)-1 {
+					l := lines[i]
+					if l == "" {
+						i++
+						break
+					}
+					if isBlockHeader(l) {
+						break
+					}
+					l = stripIndent(l)
+					switch {
+					case strings.HasPrefix(l, "Allowed-By: "):
+						for _, part := range strings.Split(strings.TrimPrefix(l, "Allowed-By: "), ",") {
+							if role := strings.TrimSpace(part); role != "" {
+								policyBy[role] = true
+							}
+						}
+						pos.allowed("Policy-Supersedes:", i+1, policyBy)
+					case strings.HasPrefix(l, "Quorum: "):
+						q, qErr := strconv.Atoi(strings.TrimPrefix(l, "Quorum: "))
+						if qErr != nil || q < 1 {
+							return nil, nil, newError(KindValidation, "TPDL-VAL-242", i+1, "invalid Policy-Supersedes Quorum")
+						}
+						policyQuorum = q
+					default:
+						return nil, nil, newError(KindParse, "TPDL-STR-051", i+1, "unknown field in Policy-Supersedes block")
+					}
+					i++
+				}
+				if len(policyBy) == 0 {
+					return nil, nil, newError(KindValidation, "TPDL-VAL-241", start+1, "Policy-Supersedes block missing Allowed-By")
+				}
+				continue
+			}
+			return nil, nil, newError(KindParse, "TPDL-STR-050", i+1, "unexpected content in RULES")
 		default:
-			return nil, errors.New("unknown section")
+			return nil, nil, newError(KindParse, "TPDL-STR-020", i+1, "unknown section")
 		}
 	}
 
 	if sectionIndex != len(sectionOrder)-1 {
-		return nil, errors.New("sections missing or out of order")
+		return nil, nil, newError(KindParse, "TPDL-STR-020", len(lines), "sections missing or out of order")
 	}
 
 	allowedList := make([]string, 0, len(allowedBy))
@@ -271,20 +582,33 @@ func Parse(data []byte) (*Policy, error) {
 		allowedList = append(allowedList, r)
 	}
 	sort.Strings(allowedList)
+	recoveryList := make([]string, 0, len(recoveryBy))
+	for r := range recoveryBy {
+		recoveryList = append(recoveryList, r)
+	}
+	sort.Strings(recoveryList)
+	policyList := make([]string, 0, len(policyBy))
+	for r := range policyBy {
+		policyList = append(policyList, r)
+	}
+	sort.Strings(policyList)
 
 	// Spec-strict META validation (ReferenceDesign.md §16.4).
 	if meta["Spec"] == "" {
-		return nil, errors.New("missing META Spec")
+		return nil, nil, newError(KindValidation, "TPDL-VAL-001", metaLine["META"], "missing META Spec")
 	}
-	if meta["Spec"] != "xdao-tpdl-1" {
-		return nil, errors.New("unsupported policy Spec")
+	if meta["Spec"] != SpecV1 && meta["Spec"] != SpecV2 {
+		return nil, nil, newError(KindValidation, "TPDL-VAL-002", metaLine["Spec"], "unsupported policy Spec")
 	}
 	if meta["Version"] == "" {
-		return nil, errors.New("missing META Version")
+		return nil, nil, newError(KindValidation, "TPDL-VAL-003", metaLine["META"], "missing META Version")
 	}
 	if meta["Version"] != "1" {
-		return nil, errors.New("unsupported policy Version")
+		return nil, nil, newError(KindValidation, "TPDL-VAL-004", metaLine["Version"], "unsupported policy Version")
+	}
+	if meta["Spec"] == SpecV1 && v2Line > 0 {
+		return nil, nil, newError(KindValidation, "TPDL-VAL-005", v2Line, v2Feature+" requires Spec "+SpecV2)
 	}
 
-	return &Policy{Meta: meta, Trust: trust, Rules: rules, SupersedesAllowedBy: allowedList}, nil
+	return &Policy{Meta: meta, Trust: trust, Deny: deny, Rules: rules, SupersedesAllowedBy: allowedList, KeyRecoveryAllowedBy: recoveryList, DelegationMaxDepth: delegationDepth, PolicySupersedesAllowedBy: policyList, PolicySupersedesQuorum: policyQuorum}, pos, nil
 }
@@ -1,6 +1,8 @@
 package tpdl
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"xdao.co/catf/compliance"
@@ -81,10 +83,11 @@ func TestParseStrictTPDL_RequiresExplicitQuorum(t *testing.T) {
 }
 
 func TestParseStrictTPDL_AllowsExplicitQuorumOne(t *testing.T) {
+	// Strict mode also requires canonical bytes (sorted META, trailing newline).
 	policyText := `-----BEGIN XDAO TRUST POLICY-----
 META
-Version: 1
 Spec: xdao-tpdl-1
+Version: 1
 
 TRUST
 Key: ed25519:K1
@@ -95,7 +98,8 @@ Require:
   Type: authorship
   Role: author
   Quorum: 1
------END XDAO TRUST POLICY-----`
+-----END XDAO TRUST POLICY-----
+`
 
 	if _, err := ParseStrict([]byte(policyText)); err != nil {
 		t.Fatalf("expected strict parse ok, got %v", err)
@@ -228,3 +232,233 @@ Require:
 		t.Fatalf("expected strict parse error")
 	}
 }
+
+func TestParseTPDL_DelegationMaxDepth(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ed25519:K1
+Role: clerk
+
+RULES
+Require:
+  Type: approval
+  Role: clerk
+  Quorum: 1
+
+Delegation:
+  Max-Depth: 2
+-----END XDAO TRUST POLICY-----
+`
+
+	policy, err := ParseStrict([]byte(policyText))
+	if err != nil {
+		t.Fatalf("expected valid TPDL, got error: %v", err)
+	}
+	if policy.DelegationMaxDepth != 2 {
+		t.Fatalf("expected Max-Depth 2, got %d", policy.DelegationMaxDepth)
+	}
+	v1 := strings.Replace(policyText, "Spec: xdao-tpdl-2", "Spec: xdao-tpdl-1", 1)
+	if _, err := Parse([]byte(v1)); RuleID(err) != "TPDL-VAL-005" || Line(err) != 16 {
+		t.Fatalf("expected TPDL-VAL-005 at line 16 under xdao-tpdl-1, got %v", err)
+	}
+
+	policy, err = Parse([]byte(validTPDL))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if policy.DelegationMaxDepth != 0 {
+		t.Fatalf("expected delegation disabled by default, got %d", policy.DelegationMaxDepth)
+	}
+}
+
+func TestParseInvalidTPDL_Delegation(t *testing.T) {
+	head := "-----BEGIN XDAO TRUST POLICY-----\nMETA\nVersion: 1\nSpec: xdao-tpdl-2\n\nTRUST\nKey: ed25519:K1\nRole: clerk\n\nRULES\n"
+	tail := "-----END XDAO TRUST POLICY-----"
+	for name, block := range map[string]string{
+		"zero depth":    "Delegation:\n  Max-Depth: 0\n",
+		"non-numeric":   "Delegation:\n  Max-Depth: two\n",
+		"missing depth": "Delegation:\n\n",
+		"unknown field": "Delegation:\n  Max-Depth: 1\n  Roles: clerk\n",
+		"duplicate":     "Delegation:\n  Max-Depth: 1\nDelegation:\n  Max-Depth: 2\n",
+	} {
+		if _, err := Parse([]byte(head + block + tail)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseTPDL_KeyRecoveryAllowedBy(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Version: 1
+Spec: xdao-tpdl-2
+
+TRUST
+Key: ed25519:K1
+Role: custodian
+
+RULES
+Key-Recovery:
+  Allowed-By: custodian, registrar
+Delegation:
+  Max-Depth: 1
+-----END XDAO TRUST POLICY-----`
+
+	policy, err := Parse([]byte(policyText))
+	if err != nil {
+		t.Fatalf("expected valid TPDL, got error: %v", err)
+	}
+	if len(policy.KeyRecoveryAllowedBy) != 2 || policy.KeyRecoveryAllowedBy[0] != "custodian" || policy.KeyRecoveryAllowedBy[1] != "registrar" {
+		t.Fatalf("unexpected Key-Recovery roles %+v", policy.KeyRecoveryAllowedBy)
+	}
+	if policy.DelegationMaxDepth != 1 {
+		t.Fatalf("expected Delegation block after Key-Recovery, got depth %d", policy.DelegationMaxDepth)
+	}
+
+	bad := strings.Replace(policyText, "  Allowed-By: custodian, registrar\n", "  Roles: custodian\n", 1)
+	if _, err := Parse([]byte(bad)); err == nil {
+		t.Fatalf("expected unknown Key-Recovery field to be rejected")
+	}
+
+	dup := strings.Replace(policyText, "Delegation:\n", "Key-Recovery:\n  Allowed-By: custodian\nDelegation:\n", 1)
+	if _, err := Parse([]byte(dup)); RuleID(err) != "TPDL-VAL-232" {
+		t.Fatalf("expected duplicate Key-Recovery block to be rejected, got %v", err)
+	}
+	v1 := strings.Replace(policyText, "Spec: xdao-tpdl-2", "Spec: xdao-tpdl-1", 1)
+	if _, err := Parse([]byte(v1)); RuleID(err) != "TPDL-VAL-005" {
+		t.Fatalf("expected Key-Recovery to require xdao-tpdl-2, got %v", err)
+	}
+}
+
+func TestParseTPDL_PolicySupersedes(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Spec: xdao-tpdl-2
+Version: 1
+
+TRUST
+Key: ed25519:K1
+Role: governor
+
+RULES
+Policy-Supersedes:
+  Allowed-By: governor, steward
+  Quorum: 2
+-----END XDAO TRUST POLICY-----
+`
+
+	policy, err := Parse([]byte(policyText))
+	if err != nil {
+		t.Fatalf("expected valid TPDL, got error: %v", err)
+	}
+	if len(policy.PolicySupersedesAllowedBy) != 2 || policy.PolicySupersedesAllowedBy[0] != "governor" || policy.PolicySupersedesQuorum != 2 {
+		t.Fatalf("unexpected Policy-Supersedes %+v quorum %d", policy.PolicySupersedesAllowedBy, policy.PolicySupersedesQuorum)
+	}
+	out, err := Render(policy)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if string(out) != policyText {
+		t.Fatalf("unexpected canonical form:\n%s", out)
+	}
+
+	defaultQuorum := strings.Replace(policyText, "  Quorum: 2\n", "", 1)
+	if policy, err := Parse([]byte(defaultQuorum)); err != nil || policy.PolicySupersedesQuorum != 1 {
+		t.Fatalf("expected default Quorum 1, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		text   string
+		ruleID string
+		line   int
+	}{
+		{strings.Replace(policyText, "Spec: xdao-tpdl-2", "Spec: xdao-tpdl-1", 1), "TPDL-VAL-005", 11},
+		{strings.Replace(policyText, "  Allowed-By: governor, steward\n", "", 1), "TPDL-VAL-241", 11},
+		{strings.Replace(policyText, "Quorum: 2", "Quorum: 0", 1), "TPDL-VAL-242", 13},
+		{strings.Replace(policyText, "  Quorum: 2\n", "  Quorum: 2\nPolicy-Supersedes:\n  Allowed-By: governor\n", 1), "TPDL-VAL-243", 14},
+		{strings.Replace(policyText, "Quorum: 2", "Max-Depth: 2", 1), "TPDL-STR-051", 13},
+	} {
+		_, err := Parse([]byte(tc.text))
+		if RuleID(err) != tc.ruleID || Line(err) != tc.line {
+			t.Fatalf("expected %s at line %d, got %v", tc.ruleID, tc.line, err)
+		}
+	}
+}
+
+func TestParseTPDL_V2DenyScopedTrustAndAnyOf(t *testing.T) {
+	policyText := `-----BEGIN XDAO TRUST POLICY-----
+META
+Version: 1
+Spec: xdao-tpdl-2
+
+TRUST
+Key: ed25519:K1
+Role: buyer
+
+Key: ed25519:K2
+Role: inspector
+Type: approval
+Subject: bafy-deed
+
+Deny: ed25519:K3
+
+Deny: ed25519:K4
+Type: approval
+
+RULES
+Require:
+  Type: approval
+  Any-Of: seller, buyer
+  Quorum: 2
+-----END XDAO TRUST POLICY-----`
+
+	policy, err := Parse([]byte(policyText))
+	if err != nil {
+		t.Fatalf("expected valid TPDL, got error: %v", err)
+	}
+	if len(policy.Trust) != 2 || policy.Trust[1] != (TrustEntry{Key: "ed25519:K2", Role: "inspector", Type: "approval", Subject: "bafy-deed"}) {
+		t.Fatalf("unexpected trust entries %+v", policy.Trust)
+	}
+	if len(policy.Deny) != 2 || policy.Deny[0] != (DenyEntry{Key: "ed25519:K3"}) || policy.Deny[1] != (DenyEntry{Key: "ed25519:K4", Type: "approval"}) {
+		t.Fatalf("unexpected deny entries %+v", policy.Deny)
+	}
+	if len(policy.Rules) != 1 || policy.Rules[0].Role != "" || policy.Rules[0].RoleLabel() != "buyer|seller" || policy.Rules[0].Quorum != 2 {
+		t.Fatalf("unexpected rules %+v", policy.Rules)
+	}
+
+	// Every v2 feature is rejected under xdao-tpdl-1.
+	v1 := strings.Replace(policyText, "Spec: xdao-tpdl-2", "Spec: xdao-tpdl-1", 1)
+	for name, bad := range map[string]string{
+		"deny":   strings.Replace(strings.Replace(v1, "Type: approval\nSubject: bafy-deed\n", "", 1), "  Any-Of: seller, buyer\n", "  Role: buyer\n", 1),
+		"scoped": strings.Replace(strings.Replace(v1, "Deny: ed25519:K3\n\nDeny: ed25519:K4\nType: approval\n\n", "", 1), "  Any-Of: seller, buyer\n", "  Role: buyer\n", 1),
+		"any-of": strings.Replace(strings.Replace(v1, "Type: approval\nSubject: bafy-deed\n", "", 1), "Deny: ed25519:K3\n\nDeny: ed25519:K4\nType: approval\n\n", "", 1),
+	} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("%s: expected xdao-tpdl-1 to reject v2 feature", name)
+		}
+	}
+
+	both := strings.Replace(policyText, "  Any-Of: seller, buyer\n", "  Role: buyer\n  Any-Of: seller, buyer\n", 1)
+	if _, err := Parse([]byte(both)); err == nil {
+		t.Fatalf("expected Role with Any-Of to be rejected")
+	}
+}
+
+func TestParseInvalidTPDL_StructuredError(t *testing.T) {
+	policyText := "-----BEGIN XDAO TRUST POLICY-----\nMETA\nSpec: xdao-tpdl-1\nVersion: 1\n\nTRUST\nKey: ed25519:K1\nRole: buyer\n\nRULES\nRequire:\n  Type: approval\n  Role: buyer\n  Quorum: many\n-----END XDAO TRUST POLICY-----\n"
+	_, err := Parse([]byte(policyText))
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected *Error, got %T %v", err, err)
+	}
+	if e.Kind != KindValidation || e.RuleID != "TPDL-VAL-202" || e.Line != 14 {
+		t.Fatalf("unexpected error %+v", e)
+	}
+	if !IsKind(err, KindValidation) || err.Error() != "line 14: invalid Quorum" {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+}